@@ -0,0 +1,12 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_collection_items" "network" {
+  organization_id = data.terrakube_organization.org.id
+  collection_name = "network"
+}
+
+output "region" {
+  value = data.terrakube_collection_items.network.values["region"]
+}
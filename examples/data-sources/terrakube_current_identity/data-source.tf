@@ -0,0 +1,13 @@
+data "terrakube_current_identity" "current" {}
+
+check "production_identity" {
+  assert {
+    condition     = data.terrakube_current_identity.current.token_type == "team"
+    error_message = "Production must be applied with a team token."
+  }
+
+  assert {
+    condition     = contains(data.terrakube_current_identity.current.organizations[*].name, "production")
+    error_message = "The token can't read the production organization, check the provider endpoint."
+  }
+}
@@ -0,0 +1,13 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_job" "latest" {
+  organization_id = data.terrakube_organization.org.id
+  workspace_id    = terrakube_workspace_vcs.sample1.id
+}
+
+data "terrakube_job" "job" {
+  organization_id = data.terrakube_organization.org.id
+  id              = "1"
+}
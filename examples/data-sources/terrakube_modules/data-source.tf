@@ -0,0 +1,8 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_modules" "vpc" {
+  organization_id = data.terrakube_organization.org.id
+  source          = "https://github.com/terraform-aws-modules/terraform-aws-vpc.git"
+}
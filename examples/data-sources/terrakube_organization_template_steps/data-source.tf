@@ -0,0 +1,23 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_organization_template_steps" "deploy" {
+  organization_id = data.terrakube_organization.org.id
+  name            = "deploy"
+}
+
+resource "terrakube_workspace_webhook" "production" {
+  organization_id = data.terrakube_organization.org.id
+  workspace_id    = terrakube_workspace_vcs.production.id
+  event           = "PUSH"
+  branch          = ["main"]
+  template_id     = data.terrakube_organization_template_steps.deploy.id
+
+  lifecycle {
+    precondition {
+      condition     = contains([for step in data.terrakube_organization_template_steps.deploy.steps : step.type], "terraformApply")
+      error_message = "The deploy template must run terraformApply."
+    }
+  }
+}
@@ -0,0 +1,10 @@
+data "terrakube_raw_object" "webhook" {
+  type            = "workspace_webhook"
+  organization_id = "00000000-0000-0000-0000-000000000000"
+  workspace_id    = "00000000-0000-0000-0000-000000000000"
+  id              = "00000000-0000-0000-0000-000000000000"
+}
+
+output "webhook_json" {
+  value = jsondecode(data.terrakube_raw_object.webhook.json)
+}
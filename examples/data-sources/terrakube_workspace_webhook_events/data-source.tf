@@ -0,0 +1,17 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_workspaces" "all" {
+  organization_id = data.terrakube_organization.org.id
+}
+
+data "terrakube_workspace_webhook_events" "events" {
+  for_each        = data.terrakube_workspaces.all.by_name
+  organization_id = data.terrakube_organization.org.id
+  workspace_id    = each.value.id
+}
+
+output "webhook_events" {
+  value = { for name, webhook_events in data.terrakube_workspace_webhook_events.events : name => webhook_events.events }
+}
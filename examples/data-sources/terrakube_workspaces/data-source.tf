@@ -0,0 +1,17 @@
+data "terrakube_organization" "org" {
+  name = "simple"
+}
+
+data "terrakube_workspaces" "prod" {
+  organization_id = data.terrakube_organization.org.id
+  tag             = "prod"
+  name_prefix     = "app-"
+}
+
+resource "terrakube_collection_reference" "prod" {
+  for_each        = data.terrakube_workspaces.prod.by_name
+  organization_id = data.terrakube_organization.org.id
+  collection_id   = terrakube_collection.prod.id
+  workspace_id    = each.value.id
+  description     = "Shared production settings"
+}
@@ -0,0 +1,4 @@
+module "vpc" {
+  source  = provider::terrakube::module_source("simple", "vpc", "aws", "terrakube-reg.minikube.net")
+  version = "1.0.0"
+}
@@ -0,0 +1,3 @@
+output "workspace_url" {
+  value = provider::terrakube::workspace_url("https://terrakube-ui.minikube.net", data.terrakube_organization.org.id, terrakube_workspace_vcs.sample1.id)
+}
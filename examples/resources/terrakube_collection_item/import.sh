@@ -1,2 +1,3 @@
 # Organization Workspace Variable can be import with organization_id,collection_id,id
+# The API never returns the value of sensitive items, set it in the configuration generated with -generate-config-out.
 terraform import terrakube_workspace_variable.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
\ No newline at end of file
@@ -1,2 +1,3 @@
 # Organization VCS can be import with organization_id,id
+# The API never returns client_secret or private_key, set them in the configuration generated with -generate-config-out.
 terraform import terrakube_vcs.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
\ No newline at end of file
@@ -1,2 +1,5 @@
 # Organization Workspace Variable can be import with organization_id,workspace_id,id
-terraform import terrakube_workspace_variable.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
\ No newline at end of file
+# The API never returns the value of sensitive variables, set it in the configuration generated with -generate-config-out.
+terraform import terrakube_workspace_variable.example 00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000,00000000-0000-0000-0000-000000000000
+# or with organization_name/workspace_name/key, the ids are found with the API
+terraform import terrakube_workspace_variable.example simple/workspace1/TF_VAR_example
\ No newline at end of file
@@ -28,14 +28,14 @@ type OrganizationTagEntity struct {
 type TeamEntity struct {
 	ID               string `jsonapi:"primary,team"`
 	Name             string `jsonapi:"attr,name"`
-	ManageState      bool   `jsonapi:"attr,manageState"`
-	ManageWorkspace  bool   `jsonapi:"attr,manageWorkspace"`
-	ManageModule     bool   `jsonapi:"attr,manageModule"`
-	ManageProvider   bool   `jsonapi:"attr,manageProvider"`
-	ManageVcs        bool   `jsonapi:"attr,manageVcs"`
-	ManageTemplate   bool   `jsonapi:"attr,manageTemplate"`
-	ManageJob        bool   `jsonapi:"attr,manageJob"`
-	ManageCollection bool   `jsonapi:"attr,manageCollection"`
+	ManageState      *bool  `jsonapi:"attr,manageState,omitempty"`
+	ManageWorkspace  *bool  `jsonapi:"attr,manageWorkspace,omitempty"`
+	ManageModule     *bool  `jsonapi:"attr,manageModule,omitempty"`
+	ManageProvider   *bool  `jsonapi:"attr,manageProvider,omitempty"`
+	ManageVcs        *bool  `jsonapi:"attr,manageVcs,omitempty"`
+	ManageTemplate   *bool  `jsonapi:"attr,manageTemplate,omitempty"`
+	ManageJob        *bool  `jsonapi:"attr,manageJob,omitempty"`
+	ManageCollection *bool  `jsonapi:"attr,manageCollection,omitempty"`
 }
 
 type TeamTokenEntity struct {
@@ -58,9 +58,17 @@ type WorkspaceEntity struct {
 	TemplateId    string     `jsonapi:"attr,defaultTemplate"`
 	IaCType       string     `jsonapi:"attr,iacType"`
 	IaCVersion    string     `jsonapi:"attr,terraformVersion"`
-	ExecutionMode string     `jsonapi:"attr,executionMode"`
+	ExecutionMode string     `jsonapi:"attr,executionMode,omitempty"`
 	Deleted       bool       `jsonapi:"attr,deleted"`
+	LastJobDate   string     `jsonapi:"attr,lastJobDate,omitempty"`
 	Vcs           *VcsEntity `jsonapi:"relation,vcs,omitempty"`
+	Ssh           *SshEntity `jsonapi:"relation,ssh,omitempty"`
+}
+
+type HistoryEntity struct {
+	ID           string `jsonapi:"primary,history"`
+	Serial       int64  `jsonapi:"attr,serial"`
+	JobReference string `jsonapi:"attr,jobReference"`
 }
 
 type WorkspaceTagEntity struct {
@@ -68,6 +76,14 @@ type WorkspaceTagEntity struct {
 	TagID string `jsonapi:"attr,tagId"`
 }
 
+type WorkspaceAccessEntity struct {
+	ID              string `jsonapi:"primary,access"`
+	Name            string `jsonapi:"attr,name"`
+	ManageState     bool   `jsonapi:"attr,manageState"`
+	ManageWorkspace bool   `jsonapi:"attr,manageWorkspace"`
+	ManageJob       bool   `jsonapi:"attr,manageJob"`
+}
+
 type WorkspaceVariableEntity struct {
 	ID          string `jsonapi:"primary,variable"`
 	Key         string `jsonapi:"attr,key"`
@@ -100,6 +116,7 @@ type VcsEntity struct {
 	Endpoint       string `jsonapi:"attr,endpoint"`
 	ApiUrl         string `jsonapi:"attr,apiUrl"`
 	Status         string `jsonapi:"attr,status"`
+	UpdatedDate    string `jsonapi:"attr,updatedDate,omitempty"`
 }
 
 type SshEntity struct {
@@ -118,6 +135,7 @@ type ModuleEntity struct {
 	Ssh         *SshEntity `jsonapi:"relation,ssh,omitempty"`
 	Folder      *string    `jsonapi:"attr,folder"`
 	TagPrefix   *string    `jsonapi:"attr,tagPrefix"`
+	Versions    []string   `jsonapi:"attr,versions,omitempty"`
 }
 
 type CollectionEntity struct {
@@ -165,3 +183,19 @@ type WorkspaceScheduleEntity struct {
 	Schedule   string `jsonapi:"attr,cron"`
 	TemplateId string `jsonapi:"attr,templateReference"`
 }
+
+type JobEntity struct {
+	ID          string `jsonapi:"primary,job"`
+	Status      string `jsonapi:"attr,status"`
+	TemplateId  string `jsonapi:"attr,templateReference"`
+	CreatedDate string `jsonapi:"attr,createdDate"`
+	UpdatedDate string `jsonapi:"attr,updatedDate"`
+}
+
+type JobStepEntity struct {
+	ID         string `jsonapi:"primary,step"`
+	Name       string `jsonapi:"attr,name"`
+	StepNumber int32  `jsonapi:"attr,stepNumber"`
+	Status     string `jsonapi:"attr,status"`
+	Output     string `jsonapi:"attr,output"`
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError describes a request to the Terrakube API that returned an unexpected status.
+type APIError struct {
+	StatusCode int
+	Detail     string
+	RequestID  string
+	URL        string
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("request to %s failed with status %d", e.URL, e.StatusCode)
+	if e.RequestID != "" {
+		message = fmt.Sprintf("%s (request id %s)", message, e.RequestID)
+	}
+	if e.Detail != "" {
+		message = fmt.Sprintf("%s: %s", message, e.Detail)
+	}
+	return message
+}
+
+// CheckResponse returns an *APIError when the response status is not 2xx.
+func CheckResponse(response *http.Response, body []byte) error {
+	if response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	apiError := &APIError{
+		StatusCode: response.StatusCode,
+		Detail:     errorDetail(body),
+		RequestID:  response.Header.Get("X-Request-Id"),
+	}
+	if response.Request != nil && response.Request.URL != nil {
+		apiError.URL = response.Request.URL.String()
+	}
+
+	return apiError
+}
+
+// errorDetail extracts the detail of a JSON:API error document, falling back to the raw body.
+func errorDetail(body []byte) string {
+	var document struct {
+		Errors []struct {
+			Detail string `json:"detail"`
+			Title  string `json:"title"`
+		} `json:"errors"`
+	}
+
+	if err := json.Unmarshal(body, &document); err == nil && len(document.Errors) > 0 {
+		if document.Errors[0].Detail != "" {
+			return document.Errors[0].Detail
+		}
+		return document.Errors[0].Title
+	}
+
+	return string(body)
+}
+
+func statusCode(err error) int {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether the object of the request doesn't exist anymore.
+func IsNotFound(err error) bool {
+	status := statusCode(err)
+	return status == http.StatusNotFound || status == http.StatusGone
+}
+
+// IsConflict reports whether the request conflicts with an existing object.
+func IsConflict(err error) bool {
+	return statusCode(err) == http.StatusConflict
+}
+
+// IsAlreadyExists reports whether the object couldn't be created because an
+// object with the same unique name exists, either as a conflict or as the
+// unique constraint error returned with a 400.
+func IsAlreadyExists(err error) bool {
+	var apiError *APIError
+	if !errors.As(err, &apiError) {
+		return false
+	}
+
+	switch apiError.StatusCode {
+	case http.StatusConflict:
+		return true
+	case http.StatusBadRequest:
+		detail := strings.ToLower(apiError.Detail)
+		return strings.Contains(detail, "already exists") || strings.Contains(detail, "duplicate") || strings.Contains(detail, "unique")
+	}
+
+	return false
+}
+
+// IsLocked reports whether the object can't be changed right now, for example
+// because it is used by a running job.
+func IsLocked(err error) bool {
+	return statusCode(err) == http.StatusLocked
+}
+
+// IsUnauthorized reports whether the request was rejected because of the token.
+func IsUnauthorized(err error) bool {
+	status := statusCode(err)
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
+
+// IsMissingVcs reports whether a workspace was rejected because its repository
+// can't be cloned without a VCS connection.
+func IsMissingVcs(err error) bool {
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(apiError.Detail), "vcs")
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCheckResponse(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://terrakube.test/api/v1/organization/org", nil)
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    *APIError
+		wantMsg string
+	}{
+		{name: "ok", status: http.StatusOK},
+		{name: "no content", status: http.StatusNoContent},
+		{name: "json api error", status: http.StatusBadRequest, body: `{"errors":[{"title":"Bad Request","detail":"name is required"}]}`, want: &APIError{StatusCode: http.StatusBadRequest, Detail: "name is required", RequestID: "req-1", URL: request.URL.String()}, wantMsg: "request to https://terrakube.test/api/v1/organization/org failed with status 400 (request id req-1): name is required"},
+		{name: "title only", status: http.StatusForbidden, body: `{"errors":[{"title":"Forbidden"}]}`, want: &APIError{StatusCode: http.StatusForbidden, Detail: "Forbidden", RequestID: "req-1", URL: request.URL.String()}},
+		{name: "plain body", status: http.StatusBadGateway, body: "upstream down", want: &APIError{StatusCode: http.StatusBadGateway, Detail: "upstream down", RequestID: "req-1", URL: request.URL.String()}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := &http.Response{StatusCode: test.status, Header: http.Header{"X-Request-Id": []string{"req-1"}}, Request: request}
+			err := CheckResponse(response, []byte(test.body))
+
+			if test.want == nil {
+				if err != nil {
+					t.Fatalf("CheckResponse(%d) = %v, want nil", test.status, err)
+				}
+				return
+			}
+
+			var apiError *APIError
+			if !errors.As(err, &apiError) {
+				t.Fatalf("CheckResponse(%d) = %v, want *APIError", test.status, err)
+			}
+			if *apiError != *test.want {
+				t.Errorf("CheckResponse(%d) = %+v, want %+v", test.status, *apiError, *test.want)
+			}
+			if test.wantMsg != "" && err.Error() != test.wantMsg {
+				t.Errorf("Error() = %q, want %q", err.Error(), test.wantMsg)
+			}
+		})
+	}
+}
+
+func TestStatusPredicates(t *testing.T) {
+	tests := []struct {
+		status       int
+		notFound     bool
+		unauthorized bool
+		conflict     bool
+	}{
+		{status: http.StatusNotFound, notFound: true},
+		{status: http.StatusGone, notFound: true},
+		{status: http.StatusUnauthorized, unauthorized: true},
+		{status: http.StatusForbidden, unauthorized: true},
+		{status: http.StatusConflict, conflict: true},
+		{status: http.StatusInternalServerError},
+	}
+
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			err := fmt.Errorf("read: %w", &APIError{StatusCode: test.status})
+			if got := IsNotFound(err); got != test.notFound {
+				t.Errorf("IsNotFound = %t, want %t", got, test.notFound)
+			}
+			if got := IsUnauthorized(err); got != test.unauthorized {
+				t.Errorf("IsUnauthorized = %t, want %t", got, test.unauthorized)
+			}
+			if got := IsConflict(err); got != test.conflict {
+				t.Errorf("IsConflict = %t, want %t", got, test.conflict)
+			}
+		})
+	}
+
+	if IsNotFound(errors.New("connection refused")) || IsUnauthorized(nil) {
+		t.Error("predicates matched an error that isn't an *APIError")
+	}
+}
+
+func TestIsMissingVcs(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "missing vcs", err: &APIError{StatusCode: http.StatusBadRequest, Detail: "Repository requires a VCS connection"}, want: true},
+		{name: "wrapped", err: fmt.Errorf("create: %w", &APIError{StatusCode: http.StatusBadRequest, Detail: "no vcs"}), want: true},
+		{name: "other bad request", err: &APIError{StatusCode: http.StatusBadRequest, Detail: "name is required"}},
+		{name: "unauthorized", err: &APIError{StatusCode: http.StatusUnauthorized, Detail: "vcs"}},
+		{name: "server error", err: &APIError{StatusCode: http.StatusInternalServerError, Detail: "vcs"}},
+		{name: "nil", err: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsMissingVcs(test.err); got != test.want {
+				t.Errorf("IsMissingVcs(%v) = %t, want %t", test.err, got, test.want)
+			}
+		})
+	}
+}
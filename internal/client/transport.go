@@ -0,0 +1,291 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LimitedTransport wraps an http.RoundTripper and caps the number of requests
+// that can be in flight at the same time.
+type LimitedTransport struct {
+	transport http.RoundTripper
+	semaphore chan struct{}
+}
+
+// NewLimitedTransport returns a transport that allows at most
+// maxConcurrentRequests requests at once. A value of zero or less disables the
+// limit and returns the original transport.
+func NewLimitedTransport(transport http.RoundTripper, maxConcurrentRequests int) http.RoundTripper {
+	if maxConcurrentRequests <= 0 {
+		return transport
+	}
+
+	return &LimitedTransport{
+		transport: transport,
+		semaphore: make(chan struct{}, maxConcurrentRequests),
+	}
+}
+
+func (t *LimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.semaphore <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-t.semaphore }()
+
+	return t.transport.RoundTrip(req)
+}
+
+// ChangeReasonHeader is the header used to annotate API mutations with a change reason.
+const ChangeReasonHeader = "X-Change-Reason"
+
+// ChangeReasonTransport wraps an http.RoundTripper and adds the change reason
+// header to the requests that modify objects.
+type ChangeReasonTransport struct {
+	transport http.RoundTripper
+	reason    string
+}
+
+// NewChangeReasonTransport returns a transport that sends reason in the
+// X-Change-Reason header of POST, PATCH and DELETE requests. An empty reason
+// returns the original transport.
+func NewChangeReasonTransport(transport http.RoundTripper, reason string) http.RoundTripper {
+	if reason == "" {
+		return transport
+	}
+
+	return &ChangeReasonTransport{
+		transport: transport,
+		reason:    reason,
+	}
+}
+
+func (t *ChangeReasonTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPatch, http.MethodDelete:
+		if req.Header.Get(ChangeReasonHeader) == "" {
+			req = req.Clone(req.Context())
+			req.Header.Set(ChangeReasonHeader, t.reason)
+		}
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+// HeadersTransport wraps an http.RoundTripper and adds a fixed set of headers
+// to every request, for example the headers required by an API gateway.
+type HeadersTransport struct {
+	transport http.RoundTripper
+	headers   map[string]string
+}
+
+// NewHeadersTransport returns a transport that sends headers on every request.
+// An empty map returns the original transport.
+func NewHeadersTransport(transport http.RoundTripper, headers map[string]string) http.RoundTripper {
+	if len(headers) == 0 {
+		return transport
+	}
+
+	return &HeadersTransport{
+		transport: transport,
+		headers:   headers,
+	}
+}
+
+func (t *HeadersTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+// recordedHeaders are the headers written as is by RecordTransport, the
+// value of any other header, like Authorization or the headers of the provider
+// configuration, is masked.
+var recordedHeaders = map[string]bool{
+	"Accept":           true,
+	"Content-Length":   true,
+	"Content-Type":     true,
+	"Date":             true,
+	"Location":         true,
+	"Retry-After":      true,
+	"Traceparent":      true,
+	ChangeReasonHeader: true,
+}
+
+// recordedExchange is a request and its response as written by RecordTransport.
+type recordedExchange struct {
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	Status          int         `json:"status,omitempty"`
+	Error           string      `json:"error,omitempty"`
+	DurationMs      int64       `json:"duration_ms"`
+	RequestHeaders  http.Header `json:"request_headers"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+}
+
+// RecordTransport wraps an http.RoundTripper and writes every request and its
+// response to a numbered JSON file, so a trace of a Terraform operation can be
+// attached to a bug report. Bodies are passed through redact and headers are
+// masked, see recordedHeaders.
+type RecordTransport struct {
+	transport http.RoundTripper
+	dir       string
+	prefix    string
+	redact    func(string) string
+
+	mutex    sync.Mutex
+	sequence int
+}
+
+// NewRecordTransport returns a transport that records the requests in dir. The
+// files of a provider process share a prefix made of its start time and pid,
+// numbered in the order the requests are sent. An empty dir returns the
+// original transport.
+func NewRecordTransport(transport http.RoundTripper, dir string, redact func(string) string) http.RoundTripper {
+	if dir == "" {
+		return transport
+	}
+
+	return &RecordTransport{
+		transport: transport,
+		dir:       dir,
+		prefix:    fmt.Sprintf("%s-%d", time.Now().UTC().Format("20060102T150405"), os.Getpid()),
+		redact:    redact,
+	}
+}
+
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mutex.Lock()
+	t.sequence++
+	sequence := t.sequence
+	t.mutex.Unlock()
+
+	exchange := recordedExchange{
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeaders: t.maskHeaders(req.Header),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			content, _ := io.ReadAll(body)
+			body.Close()
+			exchange.RequestBody = t.redactBody(content)
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req)
+	exchange.DurationMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		exchange.Error = err.Error()
+	} else {
+		exchange.Status = resp.StatusCode
+		exchange.ResponseHeaders = t.maskHeaders(resp.Header)
+
+		// The body is read here, the caller reads the same bytes and error
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			exchange.Error = readErr.Error()
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errorReader{readErr}))
+		}
+		exchange.ResponseBody = t.redactBody(body)
+	}
+
+	t.write(sequence, exchange)
+
+	return resp, err
+}
+
+func (t *RecordTransport) maskHeaders(headers http.Header) http.Header {
+	masked := http.Header{}
+	for name, values := range headers {
+		if recordedHeaders[http.CanonicalHeaderKey(name)] {
+			masked[name] = values
+			continue
+		}
+		masked[name] = []string{"***"}
+	}
+	return masked
+}
+
+func (t *RecordTransport) redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	return t.redact(string(body))
+}
+
+// write saves exchange to its file, a failure is only logged so recording
+// never fails the operation.
+func (t *RecordTransport) write(sequence int, exchange recordedExchange) {
+	content, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		log.Printf("[WARN] unable to record request %s %s: %s", exchange.Method, exchange.URL, err)
+		return
+	}
+
+	if err = os.MkdirAll(t.dir, 0700); err == nil {
+		err = os.WriteFile(filepath.Join(t.dir, fmt.Sprintf("%s-%04d.json", t.prefix, sequence)), content, 0600)
+	}
+	if err != nil {
+		log.Printf("[WARN] unable to record request %s %s: %s", exchange.Method, exchange.URL, err)
+	}
+}
+
+// errorReader fails with err, it ends a body that failed to be read.
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read(_ []byte) (int, error) {
+	return 0, r.err
+}
+
+// maxRedirects is the number of redirects followed before giving up, like the default client.
+const maxRedirects = 10
+
+// NewCheckRedirect returns a CheckRedirect function that only follows redirects
+// to host. The Authorization header of the original request is set again on
+// every redirect, so it is kept whatever the default client copies. Redirects to other hosts and from https to http are
+// refused, the token must never be sent anywhere else.
+func NewCheckRedirect(host string) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+
+		original := via[0]
+		if !strings.EqualFold(req.URL.Hostname(), host) {
+			return fmt.Errorf("redirect from %s to %s refused, only redirects to the provider endpoint host %s are followed", original.URL, req.URL, host)
+		}
+
+		if original.URL.Scheme == "https" && req.URL.Scheme != "https" {
+			return fmt.Errorf("redirect from %s to %s refused, https requests can't be redirected to http", original.URL, req.URL)
+		}
+
+		if authorization := original.Header.Get("Authorization"); authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingTransport records the highest number of requests in flight at once.
+type countingTransport struct {
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	current := t.inFlight.Add(1)
+	defer t.inFlight.Add(-1)
+
+	for {
+		peak := t.maxInFlight.Load()
+		if current <= peak || t.maxInFlight.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestLimitedTransport(t *testing.T) {
+	tests := []struct {
+		name            string
+		limit           int
+		wantMaxInFlight int32
+	}{
+		{name: "serialized", limit: 1, wantMaxInFlight: 1},
+		{name: "limited", limit: 3, wantMaxInFlight: 3},
+		{name: "unlimited", limit: 0, wantMaxInFlight: 8},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			counting := &countingTransport{}
+			transport := NewLimitedTransport(counting, test.limit)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					req, _ := http.NewRequest(http.MethodGet, "http://terrakube.test", nil)
+					if _, err := transport.RoundTrip(req); err != nil {
+						t.Errorf("RoundTrip returned %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got := counting.maxInFlight.Load(); got > test.wantMaxInFlight {
+				t.Errorf("%d requests in flight at once, want at most %d", got, test.wantMaxInFlight)
+			}
+		})
+	}
+}
+
+func TestLimitedTransportCancelled(t *testing.T) {
+	transport := NewLimitedTransport(&countingTransport{}, 1).(*LimitedTransport)
+	transport.semaphore <- struct{}{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://terrakube.test", nil)
+
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("RoundTrip returned %v, want %v", err, context.Canceled)
+	}
+}
+
+// secretTransport answers every request with a body holding a secret.
+type secretTransport struct{}
+
+func (t *secretTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/vnd.api+json")
+	header.Set("Set-Cookie", "session=s3cr3t")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(`{"data":{"type":"vcs","attributes":{"clientSecret":"s3cr3t"}}}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRecordTransport(t *testing.T) {
+	if _, ok := NewRecordTransport(&secretTransport{}, "", nil).(*RecordTransport); ok {
+		t.Error("NewRecordTransport without a directory returned a RecordTransport")
+	}
+
+	dir := t.TempDir()
+	redact := func(body string) string { return strings.ReplaceAll(body, "s3cr3t", "***") }
+	transport := NewRecordTransport(&secretTransport{}, dir, redact)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://terrakube.test/api/v1/organization/org/vcs", strings.NewReader(`{"data":{"type":"vcs","attributes":{"clientSecret":"s3cr3t"}}}`))
+	req.Header.Set("Authorization", "Bearer t0ken")
+	req.Header.Set("X-Gateway-Key", "g4teway")
+	req.Header.Set("Content-Type", "application/vnd.api+json")
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip returned %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "s3cr3t") {
+		t.Errorf("response body returned to the caller was changed: %s", body)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("%d recorded files, want 1", len(files))
+	}
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("unable to read the recorded file: %s", err)
+	}
+	for _, secret := range []string{"t0ken", "g4teway", "s3cr3t"} {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("recorded file contains %s:\n%s", secret, content)
+		}
+	}
+	if !strings.Contains(string(content), "application/vnd.api+json") {
+		t.Errorf("recorded file doesn't contain the Content-Type header:\n%s", content)
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	var authorization string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	targetURL, _ := url.Parse(target.URL)
+
+	tests := []struct {
+		name     string
+		tls      bool
+		location string
+		wantErr  string
+	}{
+		{name: "same host", location: target.URL + "/api/v1/organization"},
+		{name: "other host", location: "http://localhost:" + targetURL.Port() + "/api/v1/organization", wantErr: "only redirects to the provider endpoint host"},
+		{name: "https to http", tls: true, location: target.URL + "/api/v1/organization", wantErr: "https requests can't be redirected to http"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			authorization = ""
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, test.location, http.StatusFound)
+			})
+			source := httptest.NewServer(handler)
+			if test.tls {
+				source.Close()
+				source = httptest.NewTLSServer(handler)
+			}
+			defer source.Close()
+
+			httpClient := source.Client()
+			httpClient.CheckRedirect = NewCheckRedirect(targetURL.Hostname())
+
+			req, _ := http.NewRequest(http.MethodGet, source.URL+"/api/v1/organization", nil)
+			req.Header.Set("Authorization", "Bearer t0ken")
+			resp, err := httpClient.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Do returned %v", err)
+				}
+				if authorization != "Bearer t0ken" {
+					t.Errorf("Authorization after the redirect = %q, want the original token", authorization)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Do returned %v, want an error containing %q", err, test.wantErr)
+			}
+			if authorization != "" {
+				t.Errorf("token sent to the redirect target: %q", authorization)
+			}
+		})
+	}
+}
+
+func TestCheckRedirectLoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path, http.StatusFound)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	httpClient := server.Client()
+	httpClient.CheckRedirect = NewCheckRedirect(serverURL.Hostname())
+
+	if _, err := httpClient.Get(server.URL + "/loop"); err == nil || !strings.Contains(err.Error(), "stopped after 10 redirects") {
+		t.Errorf("Get returned %v, want the redirect limit error", err)
+	}
+}
+
+func TestCheckRedirectAuthorization(t *testing.T) {
+	original, _ := http.NewRequest(http.MethodGet, "http://terrakube.test/api/v1/organization", nil)
+	original.Header.Set("Authorization", "Bearer t0ken")
+	redirect, _ := http.NewRequest(http.MethodGet, "https://terrakube.test/api/v1/organization", nil)
+
+	if err := NewCheckRedirect("terrakube.test")(redirect, []*http.Request{original}); err != nil {
+		t.Fatalf("CheckRedirect returned %v", err)
+	}
+	if got := redirect.Header.Get("Authorization"); got != "Bearer t0ken" {
+		t.Errorf("Authorization of the redirect = %q, want the original token", got)
+	}
+}
+
+func TestHeadersTransport(t *testing.T) {
+	if _, ok := NewHeadersTransport(http.DefaultTransport, nil).(*HeadersTransport); ok {
+		t.Error("NewHeadersTransport without headers returned a HeadersTransport")
+	}
+
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewHeadersTransport(server.Client().Transport, map[string]string{"X-Gateway-Key": "g4teway", "X-Tenant": "platform"})
+	httpClient := &http.Client{Transport: NewLimitedTransport(transport, 1)}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/organization", nil)
+	req.Header.Set("Authorization", "Bearer t0ken")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do returned %v", err)
+	}
+	resp.Body.Close()
+
+	for name, want := range map[string]string{"X-Gateway-Key": "g4teway", "X-Tenant": "platform", "Authorization": "Bearer t0ken"} {
+		if got := received.Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+	if value := req.Header.Get("X-Gateway-Key"); value != "" {
+		t.Errorf("the original request was changed: X-Gateway-Key = %q", value)
+	}
+}
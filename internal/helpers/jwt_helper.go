@@ -6,15 +6,25 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GetClaimFromToken(jwtToken string, claim string) (string, error) {
+// GetClaimsFromToken returns the claims of the token without verifying its signature.
+func GetClaimsFromToken(jwtToken string) (map[string]interface{}, error) {
 	token, _, err := new(jwt.Parser).ParseUnverified(jwtToken, jwt.MapClaims{})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", fmt.Errorf("failed to parse claims")
+		return nil, fmt.Errorf("failed to parse claims")
+	}
+
+	return claims, nil
+}
+
+func GetClaimFromToken(jwtToken string, claim string) (string, error) {
+	claims, err := GetClaimsFromToken(jwtToken)
+	if err != nil {
+		return "", err
 	}
 
 	c, ok := claims[claim].(string)
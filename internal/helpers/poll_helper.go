@@ -0,0 +1,156 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"time"
+)
+
+// PollResult tells Poll what to do after each attempt.
+type PollResult int
+
+const (
+	// PollRetry waits and calls the function again.
+	PollRetry PollResult = iota
+	// PollDone stops polling successfully.
+	PollDone
+	// PollFatal stops polling and returns the error of the attempt.
+	PollFatal
+)
+
+// PollMaxInterval caps the delay between two attempts.
+const PollMaxInterval = time.Minute
+
+// PollHistorySize is the number of attempts described in the error returned on timeout.
+const PollHistorySize = 5
+
+// ErrPollTimeout is returned by Poll when the timeout expires before the function is done.
+var ErrPollTimeout = errors.New("timeout while polling")
+
+// Poll calls fn until it returns PollDone or PollFatal, the timeout expires or
+// the context is cancelled. The delay starts at interval and doubles after each
+// attempt up to PollMaxInterval, with a jitter of 20% so several resources
+// polling at the same time don't hit the API together. On timeout the error of
+// the last attempt, if any, is wrapped in ErrPollTimeout, followed by one line
+// for each of the last PollHistorySize attempts.
+func Poll(ctx context.Context, interval time.Duration, timeout time.Duration, fn func(ctx context.Context) (PollResult, error)) error {
+	deadline := time.Now().Add(timeout)
+	delay := interval
+	history := pollHistory{}
+
+	for {
+		attempt := pollAttempt{at: time.Now()}
+		result, err := fn(ctx)
+		switch result {
+		case PollDone:
+			return nil
+		case PollFatal:
+			return err
+		}
+		attempt.err = err
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			history.add(attempt)
+			if err != nil {
+				return fmt.Errorf("%w: %w\n%s", ErrPollTimeout, err, history)
+			}
+			return fmt.Errorf("%w\n%s", ErrPollTimeout, history)
+		}
+
+		wait := delay + time.Duration((rand.Float64()*0.4-0.2)*float64(delay))
+		if wait > remaining {
+			wait = remaining
+		}
+		attempt.wait = wait
+		history.add(attempt)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		delay *= 2
+		if delay > PollMaxInterval {
+			delay = PollMaxInterval
+		}
+	}
+}
+
+// pollAttempt is an attempt of Poll that didn't finish.
+type pollAttempt struct {
+	at   time.Time
+	err  error
+	wait time.Duration
+}
+
+func (a pollAttempt) String() string {
+	outcome := "not done"
+	if a.err != nil {
+		outcome = attemptError(a.err)
+	}
+
+	if a.wait == 0 {
+		return fmt.Sprintf("%s %s", a.at.Format(time.TimeOnly), outcome)
+	}
+	return fmt.Sprintf("%s %s, waited %s", a.at.Format(time.TimeOnly), outcome, a.wait.Round(time.Second))
+}
+
+// pollHistory keeps the last PollHistorySize attempts of Poll.
+type pollHistory struct {
+	attempts []pollAttempt
+	total    int
+}
+
+func (h *pollHistory) add(attempt pollAttempt) {
+	h.total++
+	h.attempts = append(h.attempts, attempt)
+	if len(h.attempts) > PollHistorySize {
+		h.attempts = h.attempts[1:]
+	}
+}
+
+// String describes the attempts one per line, for example:
+//
+//	3 attempts:
+//	  10:01:02 status 502, waited 2s
+//	  10:01:04 status 502, waited 4s
+//	  10:01:09 connection refused
+func (h pollHistory) String() string {
+	var summary strings.Builder
+	if h.total > len(h.attempts) {
+		fmt.Fprintf(&summary, "%d attempts, last %d:", h.total, len(h.attempts))
+	} else {
+		fmt.Fprintf(&summary, "%d attempts:", h.total)
+	}
+
+	for _, attempt := range h.attempts {
+		fmt.Fprintf(&summary, "\n  %s", attempt)
+	}
+
+	return summary.String()
+}
+
+// attemptError returns a short description of the error of an attempt: the
+// status of an API error or the cause of a network error.
+func attemptError(err error) string {
+	var apiError *client.APIError
+	if errors.As(err, &apiError) {
+		return fmt.Sprintf("status %d", apiError.StatusCode)
+	}
+
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		return urlError.Err.Error()
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPoll(t *testing.T) {
+	errAttempt := errors.New("workspace busy")
+
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		cancel    bool
+		results   []PollResult
+		err       error
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "immediate success", timeout: time.Second, results: []PollResult{PollDone}, wantCalls: 1},
+		{name: "success after retries", timeout: time.Second, results: []PollResult{PollRetry, PollRetry, PollDone}, wantCalls: 3},
+		{name: "fatal", timeout: time.Second, results: []PollResult{PollRetry, PollFatal}, err: errAttempt, wantCalls: 2, wantErr: errAttempt},
+		{name: "timeout", timeout: 20 * time.Millisecond, results: []PollResult{PollRetry}, wantErr: ErrPollTimeout},
+		{name: "timeout wraps the last error", timeout: 20 * time.Millisecond, results: []PollResult{PollRetry}, err: errAttempt, wantErr: errAttempt},
+		{name: "cancelled", timeout: time.Minute, cancel: true, results: []PollResult{PollRetry}, wantCalls: 1, wantErr: context.Canceled},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			calls := 0
+			err := Poll(ctx, time.Millisecond, test.timeout, func(ctx context.Context) (PollResult, error) {
+				result := test.results[min(calls, len(test.results)-1)]
+				calls++
+				if test.cancel {
+					cancel()
+				}
+				return result, test.err
+			})
+
+			if test.wantErr == nil && err != nil {
+				t.Fatalf("Poll returned %v", err)
+			}
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Fatalf("Poll returned %v, want %v", err, test.wantErr)
+			}
+			if test.wantCalls > 0 && calls != test.wantCalls {
+				t.Errorf("fn called %d times, want %d", calls, test.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPollTimeoutHistory(t *testing.T) {
+	err := Poll(context.Background(), time.Millisecond, 50*time.Millisecond, func(ctx context.Context) (PollResult, error) {
+		return PollRetry, nil
+	})
+
+	if !errors.Is(err, ErrPollTimeout) {
+		t.Fatalf("Poll returned %v, want %v", err, ErrPollTimeout)
+	}
+	if lines := strings.Split(err.Error(), "\n"); len(lines) > PollHistorySize+2 {
+		t.Errorf("timeout error has %d lines, want at most %d:\n%s", len(lines), PollHistorySize+2, err)
+	}
+}
@@ -0,0 +1,58 @@
+package helpers
+
+import (
+	"encoding/json"
+)
+
+// redactedValue replaces sensitive values in logged payloads.
+const redactedValue = "***"
+
+// sensitiveAttributes are the JSON:API attributes that are always redacted.
+var sensitiveAttributes = []string{"clientSecret", "accessToken", "privateKey", "sshPrivateKey"}
+
+// RedactPayload returns a JSON:API payload that can be logged. Secrets like
+// clientSecret, accessToken and privateKey are masked, as well as the value of
+// sensitive variables. Payloads that can't be parsed are fully masked.
+func RedactPayload(payload string) string {
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &document); err != nil {
+		return redactedValue
+	}
+
+	switch data := document["data"].(type) {
+	case map[string]interface{}:
+		redactResource(data)
+	case []interface{}:
+		for _, item := range data {
+			if resource, ok := item.(map[string]interface{}); ok {
+				redactResource(resource)
+			}
+		}
+	}
+
+	redacted, err := json.Marshal(document)
+	if err != nil {
+		return redactedValue
+	}
+
+	return string(redacted)
+}
+
+func redactResource(resource map[string]interface{}) {
+	attributes, ok := resource["attributes"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, name := range sensitiveAttributes {
+		if _, ok := attributes[name]; ok {
+			attributes[name] = redactedValue
+		}
+	}
+
+	if sensitive, ok := attributes["sensitive"].(bool); ok && sensitive {
+		if _, ok := attributes["value"]; ok {
+			attributes["value"] = redactedValue
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{
+			name:    "vcs secret",
+			payload: `{"data":{"type":"vcs","id":"vcs","attributes":{"name":"github","clientSecret":"s3cr3t","accessToken":"s3cr3t"}}}`,
+			want:    `{"data":{"attributes":{"accessToken":"***","clientSecret":"***","name":"github"},"id":"vcs","type":"vcs"}}`,
+		},
+		{
+			name:    "ssh private key",
+			payload: `{"data":{"type":"ssh","id":"ssh","attributes":{"name":"deploy","privateKey":"s3cr3t","sshPrivateKey":"s3cr3t"}}}`,
+			want:    `{"data":{"attributes":{"name":"deploy","privateKey":"***","sshPrivateKey":"***"},"id":"ssh","type":"ssh"}}`,
+		},
+		{
+			name:    "variable list",
+			payload: `{"data":[{"type":"variable","id":"1","attributes":{"key":"password","value":"s3cr3t","sensitive":true}},{"type":"variable","id":"2","attributes":{"key":"region","value":"eu-west-1","sensitive":false}}]}`,
+			want:    `{"data":[{"attributes":{"key":"password","sensitive":true,"value":"***"},"id":"1","type":"variable"},{"attributes":{"key":"region","sensitive":false,"value":"eu-west-1"},"id":"2","type":"variable"}]}`,
+		},
+		{
+			name:    "errors",
+			payload: `{"errors":[{"detail":"not found"}]}`,
+			want:    `{"errors":[{"detail":"not found"}]}`,
+		},
+		{
+			name:    "not json",
+			payload: `token=s3cr3t`,
+			want:    `***`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := RedactPayload(test.payload)
+			if got != test.want {
+				t.Errorf("RedactPayload = %s, want %s", got, test.want)
+			}
+			if strings.Contains(got, "s3cr3t") {
+				t.Errorf("RedactPayload kept a secret: %s", got)
+			}
+		})
+	}
+}
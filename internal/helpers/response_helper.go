@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// GetIDFromResponse returns the id of the object created by a request, it reads
+// data.id from the body and falls back to the last segment of the Location header.
+func GetIDFromResponse(response *http.Response, body []byte) string {
+	var payload struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Data.ID != "" {
+		return payload.Data.ID
+	}
+
+	if response == nil {
+		return ""
+	}
+
+	location := strings.TrimSuffix(response.Header.Get("Location"), "/")
+	if location == "" {
+		return ""
+	}
+
+	return location[strings.LastIndex(location, "/")+1:]
+}
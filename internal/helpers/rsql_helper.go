@@ -0,0 +1,11 @@
+package helpers
+
+import "strings"
+
+// RsqlQuote quotes a value used in an RSQL filter, so names with spaces,
+// commas, semicolons or quotes are compared as a single literal.
+func RsqlQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
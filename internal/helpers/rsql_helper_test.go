@@ -0,0 +1,29 @@
+package helpers
+
+import "testing"
+
+func TestRsqlQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "production", want: `"production"`},
+		{value: "vault.addr", want: `"vault.addr"`},
+		{value: "team/platform", want: `"team/platform"`},
+		{value: "my workspace", want: `"my workspace"`},
+		{value: "a,b;c==d", want: `"a,b;c==d"`},
+		{value: "équipe-données", want: `"équipe-données"`},
+		{value: `say "hi"`, want: `"say \"hi\""`},
+		{value: `C:\temp`, want: `"C:\\temp"`},
+		{value: `\"`, want: `"\\\""`},
+		{value: "", want: `""`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if got := RsqlQuote(test.value); got != test.want {
+				t.Errorf("RsqlQuote(%q) = %s, want %s", test.value, got, test.want)
+			}
+		})
+	}
+}
@@ -3,12 +3,15 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+	"time"
 
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -17,6 +20,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -24,11 +28,20 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &CollectionItemResource{}
 var _ resource.ResourceWithImportState = &CollectionItemResource{}
+var _ resource.ResourceWithModifyPlan = &CollectionItemResource{}
+
+var (
+	// collectionItemInUsePollInterval is the first delay between two attempts to delete an item in use.
+	collectionItemInUsePollInterval = 10 * time.Second
+	// collectionItemInUseTimeout bounds how long Delete waits for an item to stop being used.
+	collectionItemInUseTimeout = 5 * time.Minute
+)
 
 type CollectionItemResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type CollectionItemResourceModel struct {
@@ -63,10 +76,7 @@ func (r *CollectionItemResource) Schema(ctx context.Context, req resource.Schema
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"collection_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Terrakube collection id",
@@ -74,10 +84,14 @@ func (r *CollectionItemResource) Schema(ctx context.Context, req resource.Schema
 			"key": schema.StringAttribute{
 				Required:    true,
 				Description: "Variable key",
+				Validators: []validator.String{
+					variableKeyValidator(),
+				},
 			},
 			"value": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable value",
+				Sensitive:   true,
+				Description: "Variable value, always hidden in the plan output. `sensitive` only sets whether the API hides it.",
 			},
 			"description": schema.StringAttribute{
 				Required:    true,
@@ -114,20 +128,11 @@ func (r *CollectionItemResource) Configure(ctx context.Context, req resource.Con
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Collection Item resource", map[string]any{"success": true})
 }
@@ -158,7 +163,7 @@ func (r *CollectionItemResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
-	collectionItemRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item", r.endpoint, plan.OrganizationId.ValueString(), plan.CollectionId.ValueString()), strings.NewReader(out.String()))
+	collectionItemRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", url.PathEscape(plan.OrganizationId.ValueString()), "collection", url.PathEscape(plan.CollectionId.ValueString()), "item"), strings.NewReader(out.String()))
 	collectionItemRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionItemRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -215,7 +220,7 @@ func (r *CollectionItemResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	collectionItemRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item/%s", r.endpoint, state.OrganizationId.ValueString(), state.CollectionId.ValueString(), state.ID.ValueString()), nil)
+	collectionItemRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "collection", url.PathEscape(state.CollectionId.ValueString()), "item", url.PathEscape(state.ID.ValueString())), nil)
 	collectionItemRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionItemRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -233,6 +238,11 @@ func (r *CollectionItemResource) Read(ctx context.Context, req resource.ReadRequ
 	if err != nil {
 		tflog.Error(ctx, "Error reading collection item resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, collectionItemResponse, bodyResponse, "collection item") {
+		return
+	}
+
 	collectionItem := &client.CollectionItemEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -280,6 +290,9 @@ func (r *CollectionItemResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	// The value must not be logged while the item is, or is becoming, sensitive.
+	logBody := !plan.Sensitive.ValueBool() && !state.Sensitive.ValueBool()
+
 	bodyRequest := &client.CollectionItemEntity{
 		Key:         plan.Key.ValueString(),
 		Value:       plan.Value.ValueString(),
@@ -298,7 +311,7 @@ func (r *CollectionItemResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
-	collectionItemReq, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item/%s", r.endpoint, state.OrganizationId.ValueString(), state.CollectionId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	collectionItemReq, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "collection", url.PathEscape(state.CollectionId.ValueString()), "item", url.PathEscape(state.ID.ValueString())), strings.NewReader(out.String()))
 	collectionItemReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionItemReq.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -317,9 +330,11 @@ func (r *CollectionItemResource) Update(ctx context.Context, req resource.Update
 		tflog.Error(ctx, "Error reading collection item resource response")
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
+	if logBody {
+		tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
+	}
 
-	collectionItemReq, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item/%s", r.endpoint, state.OrganizationId.ValueString(), state.CollectionId.ValueString(), state.ID.ValueString()), nil)
+	collectionItemReq, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "collection", url.PathEscape(state.CollectionId.ValueString()), "item", url.PathEscape(state.ID.ValueString())), nil)
 	collectionItemReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionItemReq.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -338,7 +353,9 @@ func (r *CollectionItemResource) Update(ctx context.Context, req resource.Update
 		resp.Diagnostics.AddError("Error reading collection item resource response body", fmt.Sprintf("Error reading collection item resource response body: %s", err))
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+	if logBody {
+		tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+	}
 
 	collectionItem := &client.CollectionItemEntity{}
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), collectionItem)
@@ -348,6 +365,14 @@ func (r *CollectionItemResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	if state.Sensitive.ValueBool() && !collectionItem.Sensitive && collectionItem.Value != plan.Value.ValueString() {
+		resp.Diagnostics.AddError(
+			"Collection item value not returned",
+			fmt.Sprintf("Collection item %s is no longer sensitive but the API didn't return the value sent in the update. Apply again or recreate the item to store the value.", plan.Key.ValueString()),
+		)
+		return
+	}
+
 	if collectionItem.Sensitive {
 		tflog.Info(ctx, "Collection item is not included in response, setting values the same as the plan for sensitive=true...")
 		plan.Value = types.StringValue(plan.Value.ValueString())
@@ -376,18 +401,55 @@ func (r *CollectionItemResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/item/%s", r.endpoint, data.OrganizationId.ValueString(), data.CollectionId.ValueString(), data.ID.ValueString()), nil)
-	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	err := helpers.Poll(ctx, collectionItemInUsePollInterval, collectionItemInUseTimeout, func(ctx context.Context) (helpers.PollResult, error) {
+		err := r.deleteItem(data)
+		switch {
+		case err == nil:
+			return helpers.PollDone, nil
+		case client.IsConflict(err), client.IsLocked(err):
+			tflog.Info(ctx, fmt.Sprintf("Collection item %s is in use, waiting to delete it", data.Key.ValueString()), map[string]any{"error": err.Error()})
+			return helpers.PollRetry, err
+		default:
+			return helpers.PollFatal, err
+		}
+	})
+
+	switch {
+	case errors.Is(err, helpers.ErrPollTimeout):
+		resp.Diagnostics.AddError(
+			"Collection item in use",
+			fmt.Sprintf("Collection item %s is still in use after %s, retry once the jobs using it have finished: %s", data.Key.ValueString(), collectionItemInUseTimeout, err),
+		)
+	case err != nil:
+		resp.Diagnostics.AddError("Error deleting collection item", fmt.Sprintf("Error deleting collection item %s: %s", data.Key.ValueString(), err))
+	}
+}
+
+// deleteItem sends the DELETE request of the item and returns an *client.APIError
+// when the API refuses it.
+func (r *CollectionItemResource) deleteItem(data CollectionItemResourceModel) error {
+	collectionItemRequest, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", url.PathEscape(data.OrganizationId.ValueString()), "collection", url.PathEscape(data.CollectionId.ValueString()), "item", url.PathEscape(data.ID.ValueString())), nil)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating collection item resource request", fmt.Sprintf("Error creating collection item resource request: %s", err))
-		return
+		return fmt.Errorf("error creating collection item resource request: %w", err)
+	}
+	collectionItemRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+
+	collectionItemResponse, err := r.client.Do(collectionItemRequest)
+	if err != nil {
+		return fmt.Errorf("error executing collection item resource request: %w", err)
 	}
+	defer collectionItemResponse.Body.Close()
 
-	_, err = r.client.Do(workspaceRequest)
+	bodyResponse, err := io.ReadAll(collectionItemResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing collection item resource request", fmt.Sprintf("Error executing collection item resource request: %s", err))
-		return
+		return fmt.Errorf("error reading collection item resource response: %w", err)
 	}
+
+	return checkDeleteResponse(collectionItemResponse, bodyResponse)
+}
+
+func (r *CollectionItemResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
 }
 
 func (r *CollectionItemResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+var collectionItemTestAttributes = map[string]string{
+	"id":              "item",
+	"organization_id": "org",
+	"collection_id":   "collection",
+	"key":             "ARM_CLIENT_SECRET",
+	"value":           "old-secret",
+	"category":        "ENV",
+}
+
+// collectionItemTestState returns a state of the item with sensitive set.
+func collectionItemTestState(t *testing.T, r resource.Resource, value string, sensitive bool) tfsdk.State {
+	t.Helper()
+
+	attributes := map[string]string{}
+	for name, attribute := range collectionItemTestAttributes {
+		attributes[name] = attribute
+	}
+	attributes["value"] = value
+
+	state := newTestState(t, r, attributes)
+	for name, attribute := range map[string]bool{"sensitive": sensitive, "hcl": false} {
+		if diags := state.SetAttribute(context.Background(), path.Root(name), types.BoolValue(attribute)); diags.HasError() {
+			t.Fatalf("unable to set %s: %v", name, diags)
+		}
+	}
+
+	return state
+}
+
+func TestCollectionItemDelete(t *testing.T) {
+	interval, timeout := collectionItemInUsePollInterval, collectionItemInUseTimeout
+	collectionItemInUsePollInterval, collectionItemInUseTimeout = time.Millisecond, 100*time.Millisecond
+	defer func() {
+		collectionItemInUsePollInterval, collectionItemInUseTimeout = interval, timeout
+	}()
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int
+		wantErr      string
+	}{
+		{name: "deleted", statuses: []int{http.StatusNoContent}, wantRequests: 1},
+		{name: "already deleted", statuses: []int{http.StatusNotFound}, wantRequests: 1},
+		{name: "forbidden", statuses: []int{http.StatusForbidden}, wantRequests: 1, wantErr: "item is protected"},
+		{name: "in use then deleted", statuses: []int{http.StatusConflict, http.StatusLocked, http.StatusNoContent}, wantRequests: 3},
+		{name: "still in use", statuses: []int{http.StatusConflict}, wantErr: "still in use"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/organization/org/collection/collection/item/item" {
+					t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+				}
+				status := test.statuses[min(requests, len(test.statuses)-1)]
+				requests++
+				w.WriteHeader(status)
+				if status >= http.StatusBadRequest && status != http.StatusNotFound {
+					_, _ = w.Write([]byte(`{"errors":[{"detail":"item is protected"}]}`))
+				}
+			}))
+			defer server.Close()
+
+			r := NewCollectionItemResource()
+			configureTestResource(t, r, server)
+			resp := deleteTestResource(t, r, collectionItemTestAttributes)
+
+			if test.wantErr == "" && resp.Diagnostics.HasError() {
+				t.Fatalf("Delete returned %v", resp.Diagnostics)
+			}
+			if test.wantErr != "" {
+				if !resp.Diagnostics.HasError() {
+					t.Fatal("Delete returned no error")
+				}
+				if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, test.wantErr) {
+					t.Errorf("error detail %q doesn't contain %q", detail, test.wantErr)
+				}
+			}
+			if test.wantRequests > 0 && requests != test.wantRequests {
+				t.Errorf("%d DELETE requests, want %d", requests, test.wantRequests)
+			}
+		})
+	}
+}
+
+func TestCollectionItemUpdateSensitive(t *testing.T) {
+	tests := []struct {
+		name          string
+		fromSensitive bool
+		toSensitive   bool
+		echoValue     bool
+		wantErr       bool
+	}{
+		{name: "no longer sensitive", fromSensitive: true, echoValue: true},
+		{name: "no longer sensitive without value", fromSensitive: true, wantErr: true},
+		{name: "becomes sensitive", toSensitive: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var patch []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPatch {
+					patch, _ = io.ReadAll(r.Body)
+					_, _ = w.Write([]byte(`{"data":{"type":"item","id":"item","attributes":{"key":"ARM_CLIENT_SECRET","value":"new-secret","sensitive":false}}}`))
+					return
+				}
+
+				value := ""
+				if test.echoValue {
+					value = "new-secret"
+				}
+				_, _ = fmt.Fprintf(w, `{"data":{"type":"item","id":"item","attributes":{"key":"ARM_CLIENT_SECRET","value":%q,"category":"ENV","sensitive":%t}}}`, value, test.toSensitive)
+			}))
+			defer server.Close()
+
+			r := NewCollectionItemResource()
+			configureTestResource(t, r, server)
+
+			state := collectionItemTestState(t, r, "old-secret", test.fromSensitive)
+			planState := collectionItemTestState(t, r, "new-secret", test.toSensitive)
+			plan := tfsdk.Plan{Schema: planState.Schema, Raw: planState.Raw}
+
+			var logs bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &logs)
+			resp := resource.UpdateResponse{State: state}
+			r.Update(ctx, resource.UpdateRequest{Plan: plan, State: state}, &resp)
+
+			if !strings.Contains(string(patch), "new-secret") {
+				t.Errorf("PATCH payload %s doesn't send the value", patch)
+			}
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Fatalf("Update diagnostics = %v, want error %t", resp.Diagnostics, test.wantErr)
+			}
+			for _, diagnostic := range resp.Diagnostics {
+				if strings.Contains(diagnostic.Detail(), "new-secret") || strings.Contains(diagnostic.Detail(), "old-secret") {
+					t.Errorf("diagnostic contains the value: %s", diagnostic.Detail())
+				}
+			}
+			if strings.Contains(logs.String(), "new-secret") || strings.Contains(logs.String(), "old-secret") {
+				t.Errorf("logs contain the value:\n%s", logs.String())
+			}
+
+			if test.wantErr {
+				return
+			}
+			var value types.String
+			resp.State.GetAttribute(ctx, path.Root("value"), &value)
+			if value.ValueString() != "new-secret" {
+				t.Errorf("value in state = %q, want the planned value", value.ValueString())
+			}
+		})
+	}
+}
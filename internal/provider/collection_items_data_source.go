@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// collectionItemsPageSize is the number of collection items requested on each page.
+const collectionItemsPageSize = 100
+
+var (
+	_ datasource.DataSource                     = &CollectionItemsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &CollectionItemsDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &CollectionItemsDataSource{}
+)
+
+type CollectionItemsDataSourceModel struct {
+	OrganizationId types.String `tfsdk:"organization_id"`
+	CollectionId   types.String `tfsdk:"collection_id"`
+	CollectionName types.String `tfsdk:"collection_name"`
+	Values         types.Map    `tfsdk:"values"`
+	SensitiveKeys  types.List   `tfsdk:"sensitive_keys"`
+}
+
+type CollectionItemsDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewCollectionItemsDataSource() datasource.DataSource {
+	return &CollectionItemsDataSource{}
+}
+
+func (d *CollectionItemsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Collection Items Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	ctx = tflog.SetField(ctx, "endpoint", d.endpoint)
+	ctx = tflog.SetField(ctx, "token", d.token)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "token")
+	tflog.Info(ctx, "Collection Items Data Source configured")
+}
+
+func (d *CollectionItemsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_collection_items"
+}
+
+func (d *CollectionItemsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Read the items of a collection as a map of key and value. Values of sensitive items are never read, only their keys are listed in `sensitive_keys`.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Organization ID",
+			},
+			"collection_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Collection ID, either `collection_id` or `collection_name` must be set",
+			},
+			"collection_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Collection name, either `collection_id` or `collection_name` must be set",
+			},
+			"values": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Values of the non sensitive items keyed by item key",
+			},
+			"sensitive_keys": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Keys of the sensitive items, sorted",
+			},
+		},
+	}
+}
+
+func (d *CollectionItemsDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("collection_id"),
+			path.MatchRoot("collection_name"),
+		),
+	}
+}
+
+func (d *CollectionItemsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state CollectionItemsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organizationId := state.OrganizationId.ValueString()
+
+	if state.CollectionId.IsNull() {
+		query := url.Values{}
+		query.Set("filter[collection]", "name=="+helpers.RsqlQuote(state.CollectionName.ValueString()))
+
+		collections, err := d.getAll(ctx, apiURL(d.endpoint, "organization", url.PathEscape(organizationId), "collection"), query, reflect.TypeOf(new(client.CollectionEntity)))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading collections", err.Error())
+			return
+		}
+
+		for _, item := range collections {
+			if collection := item.(*client.CollectionEntity); collection.Name == state.CollectionName.ValueString() {
+				state.CollectionId = types.StringValue(collection.ID)
+			}
+		}
+
+		if state.CollectionId.IsNull() {
+			resp.Diagnostics.AddError("Collection not found", fmt.Sprintf("Collection %s doesn't exist in organization %s", state.CollectionName.ValueString(), organizationId))
+			return
+		}
+	}
+
+	items, err := d.getAll(ctx, apiURL(d.endpoint, "organization", url.PathEscape(organizationId), "collection", url.PathEscape(state.CollectionId.ValueString()), "item"), url.Values{}, reflect.TypeOf(new(client.CollectionItemEntity)))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading collection items", err.Error())
+		return
+	}
+
+	values := map[string]string{}
+	sensitiveKeys := []string{}
+	for _, item := range items {
+		collectionItem := item.(*client.CollectionItemEntity)
+		if collectionItem.Sensitive {
+			sensitiveKeys = append(sensitiveKeys, collectionItem.Key)
+			continue
+		}
+		values[collectionItem.Key] = collectionItem.Value
+	}
+	sort.Strings(sensitiveKeys)
+
+	valuesValue, diags := types.MapValueFrom(ctx, types.StringType, values)
+	resp.Diagnostics.Append(diags...)
+	sensitiveKeysValue, diags := types.ListValueFrom(ctx, types.StringType, sensitiveKeys)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Values = valuesValue
+	state.SensitiveKeys = sensitiveKeysValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// getAll returns every object of the list, requesting one page at a time.
+func (d *CollectionItemsDataSource) getAll(ctx context.Context, apiURL string, query url.Values, t reflect.Type) ([]interface{}, error) {
+	query.Set("page[size]", fmt.Sprintf("%d", collectionItemsPageSize))
+
+	var all []interface{}
+	for page := 1; ; page++ {
+		query.Set("page[number]", fmt.Sprintf("%d", page))
+
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s?%s", apiURL, query.Encode()), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating collection request: %w", err)
+		}
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+		request.Header.Add("Content-Type", "application/vnd.api+json")
+
+		response, err := d.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("error executing collection request: %w", err)
+		}
+
+		bodyResponse, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading collection response body: %w", err)
+		}
+
+		// The body holds the values of the items, it is never logged
+		tflog.Debug(ctx, "Collection response", map[string]any{"status": response.Status})
+
+		if err = client.CheckResponse(response, bodyResponse); err != nil {
+			return nil, err
+		}
+
+		items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), t)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal payload: %w", err)
+		}
+
+		all = append(all, items...)
+
+		if len(items) < collectionItemsPageSize {
+			return all, nil
+		}
+	}
+}
@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -24,11 +24,13 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &CollectionReferenceResource{}
 var _ resource.ResourceWithImportState = &CollectionReferenceResource{}
+var _ resource.ResourceWithModifyPlan = &CollectionReferenceResource{}
 
 type CollectionReferenceResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type CollectionReferenceResourceModel struct {
@@ -59,13 +61,13 @@ func (r *CollectionReferenceResource) Schema(ctx context.Context, req resource.S
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"workspace_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Terrakube workspace id",
+				Validators: []validator.String{
+					workspaceIdValidator(),
+				},
 			},
 			"collection_id": schema.StringAttribute{
 				Required:    true,
@@ -94,20 +96,11 @@ func (r *CollectionReferenceResource) Configure(ctx context.Context, req resourc
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Collection reference resource", map[string]any{"success": true})
 }
@@ -135,7 +128,7 @@ func (r *CollectionReferenceResource) Create(ctx context.Context, req resource.C
 		return
 	}
 
-	collectionReferenceRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s/reference", r.endpoint, plan.OrganizationId.ValueString(), plan.CollectionId.ValueString()), strings.NewReader(out.String()))
+	collectionReferenceRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "collection", plan.CollectionId.ValueString(), "reference"), strings.NewReader(out.String()))
 	collectionReferenceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionReferenceRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -182,24 +175,22 @@ func (r *CollectionReferenceResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
-	collectionItemRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/reference/%s", r.endpoint, state.ID.ValueString()), nil)
-	collectionItemRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	collectionItemRequest.Header.Add("Content-Type", "application/vnd.api+json")
+	collectionReferenceResponse, bodyResponse, err := r.referenceRequest(ctx, http.MethodGet, state, "")
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating collection reference resource request", fmt.Sprintf("Error creating collection reference resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing collection reference resource request", fmt.Sprintf("Error executing collection reference resource request: %s", err))
 		return
 	}
 
-	collectionReferenceResponse, err := r.client.Do(collectionItemRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing collection reference resource request", fmt.Sprintf("Error executing collection reference resource request: %s", err))
+	if err = client.CheckResponse(collectionReferenceResponse, bodyResponse); err != nil && !client.IsNotFound(err) && r.parentGone(ctx, state) {
+		tflog.Debug(ctx, "Collection or workspace of the reference was deleted, removing from state", map[string]any{"collectionId": state.CollectionId.ValueString(), "workspaceId": state.WorkspaceId.ValueString()})
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(collectionReferenceResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading collection item resource response")
+	if !checkReadResponse(ctx, resp, collectionReferenceResponse, bodyResponse, "collection reference") {
+		return
 	}
+
 	collectionReference := &client.CollectionReferenceEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -212,6 +203,13 @@ func (r *CollectionReferenceResource) Read(ctx context.Context, req resource.Rea
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
+	// The API leaves out the relationship of a deleted workspace or collection
+	if collectionReference.Workspace == nil || collectionReference.Collection == nil {
+		tflog.Debug(ctx, "Collection or workspace of the reference was deleted, removing from state", map[string]any{"collectionId": state.CollectionId.ValueString(), "workspaceId": state.WorkspaceId.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	state.WorkspaceId = types.StringValue(collectionReference.Workspace.ID)
 	state.CollectionId = types.StringValue(collectionReference.Collection.ID)
 	state.Description = types.StringValue(collectionReference.Description)
@@ -252,46 +250,20 @@ func (r *CollectionReferenceResource) Update(ctx context.Context, req resource.U
 		return
 	}
 
-	collectionReferenceReq, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/reference/%s", r.endpoint, state.ID.ValueString()), strings.NewReader(out.String()))
-	collectionReferenceReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	collectionReferenceReq.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating collection reference resource request", fmt.Sprintf("Error creating collection reference resource request: %s", err))
-		return
-	}
-
-	collectionReferenceResponse, err := r.client.Do(collectionReferenceReq)
+	_, bodyResponse, err := r.referenceRequest(ctx, http.MethodPatch, state, out.String())
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing collection reference resource request", fmt.Sprintf("Error executing collection reference resource request: %s", err))
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(collectionReferenceResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading collection item resource response")
-	}
-
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	collectionReferenceReq, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/reference/%s", r.endpoint, state.ID.ValueString()), nil)
-	collectionReferenceReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	collectionReferenceReq.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating collection reference resource request", fmt.Sprintf("Error creating collection reference resource request: %s", err))
-		return
-	}
-
-	collectionReferenceResponse, err = r.client.Do(collectionReferenceReq)
+	_, bodyResponse, err = r.referenceRequest(ctx, http.MethodGet, state, "")
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing collection reference resource request", fmt.Sprintf("Error executing collection reference resource request: %s", err))
 		return
 	}
 
-	bodyResponse, err = io.ReadAll(collectionReferenceResponse.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Error reading collection reference resource response body", fmt.Sprintf("Error reading collection reference resource response body: %s", err))
-	}
-
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
 	collectionReference := &client.CollectionReferenceEntity{}
@@ -320,20 +292,72 @@ func (r *CollectionReferenceResource) Delete(ctx context.Context, req resource.D
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/reference/%s", r.endpoint, data.ID.ValueString()), nil)
-	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	collectionReferenceResponse, bodyResponse, err := r.referenceRequest(ctx, http.MethodDelete, data, "")
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating collection reference resource request", fmt.Sprintf("Error creating collection reference resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing collection reference resource request", fmt.Sprintf("Error executing collection reference resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(workspaceRequest)
-	if err != nil {
+	if err = checkDeleteResponse(collectionReferenceResponse, bodyResponse); err != nil {
+		if r.parentGone(ctx, data) {
+			tflog.Debug(ctx, "Collection or workspace of the reference was deleted, nothing to delete", map[string]any{"collectionId": data.CollectionId.ValueString(), "workspaceId": data.WorkspaceId.ValueString()})
+			return
+		}
 		resp.Diagnostics.AddError("Error executing collection reference resource request", fmt.Sprintf("Error executing collection reference resource request: %s", err))
 		return
 	}
 }
 
+// parentGone reports whether the collection or the workspace of the reference was deleted.
+func (r *CollectionReferenceResource) parentGone(ctx context.Context, model CollectionReferenceResourceModel) bool {
+	return collectionGone(ctx, r.client, r.endpoint, r.token, model.OrganizationId.ValueString(), model.CollectionId.ValueString()) ||
+		workspaceGone(ctx, r.client, r.endpoint, r.token, model.OrganizationId.ValueString(), model.WorkspaceId.ValueString())
+}
+
+// referenceRequest sends the request to /reference/{id}. Older Terrakube releases
+// only expose references under their collection, so on 404 or 405 the request
+// is sent again to /organization/{org}/collection/{col}/reference/{id}.
+func (r *CollectionReferenceResource) referenceRequest(ctx context.Context, method string, model CollectionReferenceResourceModel, body string) (*http.Response, []byte, error) {
+	urls := []string{
+		apiURL(r.endpoint, "reference", model.ID.ValueString()),
+		apiURL(r.endpoint, "organization", model.OrganizationId.ValueString(), "collection", model.CollectionId.ValueString(), "reference", model.ID.ValueString()),
+	}
+
+	var response *http.Response
+	var bodyResponse []byte
+	for _, url := range urls {
+		request, err := http.NewRequest(method, url, strings.NewReader(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+		request.Header.Add("Content-Type", "application/vnd.api+json")
+
+		response, err = r.client.Do(request)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		bodyResponse, err = io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if response.StatusCode != http.StatusNotFound && response.StatusCode != http.StatusMethodNotAllowed {
+			break
+		}
+
+		tflog.Debug(ctx, "Collection reference endpoint unavailable", map[string]any{"url": url, "status": response.Status})
+	}
+
+	return response, bodyResponse, nil
+}
+
+func (r *CollectionReferenceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
+}
+
 func (r *CollectionReferenceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	idParts := strings.Split(req.ID, ",")
 
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCollectionReferenceReadRelationships(t *testing.T) {
+	tests := []struct {
+		name          string
+		relationships string
+		wantRemoved   bool
+	}{
+		{name: "both", relationships: `{"workspace":{"data":{"type":"workspace","id":"ws"}},"collection":{"data":{"type":"collection","id":"col"}}}`},
+		{name: "workspace deleted", relationships: `{"workspace":{"data":null},"collection":{"data":{"type":"collection","id":"col"}}}`, wantRemoved: true},
+		{name: "collection deleted", relationships: `{"workspace":{"data":{"type":"workspace","id":"ws"}},"collection":{"data":null}}`, wantRemoved: true},
+		{name: "relationships missing", relationships: `{}`, wantRemoved: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"data":{"type":"reference","id":"ref","attributes":{"description":"shared"},"relationships":` + test.relationships + `}}`))
+			}))
+			defer server.Close()
+
+			r := NewCollectionReferenceResource()
+			configureTestResource(t, r, server)
+
+			state := newTestState(t, r, map[string]string{"id": "ref", "organization_id": "org", "collection_id": "col", "workspace_id": "ws"})
+			resp := resource.ReadResponse{State: state}
+			r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read diagnostics: %v", resp.Diagnostics)
+			}
+			if removed := resp.State.Raw.IsNull(); removed != test.wantRemoved {
+				t.Fatalf("Read removed the reference = %t, want %t", removed, test.wantRemoved)
+			}
+			if test.wantRemoved {
+				return
+			}
+
+			var workspaceId, collectionId types.String
+			resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("workspace_id"), &workspaceId)...)
+			resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("collection_id"), &collectionId)...)
+			if workspaceId.ValueString() != "ws" || collectionId.ValueString() != "col" {
+				t.Errorf("Read set workspace_id %s and collection_id %s, want ws and col", workspaceId, collectionId)
+			}
+		})
+	}
+}
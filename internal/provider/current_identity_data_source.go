@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	tokenTypeTeam             = "team"
+	tokenTypePersonal         = "personal"
+	tokenTypeIdentityProvider = "identity_provider"
+
+	// terrakubeTokenIssuer is the issuer of the tokens generated by the Terrakube API.
+	terrakubeTokenIssuer = "Terrakube"
+)
+
+var (
+	_ datasource.DataSource              = &CurrentIdentityDataSource{}
+	_ datasource.DataSourceWithConfigure = &CurrentIdentityDataSource{}
+)
+
+type CurrentIdentityDataSourceModel struct {
+	Subject       types.String                                 `tfsdk:"subject"`
+	TokenType     types.String                                 `tfsdk:"token_type"`
+	ExpiresAt     types.String                                 `tfsdk:"expires_at"`
+	Organizations []CurrentIdentityDataSourceOrganizationModel `tfsdk:"organizations"`
+}
+
+type CurrentIdentityDataSourceOrganizationModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
+
+type CurrentIdentityDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewCurrentIdentityDataSource() datasource.DataSource {
+	return &CurrentIdentityDataSource{}
+}
+
+func (d *CurrentIdentityDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Current Identity Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	ctx = tflog.SetField(ctx, "endpoint", d.endpoint)
+	ctx = tflog.SetField(ctx, "token", d.token)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "token")
+	tflog.Info(ctx, "Current Identity Data Source configured")
+}
+
+func (d *CurrentIdentityDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_current_identity"
+}
+
+func (d *CurrentIdentityDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Identity of the token used by the provider and the organizations it can read. The token itself is never exposed.",
+		Attributes: map[string]schema.Attribute{
+			"subject": schema.StringAttribute{
+				Computed:    true,
+				Description: "Subject of the token, the user or team the token was issued for",
+			},
+			"token_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "Kind of token, `team` or `personal` for tokens generated by Terrakube and `identity_provider` for tokens issued by the identity provider",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "Expiration date of the token in RFC 3339 format, null when the token doesn't expire",
+			},
+			"organizations": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Organizations the token can read, sorted by name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Organization ID",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Organization name",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *CurrentIdentityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state CurrentIdentityDataSourceModel
+
+	// Terrakube has no introspection endpoint, the identity is read from the token claims
+	claims, err := helpers.GetClaimsFromToken(d.token)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read token claims", fmt.Sprintf("The provider token is not a valid JWT: %s", err))
+		return
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		subject, _ = claims["email"].(string)
+	}
+	state.Subject = types.StringValue(subject)
+	state.TokenType = types.StringValue(tokenTypeFromClaims(claims))
+
+	state.ExpiresAt = types.StringNull()
+	if exp, ok := claims["exp"].(float64); ok {
+		state.ExpiresAt = types.StringValue(time.Unix(int64(exp), 0).UTC().Format(time.RFC3339))
+	}
+
+	organizations, err := d.getOrganizations(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organizations", err.Error())
+		return
+	}
+
+	state.Organizations = []CurrentIdentityDataSourceOrganizationModel{}
+	for _, organization := range organizations {
+		state.Organizations = append(state.Organizations, CurrentIdentityDataSourceOrganizationModel{
+			ID:   types.StringValue(organization.ID),
+			Name: types.StringValue(organization.Name),
+		})
+	}
+
+	sort.SliceStable(state.Organizations, func(i, j int) bool {
+		return state.Organizations[i].Name.ValueString() < state.Organizations[j].Name.ValueString()
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// tokenTypeFromClaims tells team and personal tokens generated by Terrakube
+// apart from the tokens issued by the identity provider.
+func tokenTypeFromClaims(claims map[string]interface{}) string {
+	issuer, _ := claims["iss"].(string)
+	if issuer != terrakubeTokenIssuer {
+		return tokenTypeIdentityProvider
+	}
+
+	subject, _ := claims["sub"].(string)
+	if strings.HasSuffix(subject, "(Team Token)") {
+		return tokenTypeTeam
+	}
+
+	return tokenTypePersonal
+}
+
+func (d *CurrentIdentityDataSource) getOrganizations(ctx context.Context) ([]*client.OrganizationEntity, error) {
+	request, err := http.NewRequest(http.MethodGet, apiURL(d.endpoint, "organization"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating organization request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error executing organization request: %w", err)
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading organization response body: %w", err)
+	}
+
+	tflog.Debug(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+
+	if err = client.CheckResponse(response, bodyResponse); err != nil {
+		return nil, err
+	}
+
+	items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), reflect.TypeOf(new(client.OrganizationEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal organizations: %w", err)
+	}
+
+	organizations := make([]*client.OrganizationEntity, 0, len(items))
+	for _, item := range items {
+		organizations = append(organizations, item.(*client.OrganizationEntity))
+	}
+
+	return organizations, nil
+}
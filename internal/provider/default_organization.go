@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// organizationIdAttribute returns the organization_id attribute of the
+// resources that belong to an organization. It can be left out of the
+// resource configuration when the provider organization_id is set, the value
+// is then planned by setDefaultOrganization.
+func organizationIdAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: "Terrakube organization id, defaults to the provider `organization_id`",
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// setDefaultOrganization plans organization_id to the provider organization
+// when the resource configuration doesn't set it. Changing the provider
+// organization replaces the resource, like changing organization_id does.
+// Plan modifiers of the schema can't read the provider configuration, it is
+// called from ModifyPlan.
+func setDefaultOrganization(ctx context.Context, defaultOrganizationId string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Do nothing if it's destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var configured types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("organization_id"), &configured)...)
+	if resp.Diagnostics.HasError() || !configured.IsNull() {
+		return
+	}
+
+	if defaultOrganizationId == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("organization_id"),
+			"Missing organization id",
+			"organization_id must be set on the resource when the provider organization_id is not set.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("organization_id"), defaultOrganizationId)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var current types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("organization_id"), &current)...)
+	if current.ValueString() != defaultOrganizationId {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("organization_id"))
+	}
+}
+
+// checkOrganizationAccess reads the organization with the provider token, so
+// a token of another organization fails when the provider is configured
+// instead of on the first resource.
+func checkOrganizationAccess(httpClient *http.Client, endpoint string, token string, organizationId string) error {
+	request, err := http.NewRequest(http.MethodGet, apiURL(endpoint, "organization", url.PathEscape(organizationId)), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	return client.CheckResponse(response, bodyResponse)
+}
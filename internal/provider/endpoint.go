@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// normalizeEndpoint returns the endpoint the API URLs are built from, each of
+// them being the endpoint followed by /api/v1/.... The endpoint may have a path
+// prefix when Terrakube is served behind a gateway. Duplicated and trailing
+// slashes, as well as a trailing /api/v1, are removed so the URLs never hold
+// an empty path segment.
+func normalizeEndpoint(endpoint string) (*url.URL, error) {
+	parsedEndpoint, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	if parsedEndpoint.Scheme != "http" && parsedEndpoint.Scheme != "https" {
+		return nil, errors.New("the scheme must be http or https")
+	}
+
+	if parsedEndpoint.Host == "" {
+		return nil, errors.New("the host is missing")
+	}
+
+	if parsedEndpoint.RawQuery != "" || parsedEndpoint.Fragment != "" {
+		return nil, errors.New("query and fragment are not supported")
+	}
+
+	prefix := strings.TrimSuffix(path.Clean("/"+parsedEndpoint.Path), "/")
+	prefix = strings.TrimSuffix(prefix, "/api/v1")
+	parsedEndpoint.Path = prefix
+	parsedEndpoint.RawPath = ""
+
+	return parsedEndpoint, nil
+}
+
+// endpointURL joins the escaped path segments to the normalized endpoint, the
+// segments taken from ids must be escaped with url.PathEscape.
+func endpointURL(endpoint string, parts ...string) string {
+	joined, err := url.JoinPath(endpoint, parts...)
+	if err != nil {
+		// The endpoint is checked by normalizeEndpoint when the provider is
+		// configured, it can only fail to parse in tests.
+		return strings.TrimSuffix(endpoint, "/") + "/" + strings.Join(parts, "/")
+	}
+
+	return joined
+}
+
+// apiURL returns the URL of an API path, for example
+// apiURL(endpoint, "organization", url.PathEscape(id), "workspace").
+func apiURL(endpoint string, parts ...string) string {
+	return endpointURL(endpoint, append([]string{"api", "v1"}, parts...)...)
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{name: "host", endpoint: "https://terrakube-api.example.com", want: "https://terrakube-api.example.com"},
+		{name: "trailing slash", endpoint: "https://terrakube-api.example.com/", want: "https://terrakube-api.example.com"},
+		{name: "spaces", endpoint: " https://terrakube-api.example.com ", want: "https://terrakube-api.example.com"},
+		{name: "port", endpoint: "http://localhost:8080", want: "http://localhost:8080"},
+		{name: "path prefix", endpoint: "https://tools.example.com/terrakube", want: "https://tools.example.com/terrakube"},
+		{name: "path prefix trailing slash", endpoint: "https://tools.example.com/terrakube/", want: "https://tools.example.com/terrakube"},
+		{name: "duplicated slashes", endpoint: "https://tools.example.com//terrakube//", want: "https://tools.example.com/terrakube"},
+		{name: "api suffix", endpoint: "https://terrakube-api.example.com/api/v1", want: "https://terrakube-api.example.com"},
+		{name: "api suffix trailing slash", endpoint: "https://tools.example.com/terrakube/api/v1/", want: "https://tools.example.com/terrakube"},
+		{name: "non http scheme", endpoint: "ftp://terrakube-api.example.com", wantErr: true},
+		{name: "no scheme", endpoint: "terrakube-api.example.com", wantErr: true},
+		{name: "query", endpoint: "https://terrakube-api.example.com?tenant=a", wantErr: true},
+		{name: "fragment", endpoint: "https://terrakube-api.example.com#api", wantErr: true},
+		{name: "missing host", endpoint: "https:///terrakube", wantErr: true},
+		{name: "unparsable", endpoint: "https://terrakube-api.example.com:port", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			endpoint, err := normalizeEndpoint(test.endpoint)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("normalizeEndpoint(%q) error = %v, want error %t", test.endpoint, err, test.wantErr)
+			}
+			if err == nil && endpoint.String() != test.want {
+				t.Errorf("normalizeEndpoint(%q) = %q, want %q", test.endpoint, endpoint, test.want)
+			}
+		})
+	}
+}
+
+func TestApiURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		parts    []string
+		want     string
+	}{
+		{name: "host", endpoint: "https://terrakube-api.example.com", parts: []string{"organization"}, want: "https://terrakube-api.example.com/api/v1/organization"},
+		{name: "path prefix", endpoint: "https://tools.example.com/terrakube", parts: []string{"organization", "org", "workspace"}, want: "https://tools.example.com/terrakube/api/v1/organization/org/workspace"},
+		{name: "trailing slash", endpoint: "https://tools.example.com/terrakube/", parts: []string{"organization"}, want: "https://tools.example.com/terrakube/api/v1/organization"},
+		{name: "escaped id", endpoint: "https://terrakube-api.example.com", parts: []string{"organization", "a%2Fb"}, want: "https://terrakube-api.example.com/api/v1/organization/a%2Fb"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := apiURL(test.endpoint, test.parts...); got != test.want {
+				t.Errorf("apiURL(%q, %q) = %q, want %q", test.endpoint, test.parts, got, test.want)
+			}
+		})
+	}
+}
+
+// The resources send their requests below the path prefix of the endpoint.
+func TestEndpointPathPrefix(t *testing.T) {
+	var requestPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"data":{"type":"module","id":"module","attributes":{"name":"vnet","provider":"azurerm","source":"https://github.com/org/vnet.git"}}}`))
+	}))
+	defer server.Close()
+
+	endpoint, err := normalizeEndpoint(server.URL + "/terrakube/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewModuleResource()
+	var configureResp resource.ConfigureResponse
+	r.(resource.ResourceWithConfigure).Configure(context.Background(), resource.ConfigureRequest{
+		ProviderData: &TerrakubeConnectionData{Endpoint: endpoint.String(), Token: "test-token", Client: server.Client(), WorkspaceVariables: newWorkspaceVariableCache()},
+	}, &configureResp)
+
+	state := newTestState(t, r, map[string]string{"id": "module", "organization_id": "org"})
+	resp := resource.ReadResponse{State: state}
+	r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read diagnostics: %v", resp.Diagnostics)
+	}
+	if want := "/terrakube/api/v1/organization/org/module/module"; requestPath != want {
+		t.Errorf("request path = %q, want %q", requestPath, want)
+	}
+}
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &JobDataSource{}
+	_ datasource.DataSourceWithConfigure = &JobDataSource{}
+)
+
+// jobStepPageSize is the number of steps requested per page when reading job steps.
+const jobStepPageSize = 50
+
+type JobDataSourceModel struct {
+	ID             types.String             `tfsdk:"id"`
+	OrganizationId types.String             `tfsdk:"organization_id"`
+	WorkspaceId    types.String             `tfsdk:"workspace_id"`
+	Status         types.String             `tfsdk:"status"`
+	TemplateId     types.String             `tfsdk:"template_id"`
+	CreatedDate    types.String             `tfsdk:"created_date"`
+	UpdatedDate    types.String             `tfsdk:"updated_date"`
+	Steps          []JobStepDataSourceModel `tfsdk:"steps"`
+}
+
+type JobStepDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	StepNumber types.Int32  `tfsdk:"step_number"`
+	Status     types.String `tfsdk:"status"`
+	Output     types.String `tfsdk:"output"`
+}
+
+type JobDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewJobDataSource() datasource.DataSource {
+	return &JobDataSource{}
+}
+
+func (d *JobDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Job Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	ctx = tflog.SetField(ctx, "endpoint", d.endpoint)
+	ctx = tflog.SetField(ctx, "token", d.token)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "token")
+	tflog.Info(ctx, "Job Data Source configured")
+}
+
+func (d *JobDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_job"
+}
+
+func (d *JobDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Read the status and step results of a job. Use `id` to read a specific job or `workspace_id` to read the latest job of a workspace.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Job Id",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("workspace_id")),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Terrakube organization id",
+			},
+			"workspace_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Terrakube workspace id, the latest job of the workspace is read",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Job status",
+			},
+			"template_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Template ID used by the job",
+			},
+			"created_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "Job creation date",
+			},
+			"updated_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "Job last update date, this is the completion date once the job finished",
+			},
+			"steps": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Job steps ordered by step number",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Step Id",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Step name",
+						},
+						"step_number": schema.Int32Attribute{
+							Computed:    true,
+							Description: "Step number",
+						},
+						"status": schema.StringAttribute{
+							Computed:    true,
+							Description: "Step status",
+						},
+						"output": schema.StringAttribute{
+							Computed:    true,
+							Description: "Step output reference",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *JobDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state JobDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var jobsURL string
+	if !state.WorkspaceId.IsNull() {
+		jobsURL = apiURL(d.endpoint, "organization", state.OrganizationId.ValueString(), "job") + fmt.Sprintf("?filter[job]=workspace.id==%s&sort=-id&page[size]=1", state.WorkspaceId.ValueString())
+	} else {
+		jobsURL = apiURL(d.endpoint, "organization", state.OrganizationId.ValueString(), "job") + fmt.Sprintf("?filter[job]=id==%s", state.ID.ValueString())
+	}
+
+	jobs, err := d.getMany(ctx, jobsURL, reflect.TypeOf(new(client.JobEntity)))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading job", fmt.Sprintf("Error reading job: %s", err))
+		return
+	}
+
+	if len(jobs) == 0 {
+		resp.Diagnostics.AddError("Job not found", fmt.Sprintf("No job found in organization %s for id %q and workspace id %q", state.OrganizationId.ValueString(), state.ID.ValueString(), state.WorkspaceId.ValueString()))
+		return
+	}
+
+	job, _ := jobs[0].(*client.JobEntity)
+	state.ID = types.StringValue(job.ID)
+	state.Status = types.StringValue(job.Status)
+	state.TemplateId = types.StringValue(job.TemplateId)
+	state.CreatedDate = types.StringValue(job.CreatedDate)
+	state.UpdatedDate = types.StringValue(job.UpdatedDate)
+
+	state.Steps = []JobStepDataSourceModel{}
+	for page := 1; ; page++ {
+		stepsURL := apiURL(d.endpoint, "organization", state.OrganizationId.ValueString(), "job", job.ID, "step") + fmt.Sprintf("?sort=stepNumber&page[number]=%d&page[size]=%d", page, jobStepPageSize)
+		steps, err := d.getMany(ctx, stepsURL, reflect.TypeOf(new(client.JobStepEntity)))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading job steps", fmt.Sprintf("Error reading job steps: %s", err))
+			return
+		}
+
+		for _, step := range steps {
+			data, ok := step.(*client.JobStepEntity)
+			if !ok {
+				continue
+			}
+			state.Steps = append(state.Steps, JobStepDataSourceModel{
+				ID:         types.StringValue(data.ID),
+				Name:       types.StringValue(data.Name),
+				StepNumber: types.Int32Value(data.StepNumber),
+				Status:     types.StringValue(data.Status),
+				Output:     types.StringValue(data.Output),
+			})
+		}
+
+		if len(steps) < jobStepPageSize {
+			break
+		}
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (d *JobDataSource) getMany(ctx context.Context, apiURL string, t reflect.Type) ([]interface{}, error) {
+	request, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating job request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error executing job request: %w", err)
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading job response body: %w", err)
+	}
+
+	tflog.Debug(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response status: %s, response body: %s", response.Status, string(bodyResponse))
+	}
+
+	items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), t)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal payload, error: %w, response body: %s", err, string(bodyResponse))
+	}
+
+	return items, nil
+}
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// privateStateReader is implemented by the private state of the resource requests.
+type privateStateReader interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// privateStateWriter is implemented by the private state of the resource responses.
+type privateStateWriter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// managedKeys are the keys of the entries of a collection attribute, like the
+// environment variables or the access of a workspace, created by the provider.
+// They are kept in the private state under privateKey so the entries created
+// outside Terraform are told apart, see reconcileKeys.
+type managedKeys struct {
+	privateKey string
+}
+
+// Read returns the managed keys. tracked is false when they were never
+// written, for example in a state created before the attribute tracked them.
+func (m managedKeys) Read(ctx context.Context, private privateStateReader) (keys []string, tracked bool, diags diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, m.privateKey)
+	if diags.HasError() || len(value) == 0 {
+		return nil, false, diags
+	}
+
+	if err := json.Unmarshal(value, &keys); err != nil {
+		diags.AddError("Error reading managed keys", fmt.Sprintf("Unable to read %s from the private state: %s", m.privateKey, err))
+	}
+
+	return keys, true, diags
+}
+
+// Write saves the managed keys.
+func (m managedKeys) Write(ctx context.Context, private privateStateWriter, keys []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	sorted := append([]string{}, keys...)
+	sort.Strings(sorted)
+	value, err := json.Marshal(sorted)
+	if err != nil {
+		diags.AddError("Error writing managed keys", fmt.Sprintf("Unable to write %s to the private state: %s", m.privateKey, err))
+		return diags
+	}
+
+	return private.SetKey(ctx, m.privateKey, value)
+}
+
+// keyReconciliation is what reconcileKeys decided for each key.
+type keyReconciliation struct {
+	// Create are the keys of the configuration that don't exist.
+	Create []string
+	// Update are the keys of the configuration that exist and are owned.
+	Update []string
+	// Conflict are the keys of the configuration that exist but aren't owned.
+	Conflict []string
+	// Delete are the owned keys that exist but are missing from the configuration.
+	Delete []string
+}
+
+// reconcileKeys compares the keys of a collection attribute with the keys
+// that exist on the server. Every collection attribute follows the same rule:
+// an existing key is owned when it is managed, or when exclusive is set, in
+// which case the server set is made to match the configuration exactly. A key
+// that exists but isn't owned is a conflict, it belongs to another
+// configuration or was created outside Terraform. Keys are returned sorted.
+func reconcileKeys(desired []string, existing []string, managed []string, exclusive bool) keyReconciliation {
+	isDesired := map[string]bool{}
+	for _, key := range desired {
+		isDesired[key] = true
+	}
+	exists := map[string]bool{}
+	for _, key := range existing {
+		exists[key] = true
+	}
+	isManaged := map[string]bool{}
+	for _, key := range managed {
+		isManaged[key] = true
+	}
+
+	var reconciliation keyReconciliation
+	for key := range isDesired {
+		switch {
+		case !exists[key]:
+			reconciliation.Create = append(reconciliation.Create, key)
+		case isManaged[key] || exclusive:
+			reconciliation.Update = append(reconciliation.Update, key)
+		default:
+			reconciliation.Conflict = append(reconciliation.Conflict, key)
+		}
+	}
+	for key := range exists {
+		if !isDesired[key] && (isManaged[key] || exclusive) {
+			reconciliation.Delete = append(reconciliation.Delete, key)
+		}
+	}
+
+	sort.Strings(reconciliation.Create)
+	sort.Strings(reconciliation.Update)
+	sort.Strings(reconciliation.Conflict)
+	sort.Strings(reconciliation.Delete)
+
+	return reconciliation
+}
+
+// ownedKeys returns the existing keys that are owned, the ones read back into
+// a collection attribute: the managed keys, or every key when exclusive is set.
+func ownedKeys(existing []string, managed []string, exclusive bool) []string {
+	isManaged := map[string]bool{}
+	for _, key := range managed {
+		isManaged[key] = true
+	}
+
+	owned := []string{}
+	for _, key := range existing {
+		if exclusive || isManaged[key] {
+			owned = append(owned, key)
+		}
+	}
+	sort.Strings(owned)
+
+	return owned
+}
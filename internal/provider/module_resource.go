@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -12,6 +11,12 @@ import (
 	"net/http"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -23,11 +28,13 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ModuleResource{}
 var _ resource.ResourceWithImportState = &ModuleResource{}
+var _ resource.ResourceWithModifyPlan = &ModuleResource{}
 
 type ModuleResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type ModuleResourceModel struct {
@@ -41,8 +48,17 @@ type ModuleResourceModel struct {
 	SshId          types.String `tfsdk:"ssh_id"`
 	TagPrefix      types.String `tfsdk:"tag_prefix"`
 	Folder         types.String `tfsdk:"folder"`
+	ValidateSource types.Bool   `tfsdk:"validate_source_on_create"`
+	ValidateWait   types.Int64  `tfsdk:"validate_source_timeout_seconds"`
 }
 
+const (
+	// moduleVersionsPollInterval is the first delay between two checks of the module versions.
+	moduleVersionsPollInterval = 5 * time.Second
+	// moduleVersionsDefaultTimeout is used when validate_source_timeout_seconds is not set.
+	moduleVersionsDefaultTimeout = 60 * time.Second
+)
+
 func NewModuleResource() resource.Resource {
 	return &ModuleResource{}
 }
@@ -64,13 +80,13 @@ func (r *ModuleResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"name": schema.StringAttribute{
 				Required:    true,
-				Description: "Module name",
+				Description: "Module name. Changing it replaces the module, the registry source of the module changes.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"description": schema.StringAttribute{
 				Required:    true,
@@ -78,7 +94,10 @@ func (r *ModuleResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 			"provider_name": schema.StringAttribute{
 				Required:    true,
-				Description: "Module provider name. Example: azurerm, google, aws, etc",
+				Description: "Module provider name. Example: azurerm, google, aws, etc. Changing it replaces the module, the registry source of the module changes.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"source": schema.StringAttribute{
 				Required:    true,
@@ -100,6 +119,18 @@ func (r *ModuleResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Optional:    true,
 				Description: "Folder to look into for module files. Need to preprend a / and append a / to work properly.",
 			},
+			"validate_source_on_create": schema.BoolAttribute{
+				Optional: true,
+				Description: "Wait after creating the module until Terrakube discovers at least one version in the source repository. " +
+					"A warning is shown when no version is found, usually because of wrong credentials, source or tag_prefix. Defaults to `false`.",
+			},
+			"validate_source_timeout_seconds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Seconds to wait for the module versions when `validate_source_on_create` is true. Defaults to `60`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
 		},
 	}
 }
@@ -119,20 +150,11 @@ func (r *ModuleResource) Configure(ctx context.Context, req resource.ConfigureRe
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Module resource", map[string]any{"success": true})
 }
@@ -181,13 +203,13 @@ func (r *ModuleResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Body Request: %s", out.String()))
+	tflog.Debug(ctx, fmt.Sprintf("Body Request: %s", helpers.RedactPayload(out.String())))
 
-	moduleRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/module", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	moduleRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "module"), strings.NewReader(out.String()))
 	moduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	moduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating module resource request: %s", err))
 		return
 	}
 
@@ -229,6 +251,10 @@ func (r *ModuleResource) Create(ctx context.Context, req resource.CreateRequest,
 		plan.TagPrefix = types.StringPointerValue(newModule.TagPrefix)
 	}
 
+	if plan.ValidateSource.ValueBool() {
+		resp.Diagnostics.Append(r.validateSource(ctx, plan)...)
+	}
+
 	tflog.Info(ctx, "Module Resource Created", map[string]any{"success": true})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -242,11 +268,11 @@ func (r *ModuleResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	moduleRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/module/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	moduleRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "module", state.ID.ValueString()), nil)
 	moduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	moduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating module resource request: %s", err))
 		return
 	}
 
@@ -260,6 +286,11 @@ func (r *ModuleResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if err != nil {
 		tflog.Error(ctx, "Error reading module resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, moduleResponse, bodyResponse, "module") {
+		return
+	}
+
 	module := &client.ModuleEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -285,10 +316,13 @@ func (r *ModuleResource) Read(ctx context.Context, req resource.ReadRequest, res
 		state.TagPrefix = types.StringPointerValue(module.TagPrefix)
 	}
 
+	// Connections detached outside Terraform must not stay in state
+	state.VcsId = types.StringNull()
 	if module.Vcs != nil {
 		state.VcsId = types.StringValue(module.Vcs.ID)
 	}
 
+	state.SshId = types.StringNull()
 	if module.Ssh != nil {
 		state.SshId = types.StringValue(module.Ssh.ID)
 	}
@@ -349,11 +383,11 @@ func (r *ModuleResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	moduleRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/module/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	moduleRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "module", state.ID.ValueString()), strings.NewReader(out.String()))
 	moduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	moduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating module resource request: %s", err))
 		return
 	}
 
@@ -370,11 +404,11 @@ func (r *ModuleResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	moduleRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/module/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	moduleRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "module", state.ID.ValueString()), nil)
 	moduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	moduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating module resource request: %s", err))
 		return
 	}
 
@@ -422,20 +456,114 @@ func (r *ModuleResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/module/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	reqOrg, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", data.OrganizationId.ValueString(), "module", data.ID.ValueString()), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating team resource request: %s", err))
+		resp.Diagnostics.AddError("Error creating module resource request", fmt.Sprintf("Error creating module resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	response, err := r.client.Do(reqOrg)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing module resource request", fmt.Sprintf("Error executing team resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing module resource request", fmt.Sprintf("Error executing module resource request: %s", err))
+		return
+	}
+	defer response.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(response.Body)
+	if err = checkDeleteResponse(response, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing module resource request", fmt.Sprintf("Error executing module resource request: %s", err))
+		return
+	}
+}
+
+func (r *ModuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
+
+	// Nothing is renamed on create and destroy
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state ModuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Name.IsUnknown() || plan.ProviderName.IsUnknown() || (plan.Name.Equal(state.Name) && plan.ProviderName.Equal(state.ProviderName)) {
 		return
 	}
+
+	resp.Diagnostics.AddWarning(
+		"Module registry source changes",
+		fmt.Sprintf("Module %s/%s is replaced by module %s/%s, configurations consuming it must update their source to <registry_hostname>/<organization>/%s/%s. The versions of the current module are not moved to the new one.",
+			state.Name.ValueString(), state.ProviderName.ValueString(), plan.Name.ValueString(), plan.ProviderName.ValueString(), plan.Name.ValueString(), plan.ProviderName.ValueString()),
+	)
 }
 
 func (r *ModuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// validateSource waits for Terrakube to discover the versions of a new module
+// and returns a warning when none were found before the timeout. The module is
+// created either way, a source without tags is not an error.
+func (r *ModuleResource) validateSource(ctx context.Context, plan ModuleResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	timeout := moduleVersionsDefaultTimeout
+	if !plan.ValidateWait.IsNull() {
+		timeout = time.Duration(plan.ValidateWait.ValueInt64()) * time.Second
+	}
+
+	err := helpers.Poll(ctx, moduleVersionsPollInterval, timeout, func(ctx context.Context) (helpers.PollResult, error) {
+		moduleRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "module", plan.ID.ValueString()), nil)
+		if err != nil {
+			return helpers.PollFatal, err
+		}
+		moduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+		moduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+		moduleResponse, err := r.client.Do(moduleRequest)
+		if err != nil {
+			return helpers.PollRetry, err
+		}
+		defer moduleResponse.Body.Close()
+
+		bodyResponse, err := io.ReadAll(moduleResponse.Body)
+		if err != nil {
+			return helpers.PollRetry, err
+		}
+
+		if err = client.CheckResponse(moduleResponse, bodyResponse); err != nil {
+			if client.IsUnauthorized(err) || client.IsNotFound(err) {
+				return helpers.PollFatal, err
+			}
+			return helpers.PollRetry, err
+		}
+
+		module := &client.ModuleEntity{}
+		if err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), module); err != nil {
+			return helpers.PollFatal, err
+		}
+
+		if len(module.Versions) == 0 {
+			tflog.Info(ctx, fmt.Sprintf("Waiting for versions of module %s", plan.ID.ValueString()))
+			return helpers.PollRetry, nil
+		}
+
+		tflog.Info(ctx, "Module versions found", map[string]any{"versions": len(module.Versions)})
+		return helpers.PollDone, nil
+	})
+
+	if err != nil {
+		diags.AddWarning(
+			"Module source not validated",
+			fmt.Sprintf("No version of module %s was found in %s after %s, check the source, the vcs_id or ssh_id credentials and the tag_prefix: %s", plan.Name.ValueString(), plan.Source.ValueString(), timeout, err),
+		)
+	}
+
+	return diags
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestModuleReadConnections(t *testing.T) {
+	tests := []struct {
+		name          string
+		relationships string
+		wantVcsId     types.String
+		wantSshId     types.String
+	}{
+		{name: "vcs", relationships: `{"vcs":{"data":{"type":"vcs","id":"vcs-new"}},"ssh":{"data":null}}`, wantVcsId: types.StringValue("vcs-new"), wantSshId: types.StringNull()},
+		{name: "ssh", relationships: `{"vcs":{"data":null},"ssh":{"data":{"type":"ssh","id":"ssh-new"}}}`, wantVcsId: types.StringNull(), wantSshId: types.StringValue("ssh-new")},
+		{name: "detached", relationships: `{"vcs":{"data":null},"ssh":{"data":null}}`, wantVcsId: types.StringNull(), wantSshId: types.StringNull()},
+		{name: "relationships missing", relationships: `{}`, wantVcsId: types.StringNull(), wantSshId: types.StringNull()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"data":{"type":"module","id":"module","attributes":{"name":"vnet","provider":"azurerm","source":"https://github.com/org/vnet.git"},"relationships":` + test.relationships + `}}`))
+			}))
+			defer server.Close()
+
+			r := NewModuleResource()
+			configureTestResource(t, r, server)
+
+			state := newTestState(t, r, map[string]string{"id": "module", "organization_id": "org", "vcs_id": "vcs-old", "ssh_id": "ssh-old"})
+			resp := resource.ReadResponse{State: state}
+			r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read diagnostics: %v", resp.Diagnostics)
+			}
+
+			var vcsId, sshId types.String
+			resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("vcs_id"), &vcsId)...)
+			resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("ssh_id"), &sshId)...)
+			if !vcsId.Equal(test.wantVcsId) {
+				t.Errorf("vcs_id = %s, want %s", vcsId, test.wantVcsId)
+			}
+			if !sshId.Equal(test.wantSshId) {
+				t.Errorf("ssh_id = %s, want %s", sshId, test.wantSshId)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -12,6 +11,7 @@ import (
 	"net/http"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -23,11 +23,13 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &AgentResource{}
 var _ resource.ResourceWithImportState = &AgentResource{}
+var _ resource.ResourceWithModifyPlan = &AgentResource{}
 
 type AgentResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type AgentResourceModel struct {
@@ -59,10 +61,7 @@ func (r *AgentResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Self hosted agent name",
@@ -94,20 +93,11 @@ func (r *AgentResource) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Self Hosted Agent resource", map[string]any{"success": true})
 }
@@ -135,9 +125,9 @@ func (r *AgentResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Body Request: %s", out.String()))
+	tflog.Debug(ctx, fmt.Sprintf("Body Request: %s", helpers.RedactPayload(out.String())))
 
-	agentRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/agent", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	agentRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "agent"), strings.NewReader(out.String()))
 	agentRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	agentRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -187,7 +177,7 @@ func (r *AgentResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	agentRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/agent/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	agentRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "agent", state.ID.ValueString()), nil)
 	agentRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	agentRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -205,6 +195,11 @@ func (r *AgentResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	if err != nil {
 		tflog.Error(ctx, "Error reading self hosted agent resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, agentResponse, bodyResponse, "self hosted agent") {
+		return
+	}
+
 	agent := &client.AgentEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -256,7 +251,7 @@ func (r *AgentResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	agentRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/agent/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	agentRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "agent", state.ID.ValueString()), strings.NewReader(out.String()))
 	agentRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	agentRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -277,7 +272,7 @@ func (r *AgentResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	agentRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/agent/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	agentRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "agent", state.ID.ValueString()), nil)
 	agentRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	agentRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -323,18 +318,29 @@ func (r *AgentResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/agent/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	reqOrg, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", data.OrganizationId.ValueString(), "agent", data.ID.ValueString()), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating self hosted agent resource request", fmt.Sprintf("Error creating self hosted agent resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	response, err := r.client.Do(reqOrg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing self hosted agent resource request", fmt.Sprintf("Error executing self hosted agent resource request: %s", err))
 		return
 	}
+	defer response.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(response.Body)
+	if err = checkDeleteResponse(response, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing self hosted agent resource request", fmt.Sprintf("Error executing self hosted agent resource request: %s", err))
+		return
+	}
+}
+
+func (r *AgentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
 }
 
 func (r *AgentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ModuleSourceFunction{}
+
+type ModuleSourceFunction struct{}
+
+func NewModuleSourceFunction() function.Function {
+	return &ModuleSourceFunction{}
+}
+
+func (f *ModuleSourceFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "module_source"
+}
+
+func (f *ModuleSourceFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Build the registry source of a module",
+		Description: "Returns the source string used to consume a module of the Terrakube private registry, `<registry_hostname>/<organization>/<name>/<provider>`. When the registry hostname is omitted the source is returned without it.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "organization",
+				Description: "Organization name",
+			},
+			function.StringParameter{
+				Name:        "name",
+				Description: "Module name",
+			},
+			function.StringParameter{
+				Name:        "provider",
+				Description: "Module provider",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:        "registry_hostname",
+			Description: "Optional registry hostname, for example `terrakube-reg.minikube.net`",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ModuleSourceFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var organization, name, provider string
+	var registry []string
+
+	resp.Error = req.Arguments.Get(ctx, &organization, &name, &provider, &registry)
+	if resp.Error != nil {
+		return
+	}
+
+	if len(registry) > 1 {
+		resp.Error = function.NewArgumentFuncError(3, "Only one registry hostname can be set")
+		return
+	}
+
+	for position, value := range []string{organization, name, provider} {
+		if strings.TrimSpace(value) == "" {
+			resp.Error = function.NewArgumentFuncError(int64(position), "Value must not be empty")
+			return
+		}
+	}
+
+	parts := []string{organization, name, provider}
+	if len(registry) == 1 {
+		hostname := strings.TrimSuffix(registry[0], "/")
+		hostname = strings.TrimPrefix(strings.TrimPrefix(hostname, "https://"), "http://")
+		if hostname == "" {
+			resp.Error = function.NewArgumentFuncError(3, "Registry hostname must not be empty")
+			return
+		}
+		parts = append([]string{hostname}, parts...)
+	}
+
+	resp.Error = resp.Result.Set(ctx, strings.Join(parts, "/"))
+}
@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource              = &ModulesDataSource{}
+	_ datasource.DataSourceWithConfigure = &ModulesDataSource{}
+)
+
+type ModulesDataSourceModel struct {
+	OrganizationId types.String                   `tfsdk:"organization_id"`
+	Name           types.String                   `tfsdk:"name"`
+	ProviderName   types.String                   `tfsdk:"provider_name"`
+	Source         types.String                   `tfsdk:"source"`
+	Modules        []ModulesDataSourceModuleModel `tfsdk:"modules"`
+}
+
+type ModulesDataSourceModuleModel struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	ProviderName types.String `tfsdk:"provider_name"`
+	Source       types.String `tfsdk:"source"`
+	Folder       types.String `tfsdk:"folder"`
+	TagPrefix    types.String `tfsdk:"tag_prefix"`
+}
+
+type ModulesDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewModulesDataSource() datasource.DataSource {
+	return &ModulesDataSource{}
+}
+
+func (d *ModulesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Modules Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	ctx = tflog.SetField(ctx, "endpoint", d.endpoint)
+	ctx = tflog.SetField(ctx, "token", d.token)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "token")
+	tflog.Info(ctx, "Modules Data Source configured")
+}
+
+func (d *ModulesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_modules"
+}
+
+func (d *ModulesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the modules of the private registry of an organization, optionally filtered by name, provider or source.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Organization ID",
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return modules with this name",
+			},
+			"provider_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return modules for this provider",
+			},
+			"source": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return modules using this source repository",
+			},
+			"modules": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Modules matching the filters, sorted by name and provider",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Module ID",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Module name",
+						},
+						"provider_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Module provider",
+						},
+						"source": schema.StringAttribute{
+							Computed:    true,
+							Description: "Module source repository",
+						},
+						"folder": schema.StringAttribute{
+							Computed:    true,
+							Description: "Folder where the module is located inside the repository",
+						},
+						"tag_prefix": schema.StringAttribute{
+							Computed:    true,
+							Description: "Tag prefix used for the module versions",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ModulesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ModulesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var filters []string
+	if !state.Name.IsNull() {
+		filters = append(filters, "name=="+helpers.RsqlQuote(state.Name.ValueString()))
+	}
+	if !state.ProviderName.IsNull() {
+		filters = append(filters, "provider=="+helpers.RsqlQuote(state.ProviderName.ValueString()))
+	}
+	if !state.Source.IsNull() {
+		filters = append(filters, "source=="+helpers.RsqlQuote(state.Source.ValueString()))
+	}
+
+	modulesURL := apiURL(d.endpoint, "organization", state.OrganizationId.ValueString(), "module")
+
+	modules, err := d.getModules(ctx, modulesURL, filters)
+	if err != nil && len(filters) > 0 {
+		tflog.Warn(ctx, "Filtered module request failed, filtering modules on the client", map[string]any{"error": err.Error()})
+		modules, err = d.getModules(ctx, modulesURL, nil)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading modules", err.Error())
+		return
+	}
+
+	state.Modules = []ModulesDataSourceModuleModel{}
+	for _, module := range modules {
+		if !state.Name.IsNull() && module.Name != state.Name.ValueString() {
+			continue
+		}
+		if !state.ProviderName.IsNull() && module.Provider != state.ProviderName.ValueString() {
+			continue
+		}
+		if !state.Source.IsNull() && module.Source != state.Source.ValueString() {
+			continue
+		}
+
+		state.Modules = append(state.Modules, ModulesDataSourceModuleModel{
+			ID:           types.StringValue(module.ID),
+			Name:         types.StringValue(module.Name),
+			ProviderName: types.StringValue(module.Provider),
+			Source:       types.StringValue(module.Source),
+			Folder:       types.StringPointerValue(module.Folder),
+			TagPrefix:    types.StringPointerValue(module.TagPrefix),
+		})
+	}
+
+	sort.SliceStable(state.Modules, func(i, j int) bool {
+		if state.Modules[i].Name.ValueString() != state.Modules[j].Name.ValueString() {
+			return state.Modules[i].Name.ValueString() < state.Modules[j].Name.ValueString()
+		}
+		return state.Modules[i].ProviderName.ValueString() < state.Modules[j].ProviderName.ValueString()
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *ModulesDataSource) getModules(ctx context.Context, apiURL string, filters []string) ([]*client.ModuleEntity, error) {
+	if len(filters) > 0 {
+		apiURL = fmt.Sprintf("%s?filter[module]=%s", apiURL, url.QueryEscape(strings.Join(filters, ";")))
+	}
+
+	request, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating module request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error executing module request: %w", err)
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading module response body: %w", err)
+	}
+
+	tflog.Debug(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response status: %s, response body: %s", response.Status, string(bodyResponse))
+	}
+
+	items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), reflect.TypeOf(new(client.ModuleEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal payload, error: %w, response body: %s", err, string(bodyResponse))
+	}
+
+	modules := make([]*client.ModuleEntity, 0, len(items))
+	for _, item := range items {
+		modules = append(modules, item.(*client.ModuleEntity))
+	}
+
+	return modules, nil
+}
@@ -0,0 +1,14 @@
+package provider
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+// optionalStringValue returns the value read from the API for an optional
+// attribute. The API returns an empty string when the attribute was never set,
+// which is kept null when the attribute is null in the plan or state.
+func optionalStringValue(current types.String, value string) types.String {
+	if value == "" && current.IsNull() {
+		return types.StringNull()
+	}
+
+	return types.StringValue(value)
+}
@@ -3,13 +3,13 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
 	"io"
 	"net/http"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
 
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -25,11 +25,13 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &CollectionResource{}
 var _ resource.ResourceWithImportState = &CollectionResource{}
+var _ resource.ResourceWithModifyPlan = &CollectionResource{}
 
 type CollectionResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type CollectionResourceModel struct {
@@ -60,10 +62,7 @@ func (r *CollectionResource) Schema(ctx context.Context, req resource.SchemaRequ
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Collection name",
@@ -98,20 +97,11 @@ func (r *CollectionResource) Configure(ctx context.Context, req resource.Configu
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Collection resource", map[string]any{"success": true})
 }
@@ -139,9 +129,9 @@ func (r *CollectionResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": strings.NewReader(out.String())})
+	tflog.Debug(ctx, "Body Request", map[string]any{"bodyRequest": helpers.RedactPayload(out.String())})
 
-	collectionRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/collection", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	collectionRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "collection"), strings.NewReader(out.String()))
 	collectionRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -189,7 +179,7 @@ func (r *CollectionResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	collectionRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	collectionRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "collection", state.ID.ValueString()), nil)
 	collectionRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -207,6 +197,11 @@ func (r *CollectionResource) Read(ctx context.Context, req resource.ReadRequest,
 	if err != nil {
 		tflog.Error(ctx, "Error reading collection resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, collectionResponse, bodyResponse, "organization collection") {
+		return
+	}
+
 	collection := &client.CollectionEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -258,7 +253,7 @@ func (r *CollectionResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
-	collectionRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	collectionRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "collection", state.ID.ValueString()), strings.NewReader(out.String()))
 	collectionRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -279,7 +274,7 @@ func (r *CollectionResource) Update(ctx context.Context, req resource.UpdateRequ
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	collectionRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	collectionRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "collection", state.ID.ValueString()), nil)
 	collectionRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	collectionRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -326,18 +321,29 @@ func (r *CollectionResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/collection/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	reqOrg, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", data.OrganizationId.ValueString(), "collection", data.ID.ValueString()), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating collection resource request", fmt.Sprintf("Error creating collection resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	response, err := r.client.Do(reqOrg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing collection resource request", fmt.Sprintf("Error executing collection resource request: %s", err))
 		return
 	}
+	defer response.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(response.Body)
+	if err = checkDeleteResponse(response, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing collection resource request", fmt.Sprintf("Error executing collection resource request: %s", err))
+		return
+	}
+}
+
+func (r *CollectionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
 }
 
 func (r *CollectionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -53,17 +52,7 @@ func (d *OrganizationDataSource) Configure(ctx context.Context, req datasource.C
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.Client
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
 
@@ -101,7 +90,7 @@ func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRe
 
 	req.Config.Get(ctx, &state)
 
-	reqOrg, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization?filter[organization]=name==%s", d.endpoint, state.Name.ValueString()), nil)
+	reqOrg, err := http.NewRequest(http.MethodGet, apiURL(d.endpoint, "organization")+fmt.Sprintf("?filter[organization]=name==%s", state.Name.ValueString()), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
 	reqOrg.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -110,12 +99,12 @@ func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRe
 
 	resOrg, err := d.client.Do(reqOrg)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error executing organization datasource request, response status: %s, response body: %s, error: %s", resOrg.Status, resOrg.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error executing organization datasource request, %s, error: %s", formatAPIError(resOrg, nil), err))
 	}
 
 	body, err := io.ReadAll(resOrg.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading organization response, response status: %s, response body: %s, error: %s", resOrg.Status, resOrg.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading organization response, %s, error: %s", formatAPIError(resOrg, body), err))
 	}
 
 	var orgs []interface{}
@@ -123,7 +112,7 @@ func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRe
 	orgs, err = jsonapi.UnmarshalManyPayload(strings.NewReader(string(body)), reflect.TypeOf(new(client.OrganizationEntity)))
 
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to marshal payload, response status: %s, response body: %s, error: %s", resOrg.Status, resOrg.Body, err))
+		resp.Diagnostics.AddError("Unable to unmarshal payload", fmt.Sprintf("Unable to marshal payload, %s, error: %s", formatAPIError(resOrg, body), err))
 		return
 	}
 
@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
-	"crypto/tls"
 	"fmt"
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -37,8 +43,17 @@ type OrganizationResourceModel struct {
 	Name          types.String `tfsdk:"name"`
 	Description   types.String `tfsdk:"description"`
 	ExecutionMode types.String `tfsdk:"execution_mode"`
+	Disabled      types.Bool   `tfsdk:"disabled"`
+	DestroyMode   types.String `tfsdk:"destroy_mode"`
 }
 
+const (
+	// organizationDestroyDisable disables and renames the organization on destroy.
+	organizationDestroyDisable = "disable"
+	// organizationDestroyDelete deletes the organization on destroy.
+	organizationDestroyDelete = "delete"
+)
+
 func NewOrganizationResource() resource.Resource {
 	return &OrganizationResource{}
 }
@@ -71,6 +86,21 @@ func (r *OrganizationResource) Schema(ctx context.Context, req resource.SchemaRe
 				Required:    true,
 				Description: "Select default execution mode for the organization (remote or local)",
 			},
+			"disabled": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Disable the organization, default is `false`.",
+			},
+			"destroy_mode": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(organizationDestroyDisable),
+				Description: "Behavior when the organization is destroyed. `disable` disables the organization and renames it with a `_DEL_` suffix so the name can be reused, `delete` deletes it, which the API refuses while the organization has workspaces. Default is `disable`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(organizationDestroyDisable, organizationDestroyDelete),
+				},
+			},
 		},
 	}
 }
@@ -90,17 +120,7 @@ func (r *OrganizationResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -121,6 +141,7 @@ func (r *OrganizationResource) Create(ctx context.Context, req resource.CreateRe
 		Name:          plan.Name.ValueString(),
 		Description:   plan.Description.ValueString(),
 		ExecutionMode: plan.ExecutionMode.ValueString(),
+		Disabled:      plan.Disabled.ValueBool(),
 	}
 
 	var out = new(bytes.Buffer)
@@ -131,7 +152,7 @@ func (r *OrganizationResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	organizationRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization", r.endpoint), strings.NewReader(out.String()))
+	organizationRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization"), strings.NewReader(out.String()))
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -164,6 +185,7 @@ func (r *OrganizationResource) Create(ctx context.Context, req resource.CreateRe
 	plan.Name = types.StringValue(newOrganization.Name)
 	plan.Description = types.StringValue(newOrganization.Description)
 	plan.ExecutionMode = types.StringValue(newOrganization.ExecutionMode)
+	plan.Disabled = types.BoolValue(newOrganization.Disabled)
 
 	tflog.Info(ctx, "Organization Resource Created", map[string]any{"success": true})
 
@@ -178,7 +200,7 @@ func (r *OrganizationResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	organizationRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s", r.endpoint, state.ID.ValueString()), nil)
+	organizationRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.ID.ValueString()), nil)
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -196,6 +218,11 @@ func (r *OrganizationResource) Read(ctx context.Context, req resource.ReadReques
 	if err != nil {
 		tflog.Error(ctx, "Error reading organization resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, organizationResponse, bodyResponse, "organization") {
+		return
+	}
+
 	organization := &client.OrganizationEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -211,6 +238,10 @@ func (r *OrganizationResource) Read(ctx context.Context, req resource.ReadReques
 	state.Description = types.StringValue(organization.Description)
 	state.ExecutionMode = types.StringValue(organization.ExecutionMode)
 	state.ID = types.StringValue(organization.ID)
+	state.Disabled = types.BoolValue(organization.Disabled)
+	if state.DestroyMode.IsNull() {
+		state.DestroyMode = types.StringValue(organizationDestroyDisable)
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -236,6 +267,7 @@ func (r *OrganizationResource) Update(ctx context.Context, req resource.UpdateRe
 		Description:   plan.Description.ValueString(),
 		ExecutionMode: plan.ExecutionMode.ValueString(),
 		Name:          plan.Name.ValueString(),
+		Disabled:      plan.Disabled.ValueBool(),
 		ID:            state.ID.ValueString(),
 	}
 
@@ -247,7 +279,7 @@ func (r *OrganizationResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
-	organizationRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s", r.endpoint, state.ID.ValueString()), strings.NewReader(out.String()))
+	organizationRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.ID.ValueString()), strings.NewReader(out.String()))
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -268,7 +300,7 @@ func (r *OrganizationResource) Update(ctx context.Context, req resource.UpdateRe
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	organizationRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s", r.endpoint, state.ID.ValueString()), nil)
+	organizationRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.ID.ValueString()), nil)
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -301,6 +333,7 @@ func (r *OrganizationResource) Update(ctx context.Context, req resource.UpdateRe
 	plan.Name = types.StringValue(organization.Name)
 	plan.Description = types.StringValue(organization.Description)
 	plan.ExecutionMode = types.StringValue(organization.ExecutionMode)
+	plan.Disabled = types.BoolValue(organization.Disabled)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -315,6 +348,11 @@ func (r *OrganizationResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	if data.DestroyMode.ValueString() == organizationDestroyDelete {
+		r.deleteOrganization(ctx, data, resp)
+		return
+	}
+
 	var chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
 
 	ll := len(chars)
@@ -343,15 +381,15 @@ func (r *OrganizationResource) Delete(ctx context.Context, req resource.DeleteRe
 	var out = new(bytes.Buffer)
 	err := jsonapi.MarshalPayload(out, bodyRequest)
 
-	tflog.Info(ctx, "Request Body Delete Organization...")
-	tflog.Info(ctx, out.String())
+	tflog.Debug(ctx, "Request Body Delete Organization...")
+	tflog.Debug(ctx, helpers.RedactPayload(out.String()))
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s", r.endpoint, data.ID.ValueString()), strings.NewReader(out.String()))
+	reqOrg, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", data.ID.ValueString()), strings.NewReader(out.String()))
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	reqOrg.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -365,7 +403,50 @@ func (r *OrganizationResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	defer organizationResponse.Body.Close()
+
 	tflog.Info(ctx, "Delete Organization response code: "+strconv.Itoa(organizationResponse.StatusCode))
+
+	bodyResponse, _ := io.ReadAll(organizationResponse.Body)
+	if err = checkDeleteResponse(organizationResponse, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing organization resource request", fmt.Sprintf("Error executing organization resource request: %s", err))
+		return
+	}
+}
+
+// deleteOrganization deletes the organization. When the API refuses it, the
+// error tells how many workspaces are left in the organization.
+func (r *OrganizationResource) deleteOrganization(ctx context.Context, data OrganizationResourceModel, resp *resource.DeleteResponse) {
+	reqOrg, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", url.PathEscape(data.ID.ValueString())), nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating organization resource request", fmt.Sprintf("Error creating organization resource request: %s", err))
+		return
+	}
+	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	reqOrg.Header.Add("Content-Type", "application/vnd.api+json")
+
+	organizationResponse, err := r.client.Do(reqOrg)
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing organization resource request", fmt.Sprintf("Error executing organization resource request: %s", err))
+		return
+	}
+	defer organizationResponse.Body.Close()
+
+	bodyResponse, err := io.ReadAll(organizationResponse.Body)
+	if err != nil {
+		tflog.Error(ctx, "Error reading organization resource response")
+	}
+
+	if err = checkDeleteResponse(organizationResponse, bodyResponse); err != nil {
+		detail := fmt.Sprintf("Error deleting organization %s: %s", data.Name.ValueString(), err)
+
+		workspaces, listErr := listAll(r.client, r.token, apiURL(r.endpoint, "organization", url.PathEscape(data.ID.ValueString()), "workspace")+"?filter[workspace]=deleted==false", reflect.TypeOf(new(client.WorkspaceEntity)))
+		if listErr == nil && len(workspaces) > 0 {
+			detail = fmt.Sprintf("%s\nThe organization still has %d workspaces, delete them first or set destroy_mode = \"disable\".", detail, len(workspaces))
+		}
+
+		resp.Diagnostics.AddError("Error deleting organization", detail)
+	}
 }
 
 func (r *OrganizationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
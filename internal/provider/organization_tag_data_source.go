@@ -2,13 +2,14 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
 
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -53,17 +54,7 @@ func (d *OrganizationTagDataSource) Configure(ctx context.Context, req datasourc
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.Client
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
 
@@ -101,7 +92,7 @@ func (d *OrganizationTagDataSource) Read(ctx context.Context, req datasource.Rea
 
 	req.Config.Get(ctx, &state)
 
-	reqOrgTag, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/tag?filter[tag]=name==%s", d.endpoint, state.OrganizationId.ValueString(), state.Name.ValueString()), nil)
+	reqOrgTag, err := http.NewRequest(http.MethodGet, apiURL(d.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "tag")+"?"+url.Values{"filter[tag]": {"name==" + helpers.RsqlQuote(state.Name.ValueString())}}.Encode(), nil)
 	reqOrgTag.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
 	reqOrgTag.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
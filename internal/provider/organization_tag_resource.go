@@ -3,14 +3,20 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
 
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 
@@ -24,19 +30,32 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &OrganizationTagResource{}
 var _ resource.ResourceWithImportState = &OrganizationTagResource{}
+var _ resource.ResourceWithModifyPlan = &OrganizationTagResource{}
 
 type OrganizationTagResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type OrganizationTagResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	OrganizationId types.String `tfsdk:"organization_id"`
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	OrganizationId  types.String `tfsdk:"organization_id"`
+	AdoptExisting   types.Bool   `tfsdk:"adopt_existing"`
+	DeleteOnDestroy types.Bool   `tfsdk:"delete_on_destroy"`
+	PreventInUse    types.Bool   `tfsdk:"prevent_destroy_if_in_use"`
+	WorkspaceCount  types.Int64  `tfsdk:"workspace_count"`
 }
 
+const (
+	// tagWorkspacesPageSize is the number of workspaces requested on each page when counting the tag usage.
+	tagWorkspacesPageSize = 100
+	// tagWorkspacesExamples is the number of workspace names shown when a tag in use can't be deleted.
+	tagWorkspacesExamples = 5
+)
+
 func NewOrganizationTagResource() resource.Resource {
 	return &OrganizationTagResource{}
 }
@@ -57,14 +76,38 @@ func (r *OrganizationTagResource) Schema(ctx context.Context, req resource.Schem
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Organization Tag name",
 			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Adopt the existing tag with the same name instead of failing when the tag already exists in the organization. Any other error creating the tag is still reported.",
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"delete_on_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Delete the tag from the organization on destroy, set to `false` when the tag is shared with other configurations. Default is `true` for a tag created by the resource and `false` for a tag adopted with `adopt_existing`.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"prevent_destroy_if_in_use": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Fail on destroy when the tag is still attached to workspaces instead of detaching it from all of them, default is `false`",
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"workspace_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of workspaces of the organization the tag is attached to",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -84,20 +127,11 @@ func (r *OrganizationTagResource) Configure(ctx context.Context, req resource.Co
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Organization Tag resource", map[string]any{"success": true})
 }
@@ -123,7 +157,7 @@ func (r *OrganizationTagResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
-	organizationTagRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/tag", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	organizationTagRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", url.PathEscape(plan.OrganizationId.ValueString()), "tag"), strings.NewReader(out.String()))
 	organizationTagRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTagRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -133,20 +167,55 @@ func (r *OrganizationTagResource) Create(ctx context.Context, req resource.Creat
 
 	organizationTagResponse, err := r.client.Do(organizationTagRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, %s, error: %s", formatAPIError(organizationTagResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(organizationTagResponse.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading organization tag resource response, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading organization tag resource response, %s, error: %s", formatAPIError(organizationTagResponse, bodyResponse), err))
+	}
+
+	if plan.AdoptExisting.ValueBool() && client.IsAlreadyExists(client.CheckResponse(organizationTagResponse, bodyResponse)) {
+		tflog.Info(ctx, fmt.Sprintf("Organization tag already exists, looking up existing tag %s", plan.Name.ValueString()))
+
+		existingTag, err := r.findTagByName(plan.OrganizationId.ValueString(), plan.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error looking up existing organization tag", fmt.Sprintf("Error looking up existing organization tag after create failed with status %s, response body: %s, error: %s", organizationTagResponse.Status, string(bodyResponse), err))
+			return
+		}
+
+		if existingTag == nil {
+			resp.Diagnostics.AddError("Error creating organization tag", fmt.Sprintf("Organization tag %s could not be created and no existing tag with that name was found, response status: %s, response body: %s", plan.Name.ValueString(), organizationTagResponse.Status, string(bodyResponse)))
+			return
+		}
+
+		plan.ID = types.StringValue(existingTag.ID)
+		plan.Name = types.StringValue(existingTag.Name)
+		// The adopted tag was created outside this resource, it is kept on destroy unless configured otherwise
+		if plan.DeleteOnDestroy.IsUnknown() {
+			plan.DeleteOnDestroy = types.BoolValue(false)
+		}
+
+		resp.Diagnostics.Append(r.setWorkspaceCount(&plan)...)
+
+		tflog.Info(ctx, "Organization Tag Resource Adopted", map[string]any{"success": true})
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
 	}
+
+	if err = client.CheckResponse(organizationTagResponse, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error creating organization tag", fmt.Sprintf("Error creating organization tag %s: %s", plan.Name.ValueString(), err))
+		return
+	}
+
 	newOrganizationTag := &client.OrganizationTagEntity{}
 
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newOrganizationTag)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, %s, error: %s", formatAPIError(organizationTagResponse, bodyResponse), err))
 		return
 	}
 
@@ -154,6 +223,10 @@ func (r *OrganizationTagResource) Create(ctx context.Context, req resource.Creat
 
 	plan.ID = types.StringValue(newOrganizationTag.ID)
 	plan.Name = types.StringValue(newOrganizationTag.Name)
+	plan.WorkspaceCount = types.Int64Value(0)
+	if plan.DeleteOnDestroy.IsUnknown() {
+		plan.DeleteOnDestroy = types.BoolValue(true)
+	}
 
 	tflog.Info(ctx, "Organization Tag Resource Created", map[string]any{"success": true})
 
@@ -168,7 +241,7 @@ func (r *OrganizationTagResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
-	organizationTagRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/tag/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	organizationTagRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "tag", url.PathEscape(state.ID.ValueString())), nil)
 	organizationTagRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTagRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -178,21 +251,26 @@ func (r *OrganizationTagResource) Read(ctx context.Context, req resource.ReadReq
 
 	organizationTagResponse, err := r.client.Do(organizationTagRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, %s, error: %s", formatAPIError(organizationTagResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(organizationTagResponse.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading organization tag resource response, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading organization tag resource response, %s, error: %s", formatAPIError(organizationTagResponse, bodyResponse), err))
 	}
+
+	if !checkReadResponse(ctx, resp, organizationTagResponse, bodyResponse, "organization tag") {
+		return
+	}
+
 	organizationTag := &client.OrganizationTagEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), organizationTag)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, %s, error: %s", formatAPIError(organizationTagResponse, bodyResponse), err))
 		return
 	}
 
@@ -200,6 +278,22 @@ func (r *OrganizationTagResource) Read(ctx context.Context, req resource.ReadReq
 
 	state.Name = types.StringValue(organizationTag.Name)
 
+	// Imported tags have no value for the provider only flags
+	if state.AdoptExisting.IsNull() {
+		state.AdoptExisting = types.BoolValue(false)
+	}
+	if state.DeleteOnDestroy.IsNull() {
+		state.DeleteOnDestroy = types.BoolValue(true)
+	}
+	if state.PreventInUse.IsNull() {
+		state.PreventInUse = types.BoolValue(false)
+	}
+
+	resp.Diagnostics.Append(r.setWorkspaceCount(&state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -233,7 +327,7 @@ func (r *OrganizationTagResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
-	organizationTagRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/tag/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	organizationTagRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "tag", url.PathEscape(state.ID.ValueString())), strings.NewReader(out.String()))
 	organizationTagRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTagRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -243,18 +337,18 @@ func (r *OrganizationTagResource) Update(ctx context.Context, req resource.Updat
 
 	organizationTagResponse, err := r.client.Do(organizationTagRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, %s, error: %s", formatAPIError(organizationTagResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(organizationTagResponse.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading organization tag resource response, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading organization tag resource response, %s, error: %s", formatAPIError(organizationTagResponse, bodyResponse), err))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	organizationTagRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/tag/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	organizationTagRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "tag", url.PathEscape(state.ID.ValueString())), nil)
 	organizationTagRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTagRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -264,13 +358,13 @@ func (r *OrganizationTagResource) Update(ctx context.Context, req resource.Updat
 
 	organizationTagResponse, err = r.client.Do(organizationTagRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, %s, error: %s", formatAPIError(organizationTagResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err = io.ReadAll(organizationTagResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading organization tag resource response body", fmt.Sprintf("Error reading organization tag resource response body, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+		resp.Diagnostics.AddError("Error reading organization tag resource response body", fmt.Sprintf("Error reading organization tag resource response body, %s, error: %s", formatAPIError(organizationTagResponse, bodyResponse), err))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -286,6 +380,11 @@ func (r *OrganizationTagResource) Update(ctx context.Context, req resource.Updat
 	plan.ID = types.StringValue(organizationTag.ID)
 	plan.Name = types.StringValue(organizationTag.Name)
 
+	// workspace_count keeps the value planned from the state, the next refresh updates it
+	if plan.WorkspaceCount.IsUnknown() {
+		resp.Diagnostics.Append(r.setWorkspaceCount(&plan)...)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -299,7 +398,37 @@ func (r *OrganizationTagResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/tag/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	if !data.DeleteOnDestroy.IsNull() && !data.DeleteOnDestroy.ValueBool() {
+		tflog.Info(ctx, fmt.Sprintf("Organization tag %s kept in the organization, removing it only from state", data.Name.ValueString()))
+		return
+	}
+
+	if data.PreventInUse.ValueBool() {
+		workspaces, err := r.tagWorkspaces(data.OrganizationId.ValueString(), data.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading organization tag usage", fmt.Sprintf("Error reading the workspaces using organization tag %s: %s", data.Name.ValueString(), err))
+			return
+		}
+
+		if len(workspaces) > 0 {
+			var names []string
+			for _, workspace := range workspaces {
+				if len(names) == tagWorkspacesExamples {
+					names = append(names, "...")
+					break
+				}
+				names = append(names, workspace.Name)
+			}
+
+			resp.Diagnostics.AddError(
+				"Organization tag in use",
+				fmt.Sprintf("Organization tag %s is attached to %d workspaces (%s), detach it first or set prevent_destroy_if_in_use to false.", data.Name.ValueString(), len(workspaces), strings.Join(names, ", ")),
+			)
+			return
+		}
+	}
+
+	reqOrg, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", url.PathEscape(data.OrganizationId.ValueString()), "tag", url.PathEscape(data.ID.ValueString())), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating organization tag resource request", fmt.Sprintf("Error creating organization tag resource request: %s", err))
@@ -307,10 +436,110 @@ func (r *OrganizationTagResource) Delete(ctx context.Context, req resource.Delet
 	}
 
 	organizationTagResponse, err := r.client.Do(reqOrg)
-	if err != nil || organizationTagResponse.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, response status: %s, response body: %s, body: %s", organizationTagResponse.Status, organizationTagResponse.Body, err))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request, error: %s", err))
 		return
 	}
+
+	defer organizationTagResponse.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(organizationTagResponse.Body)
+	if err = checkDeleteResponse(organizationTagResponse, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing organization tag resource request", fmt.Sprintf("Error executing organization tag resource request: %s", err))
+		return
+	}
+}
+
+func (r *OrganizationTagResource) findTagByName(organizationId string, name string) (*client.OrganizationTagEntity, error) {
+	organizationTagRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(organizationId), "tag")+"?"+url.Values{"filter[tag]": {"name==" + helpers.RsqlQuote(name)}}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	organizationTagRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	organizationTagRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+	organizationTagResponse, err := r.client.Do(organizationTagRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer organizationTagResponse.Body.Close()
+
+	if organizationTagResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %s", organizationTagResponse.Status)
+	}
+
+	organizationTags, err := jsonapi.UnmarshalManyPayload(organizationTagResponse.Body, reflect.TypeOf(new(client.OrganizationTagEntity)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, organizationTag := range organizationTags {
+		data, ok := organizationTag.(*client.OrganizationTagEntity)
+		if ok && data.Name == name {
+			return data, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// setWorkspaceCount sets workspace_count with the number of workspaces using the tag.
+func (r *OrganizationTagResource) setWorkspaceCount(model *OrganizationTagResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	workspaces, err := r.tagWorkspaces(model.OrganizationId.ValueString(), model.ID.ValueString())
+	if err != nil {
+		diags.AddError("Error reading organization tag usage", fmt.Sprintf("Error reading the workspaces using organization tag %s: %s", model.Name.ValueString(), err))
+		return diags
+	}
+
+	model.WorkspaceCount = types.Int64Value(int64(len(workspaces)))
+	return diags
+}
+
+// tagWorkspaces returns the workspaces, not deleted, the tag is attached to.
+func (r *OrganizationTagResource) tagWorkspaces(organizationId string, tagId string) ([]*client.WorkspaceEntity, error) {
+	var workspaces []*client.WorkspaceEntity
+	for page := 1; ; page++ {
+		workspaceRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(organizationId), "workspace")+fmt.Sprintf("?filter[workspace]=%s&page[size]=%d&page[number]=%d", url.QueryEscape("deleted==false;workspaceTag.tagId=="+helpers.RsqlQuote(tagId)), tagWorkspacesPageSize, page), nil)
+		if err != nil {
+			return nil, err
+		}
+		workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+		workspaceRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+		workspaceResponse, err := r.client.Do(workspaceRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		bodyResponse, err := io.ReadAll(workspaceResponse.Body)
+		workspaceResponse.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = client.CheckResponse(workspaceResponse, bodyResponse); err != nil {
+			return nil, err
+		}
+
+		items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), reflect.TypeOf(new(client.WorkspaceEntity)))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			workspaces = append(workspaces, item.(*client.WorkspaceEntity))
+		}
+
+		if len(items) < tagWorkspacesPageSize {
+			return workspaces, nil
+		}
+	}
+}
+
+func (r *OrganizationTagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
 }
 
 func (r *OrganizationTagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
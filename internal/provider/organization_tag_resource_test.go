@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestOrganizationTagAdoptExisting(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantAdopted bool
+		wantError   bool
+	}{
+		{name: "conflict", status: http.StatusConflict, wantAdopted: true},
+		{name: "unique constraint", status: http.StatusBadRequest, body: `{"errors":[{"detail":"Tag already exists"}]}`, wantAdopted: true},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantError: true},
+		{name: "server error", status: http.StatusInternalServerError, wantError: true},
+		{name: "validation error", status: http.StatusBadRequest, body: `{"errors":[{"detail":"name is required"}]}`, wantError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lookedUp := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost:
+					w.WriteHeader(test.status)
+					_, _ = w.Write([]byte(test.body))
+				case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/tag"):
+					lookedUp = true
+					_, _ = w.Write([]byte(`{"data":[{"type":"tag","id":"existing","attributes":{"name":"shared"}}]}`))
+				case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/workspace"):
+					_, _ = w.Write([]byte(`{"data":[]}`))
+				default:
+					t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusMethodNotAllowed)
+				}
+			}))
+			defer server.Close()
+
+			r := NewOrganizationTagResource()
+			configureTestResource(t, r, server)
+
+			plan := newTestPlan(t, r, map[string]string{"organization_id": "org", "name": "shared"}, "id", "delete_on_destroy", "workspace_count")
+			ctx := context.Background()
+			if diags := plan.SetAttribute(ctx, path.Root("adopt_existing"), true); diags.HasError() {
+				t.Fatalf("unable to set adopt_existing: %v", diags)
+			}
+			if diags := plan.SetAttribute(ctx, path.Root("prevent_destroy_if_in_use"), false); diags.HasError() {
+				t.Fatalf("unable to set prevent_destroy_if_in_use: %v", diags)
+			}
+
+			resp := resource.CreateResponse{State: newTestState(t, r, nil)}
+			r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+
+			if resp.Diagnostics.HasError() != test.wantError {
+				t.Fatalf("error = %t, want %t: %v", resp.Diagnostics.HasError(), test.wantError, resp.Diagnostics)
+			}
+			if lookedUp != test.wantAdopted {
+				t.Errorf("looked up the existing tag = %t, want %t", lookedUp, test.wantAdopted)
+			}
+			if !test.wantAdopted {
+				return
+			}
+
+			var state OrganizationTagResourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+			if state.ID.ValueString() != "existing" {
+				t.Errorf("id = %s, want existing", state.ID)
+			}
+			if !state.DeleteOnDestroy.Equal(types.BoolValue(false)) {
+				t.Errorf("delete_on_destroy = %s, want false for an adopted tag", state.DeleteOnDestroy)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -54,17 +53,7 @@ func (d *OrganizationTemplateDataSource) Configure(ctx context.Context, req data
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.Client
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
 
@@ -102,7 +91,7 @@ func (d *OrganizationTemplateDataSource) Read(ctx context.Context, req datasourc
 
 	req.Config.Get(ctx, &state)
 
-	apiUrl := fmt.Sprintf("%s/api/v1/organization/%s/template?filter[template]=name=='%s'", d.endpoint, state.OrganizationId.ValueString(), url.PathEscape(state.Name.ValueString()))
+	apiUrl := apiURL(d.endpoint, "organization", state.OrganizationId.ValueString(), "template") + fmt.Sprintf("?filter[template]=name=='%s'", url.PathEscape(state.Name.ValueString()))
 	reqTemplate, err := http.NewRequest(http.MethodGet, apiUrl, nil)
 	reqTemplate.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
 	reqTemplate.Header.Add("Content-Type", "application/vnd.api+json")
@@ -3,18 +3,24 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
 
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -26,11 +32,13 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &OrganizationTemplateResource{}
 var _ resource.ResourceWithImportState = &OrganizationTemplateResource{}
+var _ resource.ResourceWithModifyPlan = &OrganizationTemplateResource{}
 
 type OrganizationTemplateResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type OrganizationTemplateResourceModel struct {
@@ -40,6 +48,9 @@ type OrganizationTemplateResourceModel struct {
 	Description    types.String `tfsdk:"description"`
 	Version        types.String `tfsdk:"version"`
 	Content        types.String `tfsdk:"content"`
+	ContentFile    types.String `tfsdk:"content_file"`
+	ContentHash    types.String `tfsdk:"content_hash"`
+	Force          types.Bool   `tfsdk:"force"`
 }
 
 func NewOrganizationTemplateResource() resource.Resource {
@@ -63,10 +74,7 @@ func (r *OrganizationTemplateResource) Schema(ctx context.Context, req resource.
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "The name of the template",
@@ -80,8 +88,25 @@ func (r *OrganizationTemplateResource) Schema(ctx context.Context, req resource.
 				Description: "The version of the template",
 			},
 			"content": schema.StringAttribute{
-				Required:    true,
+				Optional:    true,
 				Description: "The content of the template",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("content"), path.MatchRoot("content_file")),
+				},
+			},
+			"content_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a file with the content of the template, relative paths are resolved against the Terraform working directory. Changes are detected with `content_hash` instead of showing the whole content in the plan.",
+			},
+			"content_hash": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA256 hash of the content of the template",
+			},
+			"force": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Delete the template even when workspaces, webhooks or schedules still reference it, default is `false`. The remaining references are listed as warnings.",
 			},
 		},
 	}
@@ -102,20 +127,11 @@ func (r *OrganizationTemplateResource) Configure(ctx context.Context, req resour
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Organization Template resource", map[string]any{"success": true})
 }
@@ -129,22 +145,28 @@ func (r *OrganizationTemplateResource) Create(ctx context.Context, req resource.
 		return
 	}
 
+	content, err := templateContent(plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading template content file", fmt.Sprintf("Error reading template content file %s: %s", plan.ContentFile.ValueString(), err))
+		return
+	}
+
 	bodyRequest := &client.OrganizationTemplateEntity{
 		Name:        plan.Name.ValueString(),
 		Description: plan.Description.ValueString(),
 		Version:     plan.Version.ValueString(),
-		Content:     base64.StdEncoding.EncodeToString([]byte(plan.Content.ValueString())),
+		Content:     base64.StdEncoding.EncodeToString([]byte(content)),
 	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err = jsonapi.MarshalPayload(out, bodyRequest)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
 		return
 	}
 
-	organizationTemplateRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/template", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	organizationTemplateRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "template"), strings.NewReader(out.String()))
 	organizationTemplateRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTemplateRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -154,20 +176,20 @@ func (r *OrganizationTemplateResource) Create(ctx context.Context, req resource.
 
 	organizationTemplateResponse, err := r.client.Do(organizationTemplateRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, %s, error: %s", formatAPIError(organizationTemplateResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(organizationTemplateResponse.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading organization template resource response, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading organization template resource response, %s, error: %s", formatAPIError(organizationTemplateResponse, bodyResponse), err))
 	}
 	organizationTemplate := &client.OrganizationTemplateEntity{}
 
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), organizationTemplate)
 	tflog.Info(ctx, string(bodyResponse))
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, %s, error: %s", formatAPIError(organizationTemplateResponse, bodyResponse), err))
 		return
 	}
 
@@ -182,7 +204,10 @@ func (r *OrganizationTemplateResource) Create(ctx context.Context, req resource.
 		resp.Diagnostics.AddError("Error decoding the content from Base64.", fmt.Sprintf("Error decode the tcl: %s", err))
 		return
 	}
-	plan.Content = types.StringValue(string(contentDecoded))
+	if plan.ContentFile.IsNull() {
+		plan.Content = types.StringValue(string(contentDecoded))
+	}
+	plan.ContentHash = types.StringValue(templateContentHash(string(contentDecoded)))
 
 	tflog.Info(ctx, "Organization Template Resource Created", map[string]any{"success": true})
 
@@ -197,7 +222,7 @@ func (r *OrganizationTemplateResource) Read(ctx context.Context, req resource.Re
 		return
 	}
 
-	organizationTemplateRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/template/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	organizationTemplateRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "template", state.ID.ValueString()), nil)
 	organizationTemplateRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTemplateRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -207,14 +232,19 @@ func (r *OrganizationTemplateResource) Read(ctx context.Context, req resource.Re
 
 	organizationTemplateResponse, err := r.client.Do(organizationTemplateRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, %s, error: %s", formatAPIError(organizationTemplateResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(organizationTemplateResponse.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading organization template resource response, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading organization template resource response, %s, error: %s", formatAPIError(organizationTemplateResponse, bodyResponse), err))
 	}
+
+	if !checkReadResponse(ctx, resp, organizationTemplateResponse, bodyResponse, "organization template") {
+		return
+	}
+
 	organizationTemplate := &client.OrganizationTemplateEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -235,9 +265,17 @@ func (r *OrganizationTemplateResource) Read(ctx context.Context, req resource.Re
 		resp.Diagnostics.AddError("Error decoding the content from Base64.", fmt.Sprintf("Error decode the tcl: %s", err))
 		return
 	}
-	state.Content = types.StringValue(string(contentDecoded))
+	if state.ContentFile.IsNull() {
+		state.Content = types.StringValue(string(contentDecoded))
+	}
+	state.ContentHash = types.StringValue(templateContentHash(string(contentDecoded)))
 	state.ID = types.StringValue(organizationTemplate.ID)
 
+	// Imported templates have no value for the provider only flag
+	if state.Force.IsNull() {
+		state.Force = types.BoolValue(false)
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -258,25 +296,31 @@ func (r *OrganizationTemplateResource) Update(ctx context.Context, req resource.
 		return
 	}
 
+	content, err := templateContent(plan)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading template content file", fmt.Sprintf("Error reading template content file %s: %s", plan.ContentFile.ValueString(), err))
+		return
+	}
+
 	bodyRequest := &client.OrganizationTemplateEntity{
 		Name:        plan.Name.ValueString(),
 		Description: plan.Description.ValueString(),
 		Version:     plan.Version.ValueString(),
-		Content:     base64.StdEncoding.EncodeToString([]byte(plan.Content.ValueString())),
+		Content:     base64.StdEncoding.EncodeToString([]byte(content)),
 		ID:          state.ID.ValueString(),
 	}
 
 	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	err = jsonapi.MarshalPayload(out, bodyRequest)
 
 	if err != nil {
 		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
 		return
 	}
 
-	tflog.Info(ctx, "Body Update Request: "+out.String())
+	tflog.Debug(ctx, "Body Update Request: "+helpers.RedactPayload(out.String()))
 
-	organizationTemplateRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/template/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	organizationTemplateRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "template", state.ID.ValueString()), strings.NewReader(out.String()))
 	organizationTemplateRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTemplateRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -286,18 +330,18 @@ func (r *OrganizationTemplateResource) Update(ctx context.Context, req resource.
 
 	organizationTemplateResponse, err := r.client.Do(organizationTemplateRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, %s, error: %s", formatAPIError(organizationTemplateResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(organizationTemplateResponse.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading organization template resource response, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading organization template resource response, %s, error: %s", formatAPIError(organizationTemplateResponse, bodyResponse), err))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	organizationTemplateRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/template/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	organizationTemplateRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "template", state.ID.ValueString()), nil)
 	organizationTemplateRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationTemplateRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -307,13 +351,13 @@ func (r *OrganizationTemplateResource) Update(ctx context.Context, req resource.
 
 	organizationTemplateResponse, err = r.client.Do(organizationTemplateRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, %s, error: %s", formatAPIError(organizationTemplateResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err = io.ReadAll(organizationTemplateResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading organization template resource response body", fmt.Sprintf("Error reading organization template resource response body, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+		resp.Diagnostics.AddError("Error reading organization template resource response body", fmt.Sprintf("Error reading organization template resource response body, %s, error: %s", formatAPIError(organizationTemplateResponse, bodyResponse), err))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -335,7 +379,10 @@ func (r *OrganizationTemplateResource) Update(ctx context.Context, req resource.
 		resp.Diagnostics.AddError("Error decoding the content from Base64.", fmt.Sprintf("Error decode the tcl: %s", err))
 		return
 	}
-	plan.Content = types.StringValue(string(contentDecoded))
+	if plan.ContentFile.IsNull() {
+		plan.Content = types.StringValue(string(contentDecoded))
+	}
+	plan.ContentHash = types.StringValue(templateContentHash(string(contentDecoded)))
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -350,7 +397,28 @@ func (r *OrganizationTemplateResource) Delete(ctx context.Context, req resource.
 		return
 	}
 
-	organizationTemplateRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/template/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	usages, err := templateUsages(r.client, r.endpoint, r.token, data.OrganizationId.ValueString(), data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading template usages", fmt.Sprintf("Error looking for the references to template %s: %s", data.Name.ValueString(), err))
+		return
+	}
+
+	if len(usages) > 0 {
+		if !data.Force.ValueBool() {
+			resp.Diagnostics.AddError(
+				"Template in use",
+				fmt.Sprintf("Template %s is referenced by:\n  - %s\nRemove the references first or set force = true to delete it anyway.", data.Name.ValueString(), strings.Join(usages, "\n  - ")),
+			)
+			return
+		}
+
+		resp.Diagnostics.AddWarning(
+			"Deleted template still referenced",
+			fmt.Sprintf("Template %s was deleted with force = true, these references now point to a missing template and must be cleaned up:\n  - %s", data.Name.ValueString(), strings.Join(usages, "\n  - ")),
+		)
+	}
+
+	organizationTemplateRequest, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", data.OrganizationId.ValueString(), "template", data.ID.ValueString()), nil)
 	organizationTemplateRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating organization template resource request", fmt.Sprintf("Error creating organization template resource request: %s", err))
@@ -358,10 +426,70 @@ func (r *OrganizationTemplateResource) Delete(ctx context.Context, req resource.
 	}
 
 	organizationTemplateResponse, err := r.client.Do(organizationTemplateRequest)
-	if err != nil || organizationTemplateResponse.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, response status: %s, response body: %s, error: %s", organizationTemplateResponse.Status, organizationTemplateResponse.Body, err))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request, error: %s", err))
+		return
+	}
+
+	defer organizationTemplateResponse.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(organizationTemplateResponse.Body)
+	if err = checkDeleteResponse(organizationTemplateResponse, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing organization template resource request", fmt.Sprintf("Error executing organization template resource request: %s", err))
+		return
+	}
+}
+
+func (r *OrganizationTemplateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Do nothing if it's destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan OrganizationTemplateResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Content.IsUnknown() || plan.ContentFile.IsUnknown() {
+		plan.ContentHash = types.StringUnknown()
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	content, err := templateContent(plan)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("content_file"), "Error reading template content file", fmt.Sprintf("Error reading template content file %s: %s", plan.ContentFile.ValueString(), err))
 		return
 	}
+
+	plan.ContentHash = types.StringValue(templateContentHash(content))
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func templateContent(plan OrganizationTemplateResourceModel) (string, error) {
+	if plan.ContentFile.IsNull() {
+		return plan.Content.ValueString(), nil
+	}
+
+	content, err := os.ReadFile(plan.ContentFile.ValueString())
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+func templateContentHash(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
 }
 
 func (r *OrganizationTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ datasource.DataSource                     = &OrganizationTemplateStepsDataSource{}
+	_ datasource.DataSourceWithConfigure        = &OrganizationTemplateStepsDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &OrganizationTemplateStepsDataSource{}
+)
+
+type OrganizationTemplateStepsDataSourceModel struct {
+	OrganizationId types.String                              `tfsdk:"organization_id"`
+	ID             types.String                              `tfsdk:"id"`
+	Name           types.String                              `tfsdk:"name"`
+	RawContent     types.String                              `tfsdk:"raw_content"`
+	Steps          []OrganizationTemplateStepDataSourceModel `tfsdk:"steps"`
+}
+
+type OrganizationTemplateStepDataSourceModel struct {
+	Name       types.String `tfsdk:"name"`
+	Type       types.String `tfsdk:"type"`
+	StepNumber types.Int64  `tfsdk:"step_number"`
+}
+
+// templateFlow is the part of the template content read by the data source.
+type templateFlow struct {
+	Flow []struct {
+		Type string `yaml:"type"`
+		Name string `yaml:"name"`
+		Step int64  `yaml:"step"`
+	} `yaml:"flow"`
+}
+
+type OrganizationTemplateStepsDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewOrganizationTemplateStepsDataSource() datasource.DataSource {
+	return &OrganizationTemplateStepsDataSource{}
+}
+
+func (d *OrganizationTemplateStepsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Organization Template Steps Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	ctx = tflog.SetField(ctx, "endpoint", d.endpoint)
+	ctx = tflog.SetField(ctx, "token", d.token)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "token")
+	tflog.Info(ctx, "Organization Template Steps Data Source configured")
+}
+
+func (d *OrganizationTemplateStepsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organization_template_steps"
+}
+
+func (d *OrganizationTemplateStepsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Read the steps of the flow of an organization template, for example to check in a precondition that a template runs an apply before using it. When the content can't be parsed a warning is returned, `steps` is null and only `raw_content` is set.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Organization ID",
+			},
+			"id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Template ID, either `id` or `name` must be set",
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Template name, either `id` or `name` must be set",
+			},
+			"raw_content": schema.StringAttribute{
+				Computed:    true,
+				Description: "Decoded content of the template",
+			},
+			"steps": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Steps of the template flow ordered by step number",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Step name",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Step type, for example `terraformPlan`, `terraformApply` or `customScripts`",
+						},
+						"step_number": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Step number",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *OrganizationTemplateStepsDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
+func (d *OrganizationTemplateStepsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state OrganizationTemplateStepsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templatesURL := apiURL(d.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "template")
+
+	var template *client.OrganizationTemplateEntity
+	if !state.ID.IsNull() {
+		var err error
+		template, err = d.getTemplate(endpointURL(templatesURL, url.PathEscape(state.ID.ValueString())))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading organization template", fmt.Sprintf("Error reading template %s: %s", state.ID.ValueString(), err))
+			return
+		}
+	} else {
+		query := url.Values{}
+		query.Set("filter[template]", "name=="+helpers.RsqlQuote(state.Name.ValueString()))
+
+		templates, err := listAll(d.client, d.token, fmt.Sprintf("%s?%s", templatesURL, query.Encode()), reflect.TypeOf(new(client.OrganizationTemplateEntity)))
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading organization templates", err.Error())
+			return
+		}
+
+		for _, item := range templates {
+			if found := item.(*client.OrganizationTemplateEntity); found.Name == state.Name.ValueString() {
+				template = found
+			}
+		}
+
+		if template == nil {
+			resp.Diagnostics.AddError("Template not found", fmt.Sprintf("Template %s doesn't exist in organization %s", state.Name.ValueString(), state.OrganizationId.ValueString()))
+			return
+		}
+	}
+
+	state.ID = types.StringValue(template.ID)
+	state.Name = types.StringValue(template.Name)
+
+	// Steps are left null when the content can't be read, raw_content is still set
+	content, err := base64.StdEncoding.DecodeString(template.Content)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to decode template content", fmt.Sprintf("The content of template %s is not base64 encoded, raw_content is the content returned by the API: %s", template.Name, err))
+		state.RawContent = types.StringValue(template.Content)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+	state.RawContent = types.StringValue(string(content))
+
+	var flow templateFlow
+	if err = yaml.Unmarshal(content, &flow); err != nil {
+		resp.Diagnostics.AddWarning("Unable to parse template content", fmt.Sprintf("The content of template %s can't be parsed, only raw_content is set: %s", template.Name, err))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	state.Steps = []OrganizationTemplateStepDataSourceModel{}
+	for _, step := range flow.Flow {
+		state.Steps = append(state.Steps, OrganizationTemplateStepDataSourceModel{
+			Name:       types.StringValue(step.Name),
+			Type:       types.StringValue(step.Type),
+			StepNumber: types.Int64Value(step.Step),
+		})
+	}
+	sort.SliceStable(state.Steps, func(i, j int) bool {
+		return state.Steps[i].StepNumber.ValueInt64() < state.Steps[j].StepNumber.ValueInt64()
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *OrganizationTemplateStepsDataSource) getTemplate(apiURL string) (*client.OrganizationTemplateEntity, error) {
+	request, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.CheckResponse(response, bodyResponse); err != nil {
+		return nil, err
+	}
+
+	template := &client.OrganizationTemplateEntity{}
+	if err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), template); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal payload: %w", err)
+	}
+
+	return template, nil
+}
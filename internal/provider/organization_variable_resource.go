@@ -3,13 +3,14 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
 
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -18,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -25,11 +27,13 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &OrganizationVariableResource{}
 var _ resource.ResourceWithImportState = &OrganizationVariableResource{}
+var _ resource.ResourceWithModifyPlan = &OrganizationVariableResource{}
 
 type OrganizationVariableResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type OrganizationVariableResourceModel struct {
@@ -63,17 +67,18 @@ func (r *OrganizationVariableResource) Schema(ctx context.Context, req resource.
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"key": schema.StringAttribute{
 				Required:    true,
 				Description: "Variable key",
+				Validators: []validator.String{
+					variableKeyValidator(),
+				},
 			},
 			"value": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable value",
+				Sensitive:   true,
+				Description: "Variable value, always hidden in the plan output. `sensitive` only sets whether the API hides it.",
 			},
 			"description": schema.StringAttribute{
 				Required:    true,
@@ -110,20 +115,11 @@ func (r *OrganizationVariableResource) Configure(ctx context.Context, req resour
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Organization Variable resource", map[string]any{"success": true})
 }
@@ -154,7 +150,7 @@ func (r *OrganizationVariableResource) Create(ctx context.Context, req resource.
 		return
 	}
 
-	organizationVarRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/globalvar", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	organizationVarRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", url.PathEscape(plan.OrganizationId.ValueString()), "globalvar"), strings.NewReader(out.String()))
 	organizationVarRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationVarRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -211,7 +207,7 @@ func (r *OrganizationVariableResource) Read(ctx context.Context, req resource.Re
 		return
 	}
 
-	organizationVarRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/globalvar/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	organizationVarRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "globalvar", url.PathEscape(state.ID.ValueString())), nil)
 	organizationVarRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationVarRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -229,6 +225,11 @@ func (r *OrganizationVariableResource) Read(ctx context.Context, req resource.Re
 	if err != nil {
 		tflog.Error(ctx, "Error reading organization variable resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, organizationVariableResponse, bodyResponse, "organization variable") {
+		return
+	}
+
 	organizationVariable := &client.OrganizationVariableEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -293,9 +294,9 @@ func (r *OrganizationVariableResource) Update(ctx context.Context, req resource.
 		return
 	}
 
-	tflog.Info(ctx, "Body Update Request: "+out.String())
+	tflog.Debug(ctx, "Body Update Request: "+helpers.RedactPayload(out.String()))
 
-	organizationVarRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/globalvar/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	organizationVarRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "globalvar", url.PathEscape(state.ID.ValueString())), strings.NewReader(out.String()))
 	organizationVarRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationVarRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -316,7 +317,7 @@ func (r *OrganizationVariableResource) Update(ctx context.Context, req resource.
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	organizationVarRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/globalvar/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	organizationVarRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "globalvar", url.PathEscape(state.ID.ValueString())), nil)
 	organizationVarRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationVarRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -374,18 +375,28 @@ func (r *OrganizationVariableResource) Delete(ctx context.Context, req resource.
 		return
 	}
 
-	organizationVarRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/globalvar/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	organizationVarRequest, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", url.PathEscape(data.OrganizationId.ValueString()), "globalvar", url.PathEscape(data.ID.ValueString())), nil)
 	organizationVarRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating organization variable resource request", fmt.Sprintf("Error creating organization variable resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(organizationVarRequest)
+	organizationVariableResponse, err := r.client.Do(organizationVarRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing organization variable resource request", fmt.Sprintf("Error executing organization variable resource request: %s", err))
 		return
 	}
+
+	bodyResponse, _ := io.ReadAll(organizationVariableResponse.Body)
+	if err = checkDeleteResponse(organizationVariableResponse, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing organization variable resource request", fmt.Sprintf("Error executing organization variable resource request: %s", err))
+		return
+	}
+}
+
+func (r *OrganizationVariableResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
 }
 
 func (r *OrganizationVariableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
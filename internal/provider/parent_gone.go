@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// workspaceGone reports whether the workspace doesn't exist anymore or is
+// marked as deleted. Objects of a deleted workspace can't be read or deleted
+// reliably, so they are treated as removed. Any error reading the workspace
+// returns false and the original error is reported instead.
+func workspaceGone(ctx context.Context, httpClient *http.Client, endpoint string, token string, organizationId string, workspaceId string) bool {
+	status, body, err := getParent(httpClient, token, apiURL(endpoint, "organization", organizationId, "workspace", workspaceId))
+	if err != nil {
+		tflog.Debug(ctx, "Unable to read parent workspace", map[string]any{"workspaceId": workspaceId, "error": err.Error()})
+		return false
+	}
+
+	if isGoneOrDeleted(status) {
+		return true
+	}
+
+	workspace := &client.WorkspaceEntity{}
+	if status != http.StatusOK || jsonapi.UnmarshalPayload(strings.NewReader(string(body)), workspace) != nil {
+		return false
+	}
+
+	return workspace.Deleted
+}
+
+// collectionGone reports whether the collection doesn't exist anymore.
+func collectionGone(ctx context.Context, httpClient *http.Client, endpoint string, token string, organizationId string, collectionId string) bool {
+	status, _, err := getParent(httpClient, token, apiURL(endpoint, "organization", organizationId, "collection", collectionId))
+	if err != nil {
+		tflog.Debug(ctx, "Unable to read parent collection", map[string]any{"collectionId": collectionId, "error": err.Error()})
+		return false
+	}
+
+	return isGoneOrDeleted(status)
+}
+
+func getParent(httpClient *http.Client, token string, url string) (int, []byte, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return response.StatusCode, body, nil
+}
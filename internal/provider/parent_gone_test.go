@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// Reading or deleting an object of a deleted workspace fails with an error
+// other than 404, the object is then treated as removed.
+func TestWorkspaceChildParentGone(t *testing.T) {
+	resources := []struct {
+		name        string
+		newResource func() resource.Resource
+	}{
+		{name: "workspace_variable", newResource: NewWorkspaceVariableResource},
+		{name: "workspace_webhook", newResource: NewWorkspaceWebhookResource},
+	}
+
+	parents := []struct {
+		name     string
+		status   int
+		body     string
+		wantGone bool
+	}{
+		{name: "workspace missing", status: http.StatusNotFound, wantGone: true},
+		{name: "workspace deleted", status: http.StatusOK, body: `{"data":{"type":"workspace","id":"ws","attributes":{"name":"simple","deleted":true}}}`, wantGone: true},
+		{name: "workspace exists", status: http.StatusOK, body: `{"data":{"type":"workspace","id":"ws","attributes":{"name":"simple","deleted":false}}}`},
+		{name: "workspace unreadable", status: http.StatusForbidden},
+	}
+
+	for _, test := range resources {
+		for _, parent := range parents {
+			t.Run(test.name+"/"+parent.name, func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodGet && r.URL.Path == "/api/v1/organization/org/workspace/ws" {
+						w.WriteHeader(parent.status)
+						_, _ = w.Write([]byte(parent.body))
+						return
+					}
+					w.WriteHeader(http.StatusInternalServerError)
+					_, _ = w.Write([]byte(`{"errors":[{"detail":"workspace is being deleted"}]}`))
+				}))
+				defer server.Close()
+
+				r := test.newResource()
+				configureTestResource(t, r, server)
+				attributes := map[string]string{"id": "child", "organization_id": "org", "workspace_id": "ws"}
+
+				state := newTestState(t, r, attributes)
+				readResp := resource.ReadResponse{State: state}
+				r.Read(context.Background(), resource.ReadRequest{State: state}, &readResp)
+
+				if readResp.Diagnostics.HasError() == parent.wantGone {
+					t.Errorf("Read diagnostics = %v, want error %t", readResp.Diagnostics, !parent.wantGone)
+				}
+				if removed := readResp.State.Raw.IsNull(); removed != parent.wantGone {
+					t.Errorf("Read removed the resource = %t, want %t", removed, parent.wantGone)
+				}
+
+				deleteResp := deleteTestResource(t, r, attributes)
+				if deleteResp.Diagnostics.HasError() == parent.wantGone {
+					t.Errorf("Delete diagnostics = %v, want error %t", deleteResp.Diagnostics, !parent.wantGone)
+				}
+			})
+		}
+	}
+}
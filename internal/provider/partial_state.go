@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// nullUnknownValues replaces the unknown attributes of a resource model with
+// null, so the model can be saved when the API response could not be parsed.
+// The real values are populated by the next refresh.
+func nullUnknownValues(model interface{}) {
+	v := reflect.ValueOf(model).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if value, ok := field.Interface().(attr.Value); ok {
+			field.Set(reflect.ValueOf(nullUnknownValue(value)))
+		}
+	}
+}
+
+// nullUnknownValue returns value with its unknown parts replaced by null, the
+// elements of known collections and the attributes of known objects included.
+func nullUnknownValue(value attr.Value) attr.Value {
+	ctx := context.Background()
+
+	switch value := value.(type) {
+	case types.String:
+		if value.IsUnknown() {
+			return types.StringNull()
+		}
+	case types.Bool:
+		if value.IsUnknown() {
+			return types.BoolNull()
+		}
+	case types.Int32:
+		if value.IsUnknown() {
+			return types.Int32Null()
+		}
+	case types.Int64:
+		if value.IsUnknown() {
+			return types.Int64Null()
+		}
+	case types.Float64:
+		if value.IsUnknown() {
+			return types.Float64Null()
+		}
+	case types.List:
+		if value.IsUnknown() {
+			return types.ListNull(value.ElementType(ctx))
+		}
+		if !value.IsNull() {
+			return types.ListValueMust(value.ElementType(ctx), nullUnknownElements(value.Elements()))
+		}
+	case types.Set:
+		if value.IsUnknown() {
+			return types.SetNull(value.ElementType(ctx))
+		}
+		if !value.IsNull() {
+			return types.SetValueMust(value.ElementType(ctx), nullUnknownElements(value.Elements()))
+		}
+	case types.Map:
+		if value.IsUnknown() {
+			return types.MapNull(value.ElementType(ctx))
+		}
+		if !value.IsNull() {
+			elements := map[string]attr.Value{}
+			for key, element := range value.Elements() {
+				elements[key] = nullUnknownValue(element)
+			}
+			return types.MapValueMust(value.ElementType(ctx), elements)
+		}
+	case types.Object:
+		if value.IsUnknown() {
+			return types.ObjectNull(value.AttributeTypes(ctx))
+		}
+		if !value.IsNull() {
+			attributes := map[string]attr.Value{}
+			for name, attribute := range value.Attributes() {
+				attributes[name] = nullUnknownValue(attribute)
+			}
+			return types.ObjectValueMust(value.AttributeTypes(ctx), attributes)
+		}
+	}
+
+	return value
+}
+
+func nullUnknownElements(elements []attr.Value) []attr.Value {
+	values := make([]attr.Value, 0, len(elements))
+	for _, element := range elements {
+		values = append(values, nullUnknownValue(element))
+	}
+
+	return values
+}
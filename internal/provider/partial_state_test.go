@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNullUnknownValue(t *testing.T) {
+	objectType := map[string]attr.Type{"name": types.StringType, "enabled": types.BoolType}
+
+	tests := []struct {
+		name  string
+		value attr.Value
+		want  attr.Value
+	}{
+		{name: "unknown string", value: types.StringUnknown(), want: types.StringNull()},
+		{name: "known string", value: types.StringValue("value"), want: types.StringValue("value")},
+		{name: "unknown bool", value: types.BoolUnknown(), want: types.BoolNull()},
+		{name: "unknown int32", value: types.Int32Unknown(), want: types.Int32Null()},
+		{name: "unknown int64", value: types.Int64Unknown(), want: types.Int64Null()},
+		{name: "unknown float64", value: types.Float64Unknown(), want: types.Float64Null()},
+		{name: "unknown list", value: types.ListUnknown(types.StringType), want: types.ListNull(types.StringType)},
+		{
+			name:  "list with unknown element",
+			value: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringUnknown()}),
+			want:  types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringNull()}),
+		},
+		{name: "unknown set", value: types.SetUnknown(types.StringType), want: types.SetNull(types.StringType)},
+		{
+			name:  "set with unknown element",
+			value: types.SetValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringUnknown()}),
+			want:  types.SetValueMust(types.StringType, []attr.Value{types.StringValue("a"), types.StringNull()}),
+		},
+		{name: "null set", value: types.SetNull(types.StringType), want: types.SetNull(types.StringType)},
+		{name: "unknown map", value: types.MapUnknown(types.StringType), want: types.MapNull(types.StringType)},
+		{
+			name:  "map with unknown value",
+			value: types.MapValueMust(types.StringType, map[string]attr.Value{"a": types.StringValue("a"), "b": types.StringUnknown()}),
+			want:  types.MapValueMust(types.StringType, map[string]attr.Value{"a": types.StringValue("a"), "b": types.StringNull()}),
+		},
+		{name: "unknown object", value: types.ObjectUnknown(objectType), want: types.ObjectNull(objectType)},
+		{
+			name:  "object with unknown attribute",
+			value: types.ObjectValueMust(objectType, map[string]attr.Value{"name": types.StringValue("a"), "enabled": types.BoolUnknown()}),
+			want:  types.ObjectValueMust(objectType, map[string]attr.Value{"name": types.StringValue("a"), "enabled": types.BoolNull()}),
+		},
+		{
+			name: "list of objects with unknown attribute",
+			value: types.ListValueMust(types.ObjectType{AttrTypes: objectType}, []attr.Value{
+				types.ObjectValueMust(objectType, map[string]attr.Value{"name": types.StringValue("a"), "enabled": types.BoolUnknown()}),
+			}),
+			want: types.ListValueMust(types.ObjectType{AttrTypes: objectType}, []attr.Value{
+				types.ObjectValueMust(objectType, map[string]attr.Value{"name": types.StringValue("a"), "enabled": types.BoolNull()}),
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := nullUnknownValue(test.value); !got.Equal(test.want) {
+				t.Errorf("nullUnknownValue(%s) = %s, want %s", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNullUnknownValuesModel(t *testing.T) {
+	model := WorkspaceVcsResourceModel{
+		ID:          types.StringValue("workspace"),
+		VcsId:       types.StringUnknown(),
+		TagNames:    types.SetUnknown(types.StringType),
+		Environment: types.MapUnknown(types.StringType),
+		Access:      types.ListUnknown(workspaceAccessType),
+	}
+
+	nullUnknownValues(&model)
+
+	if model.ID.ValueString() != "workspace" {
+		t.Errorf("id = %s, want workspace", model.ID)
+	}
+	for name, value := range map[string]attr.Value{"vcs_id": model.VcsId, "tag_names": model.TagNames, "environment": model.Environment, "access": model.Access} {
+		if !value.IsNull() {
+			t.Errorf("%s = %s, want null", name, value)
+		}
+	}
+}
+
+// A workspace or VCS connection created with a response that can't be parsed
+// must be saved in state with its id, so the next apply doesn't create it again.
+func TestCreateMalformedResponse(t *testing.T) {
+	resources := []struct {
+		name        string
+		newResource func() resource.Resource
+		attributes  map[string]string
+		unknown     []string
+	}{
+		{
+			name:        "vcs",
+			newResource: NewVcsResource,
+			attributes:  map[string]string{"organization_id": "org", "name": "github", "vcs_type": "GITHUB", "client_id": "client"},
+			unknown:     []string{"id"},
+		},
+		{
+			name:        "workspace_vcs",
+			newResource: NewWorkspaceVcsResource,
+			attributes:  map[string]string{"organization_id": "org", "name": "workspace", "repository": "https://github.com/example/repo.git", "branch": "main", "template_id": "template", "iac_type": "terraform", "iac_version": "1.5.7"},
+			unknown:     []string{"id", "vcs_id", "web_url", "tag_names", "environment", "access"},
+		},
+	}
+
+	bodies := []struct {
+		name     string
+		location string
+		body     string
+	}{
+		{name: "invalid attributes", body: `{"data":{"type":"object","id":"created-id","attributes":{"name":1}}}`},
+		{name: "truncated with location", location: "/api/v1/organization/org/object/created-id", body: `{"data":{"type":"object","id":`},
+	}
+
+	for _, test := range resources {
+		for _, body := range bodies {
+			t.Run(test.name+"/"+body.name, func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method != http.MethodPost {
+						t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+						w.WriteHeader(http.StatusMethodNotAllowed)
+						return
+					}
+					if body.location != "" {
+						w.Header().Set("Location", body.location)
+					}
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(body.body))
+				}))
+				defer server.Close()
+
+				r := test.newResource()
+				configureTestResource(t, r, server)
+
+				plan := newTestPlan(t, r, test.attributes, test.unknown...)
+				resp := resource.CreateResponse{State: newTestState(t, r, nil)}
+				r.Create(context.Background(), resource.CreateRequest{Plan: plan}, &resp)
+
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				if resp.Diagnostics.WarningsCount() == 0 {
+					t.Errorf("expected a warning about the response")
+				}
+
+				var id types.String
+				resp.Diagnostics.Append(resp.State.GetAttribute(context.Background(), path.Root("id"), &id)...)
+				if id.ValueString() != "created-id" {
+					t.Errorf("id = %s, want created-id", id)
+				}
+				if !resp.State.Raw.IsFullyKnown() {
+					t.Errorf("state has unknown values: %s", resp.State.Raw)
+				}
+			})
+		}
+	}
+}
@@ -5,12 +5,21 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -19,6 +28,7 @@ import (
 
 // Ensure TerrakubeProvider satisfies various provider interfaces.
 var _ provider.Provider = &TerrakubeProvider{}
+var _ provider.ProviderWithFunctions = &TerrakubeProvider{}
 
 // TerrakubeProvider defines the provider implementation.
 type TerrakubeProvider struct {
@@ -30,15 +40,32 @@ type TerrakubeProvider struct {
 
 // hashicupsProviderModel maps provider schema data to a Go type.
 type TerrakubeProviderModel struct {
-	Endpoint           types.String `tfsdk:"endpoint"`
-	Token              types.String `tfsdk:"token"`
-	InsecureHttpClient types.Bool   `tfsdk:"insecure_http_client"`
+	Endpoint               types.String `tfsdk:"endpoint"`
+	Token                  types.String `tfsdk:"token"`
+	InsecureHttpClient     types.Bool   `tfsdk:"insecure_http_client"`
+	MaxConcurrentRequests  types.Int64  `tfsdk:"max_concurrent_requests"`
+	ValidateReferences     types.Bool   `tfsdk:"validate_references"`
+	DefaultChangeReason    types.String `tfsdk:"default_change_reason"`
+	UiEndpoint             types.String `tfsdk:"ui_endpoint"`
+	AdditionalHeaders      types.Map    `tfsdk:"additional_headers"`
+	FetchWorkspaceStatus   types.Bool   `tfsdk:"fetch_workspace_status"`
+	OrganizationId         types.String `tfsdk:"organization_id"`
+	EnableRawPayloadExport types.Bool   `tfsdk:"enable_raw_payload_export"`
+	NetworkProtocol        types.String `tfsdk:"network_protocol"`
+	DialTimeoutSeconds     types.Int64  `tfsdk:"dial_timeout_seconds"`
 }
 
 type TerrakubeConnectionData struct {
-	Endpoint           string
-	Token              string
-	InsecureHttpClient bool
+	Endpoint               string
+	UiEndpoint             string
+	Token                  string
+	InsecureHttpClient     bool
+	ValidateReferences     bool
+	FetchWorkspaceStatus   bool
+	OrganizationId         string
+	EnableRawPayloadExport bool
+	Client                 *http.Client
+	WorkspaceVariables     *workspaceVariableCache
 }
 
 func New(version string) func() provider.Provider {
@@ -57,18 +84,69 @@ func (p *TerrakubeProvider) Metadata(ctx context.Context, req provider.MetadataR
 func (p *TerrakubeProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"additional_headers": schema.MapAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "Headers sent on every request to the Terrakube API, for example the headers required by an API gateway in front of Terrakube. `Authorization` and `Content-Type` can't be set.",
+			},
+			"enable_raw_payload_export": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Allow the `terrakube_raw_object` data source to read the JSON:API objects returned by the Terrakube API, to attach them to Terrakube issues, default is `false`. Secrets and values of sensitive variables are redacted.",
+			},
 			"endpoint": schema.StringAttribute{
 				Optional:    true,
-				Description: "Terrakube API Endpoint. Example: https://terrakube-api.minikube.net, can also be specified with environment variable `TERRAKUBE_ENDPOINT`.",
+				Description: "Terrakube API Endpoint. Example: https://terrakube-api.minikube.net, can also be specified with environment variable `TERRAKUBE_ENDPOINT`. The endpoint may include the path prefix of a gateway, for example https://tools.example.com/terrakube, trailing slashes and a trailing `/api/v1` are removed.",
 			},
 			"token": schema.StringAttribute{
 				Optional:    true,
 				Description: "Access Token generated in Terrakube UI (https://docs.terrakube.io/user-guide/organizations/api-tokens), can also be specificed with environment variable `TERRAKUBE_TOKEN`.",
 			},
+			"fetch_workspace_status": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Read the latest state of every workspace during refresh to set `current_state_serial` on the workspace resources, default is `false`. It adds one request per workspace.",
+			},
 			"insecure_http_client": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Disable https certificate validation, default is `false`.",
 			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of concurrent requests sent to the Terrakube API, default is `0` (unlimited).",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"network_protocol": schema.StringAttribute{
+				Optional:    true,
+				Description: "Address family used to connect to the Terrakube API, one of `auto`, `ipv4` or `ipv6`, default is `auto`. Set `ipv4` or `ipv6` when the endpoint resolves to both IPv4 and IPv6 addresses but only one of them is reachable, to avoid waiting on the unreachable one.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(networkProtocolAuto, networkProtocolIPv4, networkProtocolIPv6),
+				},
+			},
+			"dial_timeout_seconds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Timeout in seconds to open a connection to the Terrakube API, default is `30`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"organization_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Organization id used by the resources that don't set `organization_id`, can also be specified with environment variable `TERRAKUBE_ORGANIZATION_ID`. The provider checks that the token can read the organization when it is configured. Use a provider alias per organization to manage several organizations.",
+			},
+			"default_change_reason": schema.StringAttribute{
+				Optional:    true,
+				Description: "Change reason, for example a change ticket number, sent in the `X-Change-Reason` header of every request that creates, updates or deletes objects, can also be specified with environment variable `TERRAKUBE_CHANGE_REASON`. Use a provider alias with its own reason for the resources that need a different one.",
+			},
+			"ui_endpoint": schema.StringAttribute{
+				Optional:    true,
+				Description: "Terrakube UI Endpoint used to build workspace links. Example: https://terrakube-ui.minikube.net, can also be specified with environment variable `TERRAKUBE_UI_ENDPOINT`. Defaults to the scheme and host of `endpoint`.",
+			},
+			"validate_references": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Check during apply that the templates referenced by workspaces and webhooks belong to the same organization and that the VCS connection of a workspace is connected, default is `true`. Disable it when the token can't read organization templates or VCS connections.",
+			},
 		},
 	}
 }
@@ -101,6 +179,44 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 		)
 	}
 
+	if config.OrganizationId.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("organization_id"),
+			"Unknown Terrakube organization",
+			"The provider cannot use the default organization as there is an unknown configuration value for the organization id. "+
+				"Either target apply the source of the value first, set the value statically in the configuration, or use the TERRAKUBE_ORGANIZATION_ID environment variable.",
+		)
+	}
+
+	if config.AdditionalHeaders.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("additional_headers"),
+			"Unknown Terrakube API headers",
+			"The provider cannot create the Terrakube API client as there is an unknown configuration value for the additional headers. "+
+				"Either target apply the source of the value first or set the value statically in the configuration.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	additionalHeaders := map[string]string{}
+	if !config.AdditionalHeaders.IsNull() {
+		resp.Diagnostics.Append(config.AdditionalHeaders.ElementsAs(ctx, &additionalHeaders, false)...)
+	}
+
+	for name := range additionalHeaders {
+		switch http.CanonicalHeaderKey(name) {
+		case "Authorization", "Content-Type":
+			resp.Diagnostics.AddAttributeError(
+				path.Root("additional_headers"),
+				"Invalid Terrakube API header",
+				fmt.Sprintf("The %s header is set by the provider and can't be used in additional_headers.", name),
+			)
+		}
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -110,7 +226,16 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 
 	endpoint := os.Getenv("TERRAKUBE_ENDPOINT")
 	token := os.Getenv("TERRAKUBE_TOKEN")
+	changeReason := os.Getenv("TERRAKUBE_CHANGE_REASON")
+	uiEndpoint := os.Getenv("TERRAKUBE_UI_ENDPOINT")
+	organizationId := os.Getenv("TERRAKUBE_ORGANIZATION_ID")
 	insecureHttpClient := false
+	maxConcurrentRequests := 0
+	validateReferences := true
+	fetchWorkspaceStatus := false
+	enableRawPayloadExport := false
+	networkProtocol := networkProtocolAuto
+	var dialTimeout time.Duration
 
 	if !config.Endpoint.IsNull() {
 		endpoint = config.Endpoint.ValueString()
@@ -124,6 +249,42 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 		insecureHttpClient = config.InsecureHttpClient.ValueBool()
 	}
 
+	if !config.MaxConcurrentRequests.IsNull() {
+		maxConcurrentRequests = int(config.MaxConcurrentRequests.ValueInt64())
+	}
+
+	if !config.UiEndpoint.IsNull() {
+		uiEndpoint = config.UiEndpoint.ValueString()
+	}
+
+	if !config.OrganizationId.IsNull() {
+		organizationId = config.OrganizationId.ValueString()
+	}
+
+	if !config.DefaultChangeReason.IsNull() {
+		changeReason = config.DefaultChangeReason.ValueString()
+	}
+
+	if !config.ValidateReferences.IsNull() {
+		validateReferences = config.ValidateReferences.ValueBool()
+	}
+
+	if !config.FetchWorkspaceStatus.IsNull() {
+		fetchWorkspaceStatus = config.FetchWorkspaceStatus.ValueBool()
+	}
+
+	if !config.EnableRawPayloadExport.IsNull() {
+		enableRawPayloadExport = config.EnableRawPayloadExport.ValueBool()
+	}
+
+	if !config.NetworkProtocol.IsNull() {
+		networkProtocol = config.NetworkProtocol.ValueString()
+	}
+
+	if !config.DialTimeoutSeconds.IsNull() {
+		dialTimeout = time.Duration(config.DialTimeoutSeconds.ValueInt64()) * time.Second
+	}
+
 	// If any of the expected configurations are missing, return
 	// errors with provider-specific guidance.
 
@@ -137,6 +298,21 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 		)
 	}
 
+	endpointHost := ""
+	if endpoint != "" {
+		parsedEndpoint, err := normalizeEndpoint(endpoint)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Invalid Terrakube API Host",
+				fmt.Sprintf("The Terrakube API host %q must be an absolute http or https URL, for example https://terrakube-api.example.com or https://tools.example.com/terrakube: %s.", endpoint, err),
+			)
+		} else {
+			endpoint = parsedEndpoint.String()
+			endpointHost = parsedEndpoint.Hostname()
+		}
+	}
+
 	if token == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
@@ -154,8 +330,45 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 	connection := new(TerrakubeConnectionData)
 
 	connection.Endpoint = endpoint
+	connection.UiEndpoint = uiEndpoint
+	if uiEndpoint == "" {
+		connection.UiEndpoint = uiEndpointFromApi(endpoint)
+	}
 	connection.Token = token
 	connection.InsecureHttpClient = insecureHttpClient
+	connection.ValidateReferences = validateReferences
+	connection.FetchWorkspaceStatus = fetchWorkspaceStatus
+	connection.OrganizationId = organizationId
+	connection.EnableRawPayloadExport = enableRawPayloadExport
+	connection.WorkspaceVariables = newWorkspaceVariableCache()
+
+	transport := newBaseTransport(insecureHttpClient, networkProtocol, dialTimeout)
+	transport = client.NewRecordTransport(transport, os.Getenv("TERRAKUBE_PROVIDER_RECORD_DIR"), helpers.RedactPayload)
+	transport = client.NewChangeReasonTransport(transport, changeReason)
+	transport = client.NewHeadersTransport(transport, additionalHeaders)
+	connection.Client = &http.Client{
+		Transport:     client.NewLimitedTransport(transport, maxConcurrentRequests),
+		CheckRedirect: client.NewCheckRedirect(endpointHost),
+	}
+
+	if organizationId != "" {
+		if err := checkOrganizationAccess(connection.Client, endpoint, token, organizationId); err != nil {
+			if client.IsUnauthorized(err) || client.IsNotFound(err) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("organization_id"),
+					"Terrakube organization not accessible",
+					fmt.Sprintf("The token has no access to organization %s, check the organization id and that the token belongs to a team of the organization: %s", organizationId, err),
+				)
+			} else {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("organization_id"),
+					"Unable to read Terrakube organization",
+					fmt.Sprintf("Error reading organization %s: %s", organizationId, err),
+				)
+			}
+			return
+		}
+	}
 
 	resp.DataSourceData = connection
 	resp.ResourceData = connection
@@ -163,6 +376,12 @@ func (p *TerrakubeProvider) Configure(ctx context.Context, req provider.Configur
 	ctx = tflog.SetField(ctx, "terrakube_endpoint", endpoint)
 	ctx = tflog.SetField(ctx, "terrakube_token", token)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "terrakube_token")
+	// Header values are never logged, they often hold gateway credentials
+	headerNames := make([]string, 0, len(additionalHeaders))
+	for name := range additionalHeaders {
+		headerNames = append(headerNames, name)
+	}
+	ctx = tflog.SetField(ctx, "terrakube_additional_headers", headerNames)
 
 	tflog.Info(ctx, "Creating Terrakube client information", map[string]any{"success": true})
 }
@@ -194,8 +413,23 @@ func (p *TerrakubeProvider) DataSources(ctx context.Context) []func() datasource
 	return []func() datasource.DataSource{
 		NewOrganizationDataSource,
 		NewOrganizationTemplateDataSource,
+		NewOrganizationTemplateStepsDataSource,
 		NewOrganizationTagDataSource,
 		NewVcsDataSource,
 		NewSshDataSource,
+		NewJobDataSource,
+		NewModulesDataSource,
+		NewWorkspacesDataSource,
+		NewCurrentIdentityDataSource,
+		NewCollectionItemsDataSource,
+		NewWorkspaceWebhookEventsDataSource,
+		NewRawObjectDataSource,
+	}
+}
+
+func (p *TerrakubeProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewModuleSourceFunction,
+		NewWorkspaceUrlFunction,
 	}
 }
@@ -0,0 +1,461 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// fakeObject is a JSON:API resource object stored by fakeTerrakube.
+type fakeObject struct {
+	Type          string         `json:"type"`
+	ID            string         `json:"id"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+	Relationships map[string]any `json:"relationships,omitempty"`
+}
+
+// fakeTerrakube is an in-memory Terrakube API. Objects are stored by their
+// path below /api/v1, collections are listed without applying filters, and
+// the objects of a missing or deleted workspace can't be read or written,
+// like the API does once a workspace is deleted.
+type fakeTerrakube struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	objects  map[string]*fakeObject
+	requests []string
+	ids      int
+}
+
+func newFakeTerrakube(t *testing.T) *fakeTerrakube {
+	t.Helper()
+
+	fake := &fakeTerrakube{objects: map[string]*fakeObject{}}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(fake.Close)
+
+	return fake
+}
+
+// add stores an object, for example the organization and templates the
+// configuration refers to.
+func (f *fakeTerrakube) add(path string, object fakeObject) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.objects[path] = &object
+}
+
+// get returns the object stored at path, or nil.
+func (f *fakeTerrakube) get(path string) *fakeObject {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.objects[path]
+}
+
+// list returns the paths of the objects stored below prefix.
+func (f *fakeTerrakube) list(prefix string) []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var paths []string
+	for path := range f.objects {
+		if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// writes returns the POST, PATCH and DELETE requests received after the
+// first requests of the log.
+func (f *fakeTerrakube) writes(first int) []string {
+	var writes []string
+	for _, request := range f.log()[first:] {
+		if !strings.HasPrefix(request, http.MethodGet+" ") {
+			writes = append(writes, request)
+		}
+	}
+
+	return writes
+}
+
+// log returns the requests received so far as "METHOD path".
+func (f *fakeTerrakube) log() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return append([]string{}, f.requests...)
+}
+
+func (f *fakeTerrakube) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/")
+
+	if len(segments) > 4 && segments[0] == "organization" && segments[2] == "workspace" {
+		workspace := f.objects[strings.Join(segments[:4], "/")]
+		if workspace == nil || workspace.Attributes["deleted"] == true {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"errors":[{"detail":"workspace is being deleted"}]}`))
+			return
+		}
+	}
+
+	path := strings.Join(segments, "/")
+	if strings.Contains(path, "/relationships/") {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	collection := len(segments)%2 == 1
+	object := f.objects[path]
+	switch {
+	case r.Method == http.MethodGet && collection:
+		items := []*fakeObject{}
+		for _, itemPath := range f.sortedPaths() {
+			if strings.HasPrefix(itemPath, path+"/") && !strings.Contains(strings.TrimPrefix(itemPath, path+"/"), "/") {
+				items = append(items, f.objects[itemPath])
+			}
+		}
+		writeFakeData(w, http.StatusOK, items)
+	case r.Method == http.MethodPost && collection:
+		var payload struct{ Data fakeObject }
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.ids++
+		payload.Data.ID = fmt.Sprintf("%s-%d", payload.Data.Type, f.ids)
+		f.objects[path+"/"+payload.Data.ID] = &payload.Data
+		writeFakeData(w, http.StatusCreated, payload.Data)
+	case object == nil:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"detail":"not found"}]}`))
+	case r.Method == http.MethodGet:
+		writeFakeData(w, http.StatusOK, object)
+	case r.Method == http.MethodPatch:
+		var payload struct{ Data fakeObject }
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if object.Attributes == nil {
+			object.Attributes = map[string]any{}
+		}
+		for name, value := range payload.Data.Attributes {
+			object.Attributes[name] = value
+		}
+		writeFakeData(w, http.StatusOK, object)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeTerrakube) sortedPaths() []string {
+	paths := make([]string, 0, len(f.objects))
+	for path := range f.objects {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+func writeFakeData(w http.ResponseWriter, status int, data any) {
+	body, _ := json.Marshal(map[string]any{"data": data})
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// testProviderServer drives the provider through the plugin protocol, the way
+// Terraform does, against an API server.
+type testProviderServer struct {
+	t       *testing.T
+	server  tfprotov6.ProviderServer
+	schemas map[string]*tfprotov6.Schema
+}
+
+// testResourceInstance is the state of a resource managed by testProviderServer.
+type testResourceInstance struct {
+	typeName string
+	state    tftypes.Value
+	private  []byte
+}
+
+func newTestProviderServer(t *testing.T, endpoint string) *testProviderServer {
+	t.Helper()
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil || testHasError(schemaResp.Diagnostics) {
+		t.Fatalf("GetProviderSchema: %v %v", err, testDiagnostics(schemaResp.Diagnostics))
+	}
+
+	s := &testProviderServer{t: t, server: server, schemas: schemaResp.ResourceSchemas}
+	config := testSchemaValue(t, schemaResp.Provider, map[string]tftypes.Value{
+		"endpoint": tftypes.NewValue(tftypes.String, endpoint),
+		"token":    tftypes.NewValue(tftypes.String, "test-token"),
+	})
+	configureResp, err := server.ConfigureProvider(context.Background(), &tfprotov6.ConfigureProviderRequest{Config: s.dynamicValue(config)})
+	if err != nil || testHasError(configureResp.Diagnostics) {
+		t.Fatalf("ConfigureProvider: %v %v", err, testDiagnostics(configureResp.Diagnostics))
+	}
+
+	return s
+}
+
+// testSchemaValue returns an object of the schema with the attributes set,
+// every other attribute is null.
+func testSchemaValue(t *testing.T, schema *tfprotov6.Schema, attributes map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	objectType := schema.ValueType().(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range attributes {
+		if _, ok := values[name]; !ok {
+			t.Fatalf("unknown attribute %s", name)
+		}
+		values[name] = value
+	}
+
+	return tftypes.NewValue(objectType, values)
+}
+
+func testHasError(diagnostics []*tfprotov6.Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *testProviderServer) dynamicValue(value tftypes.Value) *tfprotov6.DynamicValue {
+	s.t.Helper()
+
+	dynamicValue, err := tfprotov6.NewDynamicValue(value.Type(), value)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	return &dynamicValue
+}
+
+func (s *testProviderServer) value(typeName string, dynamicValue *tfprotov6.DynamicValue) tftypes.Value {
+	s.t.Helper()
+
+	value, err := dynamicValue.Unmarshal(s.schemas[typeName].ValueType())
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	return value
+}
+
+// config returns the configuration of a resource with the attributes set.
+func (s *testProviderServer) config(typeName string, attributes map[string]tftypes.Value) tftypes.Value {
+	s.t.Helper()
+
+	return testSchemaValue(s.t, s.schemas[typeName], attributes)
+}
+
+// proposedNewState merges the configuration with the prior state like
+// Terraform does: computed attributes that are not configured keep their
+// prior value.
+func (s *testProviderServer) proposedNewState(typeName string, prior tftypes.Value, config tftypes.Value) tftypes.Value {
+	s.t.Helper()
+
+	if prior.IsNull() {
+		return config
+	}
+
+	var priorValues, configValues map[string]tftypes.Value
+	if err := prior.As(&priorValues); err != nil {
+		s.t.Fatal(err)
+	}
+	if err := config.As(&configValues); err != nil {
+		s.t.Fatal(err)
+	}
+	for _, attribute := range s.schemas[typeName].Block.Attributes {
+		if attribute.Computed && configValues[attribute.Name].IsNull() {
+			configValues[attribute.Name] = priorValues[attribute.Name]
+		}
+	}
+
+	return tftypes.NewValue(config.Type(), configValues)
+}
+
+// plan plans the change of the resource to the configuration, instance is nil
+// for a resource that doesn't exist yet.
+func (s *testProviderServer) plan(typeName string, instance *testResourceInstance, config tftypes.Value) *tfprotov6.PlanResourceChangeResponse {
+	s.t.Helper()
+
+	prior, private := tftypes.NewValue(s.schemas[typeName].ValueType(), nil), []byte(nil)
+	if instance != nil {
+		prior, private = instance.state, instance.private
+	}
+
+	resp, err := s.server.PlanResourceChange(context.Background(), &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       s.dynamicValue(prior),
+		ProposedNewState: s.dynamicValue(s.proposedNewState(typeName, prior, config)),
+		Config:           s.dynamicValue(config),
+		PriorPrivate:     private,
+	})
+	if err != nil || testHasError(resp.Diagnostics) {
+		s.t.Fatalf("PlanResourceChange %s: %v %v", typeName, err, testDiagnostics(resp.Diagnostics))
+	}
+
+	return resp
+}
+
+// apply plans and applies the configuration, instance is nil to create the
+// resource and is updated in place otherwise.
+func (s *testProviderServer) apply(typeName string, instance *testResourceInstance, config tftypes.Value) *testResourceInstance {
+	s.t.Helper()
+
+	plan := s.plan(typeName, instance, config)
+	if instance != nil && len(plan.RequiresReplace) > 0 {
+		s.t.Fatalf("%s requires replacement, %v", typeName, plan.RequiresReplace)
+	}
+	if instance != nil && len(s.changes(instance, plan)) == 0 {
+		return instance
+	}
+
+	prior, private := tftypes.NewValue(s.schemas[typeName].ValueType(), nil), plan.PlannedPrivate
+	if instance != nil {
+		prior = instance.state
+	}
+
+	resp, err := s.server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       typeName,
+		PriorState:     s.dynamicValue(prior),
+		PlannedState:   plan.PlannedState,
+		Config:         s.dynamicValue(config),
+		PlannedPrivate: private,
+	})
+	if err != nil || testHasError(resp.Diagnostics) {
+		s.t.Fatalf("ApplyResourceChange %s: %v %v", typeName, err, testDiagnostics(resp.Diagnostics))
+	}
+
+	// Terraform rejects a new state that doesn't keep the known planned values.
+	state := s.value(typeName, resp.NewState)
+	diffs, err := s.value(typeName, plan.PlannedState).Diff(state)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	for _, diff := range diffs {
+		if len(diff.Path.Steps()) > 0 && diff.Value1 != nil && diff.Value1.IsFullyKnown() {
+			s.t.Errorf("%s produced an inconsistent result, %s: planned %v, applied %v", typeName, diff.Path, diff.Value1, diff.Value2)
+		}
+	}
+
+	return &testResourceInstance{typeName: typeName, state: state, private: resp.Private}
+}
+
+// changes returns the paths of the attributes the plan changes, Terraform
+// doesn't apply a plan without changes.
+func (s *testProviderServer) changes(instance *testResourceInstance, plan *tfprotov6.PlanResourceChangeResponse) []string {
+	s.t.Helper()
+
+	diffs, err := instance.state.Diff(s.value(instance.typeName, plan.PlannedState))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	var paths []string
+	for _, diff := range diffs {
+		if len(diff.Path.Steps()) > 0 {
+			paths = append(paths, fmt.Sprintf("%s: %v => %v", diff.Path, diff.Value1, diff.Value2))
+		}
+	}
+
+	return paths
+}
+
+// destroy deletes the resource.
+func (s *testProviderServer) destroy(instance *testResourceInstance) {
+	s.t.Helper()
+
+	null := tftypes.NewValue(s.schemas[instance.typeName].ValueType(), nil)
+	resp, err := s.server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       instance.typeName,
+		PriorState:     s.dynamicValue(instance.state),
+		PlannedState:   s.dynamicValue(null),
+		Config:         s.dynamicValue(null),
+		PlannedPrivate: instance.private,
+	})
+	if err != nil || testHasError(resp.Diagnostics) {
+		s.t.Fatalf("ApplyResourceChange destroy %s: %v %v", instance.typeName, err, testDiagnostics(resp.Diagnostics))
+	}
+}
+
+// refresh reads the resource.
+func (s *testProviderServer) refresh(instance *testResourceInstance) {
+	s.t.Helper()
+
+	resp, err := s.server.ReadResource(context.Background(), &tfprotov6.ReadResourceRequest{
+		TypeName:     instance.typeName,
+		CurrentState: s.dynamicValue(instance.state),
+		Private:      instance.private,
+	})
+	if err != nil || testHasError(resp.Diagnostics) {
+		s.t.Fatalf("ReadResource %s: %v %v", instance.typeName, err, testDiagnostics(resp.Diagnostics))
+	}
+
+	instance.state, instance.private = s.value(instance.typeName, resp.NewState), resp.Private
+}
+
+// attribute returns a string attribute of the resource state.
+func (instance *testResourceInstance) attribute(t *testing.T, name string) string {
+	t.Helper()
+
+	var values map[string]tftypes.Value
+	if err := instance.state.As(&values); err != nil {
+		t.Fatal(err)
+	}
+
+	var value string
+	if err := values[name].As(&value); err != nil {
+		t.Fatalf("%s.%s: %s", instance.typeName, name, err)
+	}
+
+	return value
+}
+
+func testDiagnostics(diagnostics []*tfprotov6.Diagnostic) []string {
+	var messages []string
+	for _, diagnostic := range diagnostics {
+		messages = append(messages, fmt.Sprintf("%s: %s: %s", diagnostic.Severity, diagnostic.Summary, diagnostic.Detail))
+	}
+
+	return messages
+}
@@ -1 +1,84 @@
 package provider
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// configureTestResource configures the resource to send its requests to server.
+func configureTestResource(t *testing.T, r resource.Resource, server *httptest.Server) {
+	t.Helper()
+
+	configurable, ok := r.(resource.ResourceWithConfigure)
+	if !ok {
+		t.Fatalf("%T doesn't implement resource.ResourceWithConfigure", r)
+	}
+
+	var resp resource.ConfigureResponse
+	configurable.Configure(context.Background(), resource.ConfigureRequest{
+		ProviderData: &TerrakubeConnectionData{
+			Endpoint:           server.URL,
+			Token:              "test-token",
+			Client:             server.Client(),
+			WorkspaceVariables: newWorkspaceVariableCache(),
+		},
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected configure diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+// newTestState returns a state of the resource with the string attributes set,
+// every other attribute is null.
+func newTestState(t *testing.T, r resource.Resource, attributes map[string]string) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	for name, value := range attributes {
+		if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+			t.Fatalf("unable to set %s: %v", name, diags)
+		}
+	}
+
+	return state
+}
+
+// newTestPlan returns a plan of the resource with the string attributes set and
+// the unknown attributes unknown, every other attribute is null.
+func newTestPlan(t *testing.T, r resource.Resource, attributes map[string]string, unknown ...string) tfsdk.Plan {
+	t.Helper()
+	ctx := context.Background()
+
+	state := newTestState(t, r, attributes)
+	for _, name := range unknown {
+		attributeType, diags := state.Schema.TypeAtPath(ctx, path.Root(name))
+		if diags.HasError() {
+			t.Fatalf("unable to read the type of %s: %v", name, diags)
+		}
+		value, err := attributeType.ValueFromTerraform(ctx, tftypes.NewValue(attributeType.TerraformType(ctx), tftypes.UnknownValue))
+		if err != nil {
+			t.Fatalf("unable to build unknown %s: %s", name, err)
+		}
+		if diags := state.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+			t.Fatalf("unable to set %s: %v", name, diags)
+		}
+	}
+
+	return tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// rawObjectParent is the object an API object is nested under.
+type rawObjectParent int
+
+const (
+	rawObjectParentNone rawObjectParent = iota
+	rawObjectParentOrganization
+	rawObjectParentWorkspace
+)
+
+// rawObjectType is the API path of an object type, relative to its parent.
+type rawObjectType struct {
+	parent rawObjectParent
+	path   string
+}
+
+// rawObjectTypes are the object types that can be exported, named after the
+// resource managing them.
+var rawObjectTypes = map[string]rawObjectType{
+	"organization":          {rawObjectParentNone, "organization"},
+	"collection":            {rawObjectParentOrganization, "collection"},
+	"module":                {rawObjectParentOrganization, "module"},
+	"organization_tag":      {rawObjectParentOrganization, "tag"},
+	"organization_template": {rawObjectParentOrganization, "template"},
+	"organization_variable": {rawObjectParentOrganization, "globalvar"},
+	"self_hosted_agent":     {rawObjectParentOrganization, "agent"},
+	"team":                  {rawObjectParentOrganization, "team"},
+	"vcs":                   {rawObjectParentOrganization, "vcs"},
+	"workspace":             {rawObjectParentOrganization, "workspace"},
+	"workspace_schedule":    {rawObjectParentWorkspace, "schedule"},
+	"workspace_tag":         {rawObjectParentWorkspace, "workspaceTag"},
+	"workspace_variable":    {rawObjectParentWorkspace, "variable"},
+	"workspace_webhook":     {rawObjectParentWorkspace, "webhook"},
+}
+
+var (
+	_ datasource.DataSource              = &RawObjectDataSource{}
+	_ datasource.DataSourceWithConfigure = &RawObjectDataSource{}
+)
+
+type RawObjectDataSourceModel struct {
+	Type           types.String `tfsdk:"type"`
+	ID             types.String `tfsdk:"id"`
+	OrganizationId types.String `tfsdk:"organization_id"`
+	WorkspaceId    types.String `tfsdk:"workspace_id"`
+	Json           types.String `tfsdk:"json"`
+}
+
+type RawObjectDataSource struct {
+	client                *http.Client
+	endpoint              string
+	token                 string
+	enabled               bool
+	defaultOrganizationId string
+}
+
+func NewRawObjectDataSource() datasource.DataSource {
+	return &RawObjectDataSource{}
+}
+
+func (d *RawObjectDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Raw Object Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+	d.enabled = providerData.EnableRawPayloadExport
+	d.defaultOrganizationId = providerData.OrganizationId
+
+	ctx = tflog.SetField(ctx, "endpoint", d.endpoint)
+	ctx = tflog.SetField(ctx, "token", d.token)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "token")
+	tflog.Info(ctx, "Raw Object Data Source configured")
+}
+
+func (d *RawObjectDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_raw_object"
+}
+
+func (d *RawObjectDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	names := make([]string, 0, len(rawObjectTypes))
+	for name := range rawObjectTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp.Schema = schema.Schema{
+		Description: "Read the JSON:API object returned by the Terrakube API for an object, to attach it to a Terrakube issue. Secrets and values of sensitive variables are redacted. Requires the provider `enable_raw_payload_export` to be `true`.",
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: "Object type, named after the resource managing it, one of `" + strings.Join(names, "`, `") + "`",
+				Validators: []validator.String{
+					stringvalidator.OneOf(names...),
+				},
+			},
+			"id": schema.StringAttribute{
+				Required:    true,
+				Description: "Object ID",
+			},
+			"organization_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Organization ID of the object, defaults to the provider `organization_id`. Not used for `organization`.",
+			},
+			"workspace_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Workspace ID of the object, required for the `workspace_*` types except `workspace`",
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Description: "Redacted JSON:API object returned by the API",
+			},
+		},
+	}
+}
+
+func (d *RawObjectDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state RawObjectDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !d.enabled {
+		resp.Diagnostics.AddError("Raw payload export disabled", "Set enable_raw_payload_export = true on the provider to read raw API objects.")
+		return
+	}
+
+	objectType := rawObjectTypes[state.Type.ValueString()]
+
+	organizationId := state.OrganizationId.ValueString()
+	if state.OrganizationId.IsNull() {
+		organizationId = d.defaultOrganizationId
+	}
+
+	parts := []string{}
+	switch objectType.parent {
+	case rawObjectParentOrganization, rawObjectParentWorkspace:
+		if organizationId == "" {
+			resp.Diagnostics.AddAttributeError(path.Root("organization_id"), "Missing organization id", fmt.Sprintf("organization_id is required for type %s when the provider organization_id is not set.", state.Type.ValueString()))
+			return
+		}
+		parts = append(parts, "organization", url.PathEscape(organizationId))
+	}
+	if objectType.parent == rawObjectParentWorkspace {
+		if state.WorkspaceId.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("workspace_id"), "Missing workspace id", fmt.Sprintf("workspace_id is required for type %s.", state.Type.ValueString()))
+			return
+		}
+		parts = append(parts, "workspace", url.PathEscape(state.WorkspaceId.ValueString()))
+	}
+	parts = append(parts, objectType.path, url.PathEscape(state.ID.ValueString()))
+
+	request, err := http.NewRequest(http.MethodGet, apiURL(d.endpoint, parts...), nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating raw object request", err.Error())
+		return
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing raw object request", err.Error())
+		return
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading raw object response body", err.Error())
+		return
+	}
+
+	if err = client.CheckResponse(response, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error reading raw object", fmt.Sprintf("Error reading %s %s: %s", state.Type.ValueString(), state.ID.ValueString(), err))
+		return
+	}
+
+	state.Json = types.StringValue(helpers.RedactPayload(string(bodyResponse)))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// Renamed attributes are kept next to their new name until the next major
+// version. Both attributes are Optional and Computed and always hold the same
+// value: the one that is configured is copied into the other by
+// renamedAttributePlanModifier, so switching from the old name to the new one
+// doesn't produce a diff. The old attribute has a DeprecationMessage and a
+// Conflicting config validator rejects setting both.
+
+// renamedAttributeDeprecation returns the deprecation message of an attribute replaced by newName.
+func renamedAttributeDeprecation(newName string) string {
+	return fmt.Sprintf("Use %s instead, this attribute will be removed in the next major version.", newName)
+}
+
+// renamedAttributePlanModifier plans the attribute with the configured value of
+// its other name, or defaultValue when neither name is configured.
+func renamedAttributePlanModifier(otherName string, defaultValue string) planmodifier.String {
+	return renamedAttributeModifier{otherName: otherName, defaultValue: defaultValue}
+}
+
+type renamedAttributeModifier struct {
+	otherName    string
+	defaultValue string
+}
+
+func (m renamedAttributeModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("Uses the value of %s when this attribute is not configured.", m.otherName)
+}
+
+func (m renamedAttributeModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m renamedAttributeModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+
+	var other types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(m.otherName), &other)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if other.IsNull() {
+		resp.PlanValue = types.StringValue(m.defaultValue)
+		return
+	}
+
+	resp.PlanValue = other
+}
+
+// renamedAttributeStateUpgrader copies the value of oldName into newName in a
+// state written before newName existed.
+func renamedAttributeStateUpgrader(oldName string, newName string) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			var state map[string]json.RawMessage
+			if err := json.Unmarshal(req.RawState.JSON, &state); err != nil {
+				resp.Diagnostics.AddError("Unable to upgrade state", fmt.Sprintf("Unable to read prior state: %s", err))
+				return
+			}
+
+			if value, ok := state[newName]; !ok || string(value) == "null" {
+				state[newName] = state[oldName]
+			}
+
+			upgraded, err := json.Marshal(state)
+			if err != nil {
+				resp.Diagnostics.AddError("Unable to upgrade state", fmt.Sprintf("Unable to write upgraded state: %s", err))
+				return
+			}
+
+			resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+		},
+	}
+}
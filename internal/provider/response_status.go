@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// isGoneOrDeleted reports whether a response status means the object no longer
+// exists, which is treated as a successful deletion.
+func isGoneOrDeleted(status int) bool {
+	return status == http.StatusNotFound || status == http.StatusGone
+}
+
+// checkDeleteResponse returns the error of the response to a delete request.
+// An object that no longer exists, for example because it was deleted from the
+// UI, is already deleted, so a missing object is not an error.
+func checkDeleteResponse(response *http.Response, body []byte) error {
+	if isGoneOrDeleted(response.StatusCode) {
+		return nil
+	}
+
+	return client.CheckResponse(response, body)
+}
+
+// checkReadResponse maps the status of the response received while refreshing a
+// resource. It returns true when the body can be used. Authentication errors are
+// always reported and never remove the resource from state, a missing object is
+// removed from state and any other unexpected status is reported with its body.
+func checkReadResponse(ctx context.Context, resp *resource.ReadResponse, response *http.Response, body []byte, resourceName string) bool {
+	err := client.CheckResponse(response, body)
+	switch {
+	case err == nil:
+		return true
+	case client.IsUnauthorized(err):
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Error reading %s resource", resourceName),
+			fmt.Sprintf("Authentication/authorization failed reading %s resource, check the provider token: %s", resourceName, err),
+		)
+	case client.IsNotFound(err):
+		tflog.Warn(ctx, fmt.Sprintf("%s resource not found, removing from state", resourceName), map[string]any{"responseStatus": response.Status})
+		resp.State.RemoveResource(ctx)
+	default:
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Error reading %s resource", resourceName),
+			fmt.Sprintf("Error reading %s resource: %s", resourceName, err),
+		)
+	}
+
+	return false
+}
+
+// maxErrorBodyLength is the length of the response body kept in error messages.
+const maxErrorBodyLength = 1024
+
+// formatAPIError renders the status and body of an API response for error
+// messages. JSON bodies are redacted and long bodies truncated, body may be nil
+// when the response wasn't read.
+func formatAPIError(response *http.Response, body []byte) string {
+	if response == nil {
+		return "no response"
+	}
+
+	text := string(body)
+	if json.Valid(body) {
+		text = helpers.RedactPayload(text)
+	}
+	if len(text) > maxErrorBodyLength {
+		text = text[:maxErrorBodyLength] + "... (truncated)"
+	}
+	if text == "" {
+		return fmt.Sprintf("response status: %s", response.Status)
+	}
+
+	return fmt.Sprintf("response status: %s, response body: %s", response.Status, text)
+}
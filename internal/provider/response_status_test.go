@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func TestCheckDeleteResponse(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr bool
+	}{
+		{status: http.StatusOK},
+		{status: http.StatusAccepted},
+		{status: http.StatusNoContent},
+		{status: http.StatusNotFound},
+		{status: http.StatusGone},
+		{status: http.StatusBadRequest, wantErr: true},
+		{status: http.StatusUnauthorized, wantErr: true},
+		{status: http.StatusForbidden, wantErr: true},
+		{status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			err := checkDeleteResponse(&http.Response{StatusCode: test.status, Header: http.Header{}}, []byte(`{"errors":[{"detail":"failed"}]}`))
+			if (err != nil) != test.wantErr {
+				t.Errorf("checkDeleteResponse(%d) error = %v, want error %t", test.status, err, test.wantErr)
+			}
+		})
+	}
+}
+
+// deleteTestResources are resources whose Delete only sends the DELETE request.
+var deleteTestResources = []struct {
+	name        string
+	newResource func() resource.Resource
+	attributes  map[string]string
+}{
+	{name: "team", newResource: NewTeamResource, attributes: map[string]string{"id": "team", "organization_id": "org"}},
+	{name: "module", newResource: NewModuleResource, attributes: map[string]string{"id": "module", "organization_id": "org"}},
+	{name: "collection", newResource: NewCollectionResource, attributes: map[string]string{"id": "collection", "organization_id": "org"}},
+	{name: "agent", newResource: NewAgentResource, attributes: map[string]string{"id": "agent", "organization_id": "org"}},
+	{name: "workspace_schedule", newResource: NewWorkspaceScheduleResource, attributes: map[string]string{"id": "schedule", "workspace_id": "workspace"}},
+	{name: "workspace_tag", newResource: NewWorkspaceTagResource, attributes: map[string]string{"id": "tag", "organization_id": "org", "workspace_id": "workspace", "tag_id": "tag"}},
+	{name: "workspace_webhook", newResource: NewWorkspaceWebhookResource, attributes: map[string]string{"id": "webhook", "organization_id": "org", "workspace_id": "workspace"}},
+	{name: "vcs", newResource: NewVcsResource, attributes: map[string]string{"id": "vcs", "organization_id": "org"}},
+	{name: "organization_variable", newResource: NewOrganizationVariableResource, attributes: map[string]string{"id": "variable", "organization_id": "org"}},
+}
+
+func deleteTestResource(t *testing.T, r resource.Resource, attributes map[string]string) resource.DeleteResponse {
+	t.Helper()
+
+	state := newTestState(t, r, attributes)
+	resp := resource.DeleteResponse{State: state}
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+
+	return resp
+}
+
+// Deleting twice, the second time after the object was deleted from the UI,
+// must succeed.
+func TestResourceDeleteTwice(t *testing.T) {
+	for _, test := range deleteTestResources {
+		t.Run(test.name, func(t *testing.T) {
+			var mutex sync.Mutex
+			deleted := map[string]bool{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+
+				mutex.Lock()
+				defer mutex.Unlock()
+				if deleted[r.URL.Path] {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				deleted[r.URL.Path] = true
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			r := test.newResource()
+			configureTestResource(t, r, server)
+
+			for i := 0; i < 2; i++ {
+				if resp := deleteTestResource(t, r, test.attributes); resp.Diagnostics.HasError() {
+					t.Fatalf("delete %d: unexpected diagnostics: %v", i+1, resp.Diagnostics)
+				}
+			}
+		})
+	}
+}
+
+// A rejected delete must not be reported as a successful destroy.
+func TestResourceDeleteError(t *testing.T) {
+	for _, status := range []int{http.StatusForbidden, http.StatusInternalServerError} {
+		for _, test := range deleteTestResources {
+			t.Run(test.name+"/"+http.StatusText(status), func(t *testing.T) {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(status)
+				}))
+				defer server.Close()
+
+				r := test.newResource()
+				configureTestResource(t, r, server)
+
+				if resp := deleteTestResource(t, r, test.attributes); !resp.Diagnostics.HasError() {
+					t.Fatalf("delete returned no error for status %d", status)
+				}
+			})
+		}
+	}
+}
+
+func TestCheckReadResponse(t *testing.T) {
+	tests := []struct {
+		status      int
+		wantUsable  bool
+		wantRemoved bool
+		wantErr     bool
+	}{
+		{status: http.StatusOK, wantUsable: true},
+		{status: http.StatusNotFound, wantRemoved: true},
+		{status: http.StatusGone, wantRemoved: true},
+		{status: http.StatusUnauthorized, wantErr: true},
+		{status: http.StatusForbidden, wantErr: true},
+		{status: http.StatusBadRequest, wantErr: true},
+		{status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(http.StatusText(test.status), func(t *testing.T) {
+			state := newTestState(t, NewTeamResource(), map[string]string{"id": "team", "organization_id": "org"})
+			resp := resource.ReadResponse{State: state}
+
+			usable := checkReadResponse(context.Background(), &resp, &http.Response{StatusCode: test.status, Status: http.StatusText(test.status), Header: http.Header{}}, []byte(`{"errors":[{"detail":"failed"}]}`), "team")
+
+			if usable != test.wantUsable {
+				t.Errorf("checkReadResponse(%d) = %t, want %t", test.status, usable, test.wantUsable)
+			}
+			if removed := resp.State.Raw.IsNull(); removed != test.wantRemoved {
+				t.Errorf("checkReadResponse(%d) removed the resource = %t, want %t", test.status, removed, test.wantRemoved)
+			}
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Errorf("checkReadResponse(%d) diagnostics = %v, want error %t", test.status, resp.Diagnostics, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatAPIErrorRedacted(t *testing.T) {
+	response := &http.Response{StatusCode: http.StatusBadRequest, Status: "400 Bad Request", Header: http.Header{}}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "vcs", body: `{"data":{"type":"vcs","id":"vcs","attributes":{"clientSecret":"s3cr3t"}}}`},
+		{name: "sensitive variable", body: `{"data":{"type":"variable","id":"variable","attributes":{"key":"password","value":"s3cr3t","sensitive":true}}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if text := formatAPIError(response, []byte(test.body)); strings.Contains(text, "s3cr3t") {
+				t.Errorf("formatAPIError kept a secret: %s", text)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Applying an unchanged configuration again plans no change and sends no
+// write request, after the API filled in the values the provider doesn't set.
+func TestSecondApplyNoWrites(t *testing.T) {
+	fake := newFakeTerrakube(t)
+	workspaceTestConfiguration(fake)
+	s := newTestProviderServer(t, fake.URL)
+
+	configs := map[string]tftypes.Value{
+		"terrakube_team": s.config("terrakube_team", map[string]tftypes.Value{
+			"organization_id":  tftypes.NewValue(tftypes.String, "org"),
+			"name":             tftypes.NewValue(tftypes.String, "DEVELOPERS"),
+			"manage_state":     tftypes.NewValue(tftypes.Bool, true),
+			"manage_workspace": tftypes.NewValue(tftypes.Bool, true),
+		}),
+		"terrakube_vcs": s.config("terrakube_vcs", map[string]tftypes.Value{
+			"organization_id": tftypes.NewValue(tftypes.String, "org"),
+			"name":            tftypes.NewValue(tftypes.String, "github"),
+			"type":            tftypes.NewValue(tftypes.String, "GITHUB"),
+			"client_id":       tftypes.NewValue(tftypes.String, "Ov23liABCDEFGHIJKLMN"),
+			"client_secret":   tftypes.NewValue(tftypes.String, "secret"),
+		}),
+	}
+	instances := map[string]*testResourceInstance{}
+	for _, typeName := range []string{"terrakube_team", "terrakube_vcs"} {
+		instances[typeName] = s.apply(typeName, nil, configs[typeName])
+	}
+
+	// The connection is completed from the connect_url.
+	vcsId := instances["terrakube_vcs"].attribute(t, "id")
+	fake.get("organization/org/vcs/" + vcsId).Attributes["status"] = "COMPLETED"
+
+	var workspaceValues map[string]tftypes.Value
+	if err := workspaceVcsTestConfig(s).As(&workspaceValues); err != nil {
+		t.Fatal(err)
+	}
+	workspaceValues["vcs_id"] = tftypes.NewValue(tftypes.String, vcsId)
+	configs["terrakube_workspace_vcs"] = s.config("terrakube_workspace_vcs", workspaceValues)
+	instances["terrakube_workspace_vcs"] = s.apply("terrakube_workspace_vcs", nil, configs["terrakube_workspace_vcs"])
+
+	workspaceId := tftypes.NewValue(tftypes.String, instances["terrakube_workspace_vcs"].attribute(t, "id"))
+	configs["terrakube_workspace_webhook"] = workspaceChildTestConfigs(s, workspaceId)["terrakube_workspace_webhook.push"]
+	instances["terrakube_workspace_webhook"] = s.apply("terrakube_workspace_webhook", nil, configs["terrakube_workspace_webhook"])
+
+	start := len(fake.log())
+	for _, typeName := range []string{"terrakube_team", "terrakube_vcs", "terrakube_workspace_vcs", "terrakube_workspace_webhook"} {
+		instance := instances[typeName]
+		s.refresh(instance)
+		if changes := s.changes(instance, s.plan(typeName, instance, configs[typeName])); len(changes) > 0 {
+			t.Errorf("%s plans changes: %v", typeName, changes)
+		}
+		s.apply(typeName, instance, configs[typeName])
+	}
+
+	if writes := fake.writes(start); len(writes) > 0 {
+		t.Errorf("second apply sent write requests: %v", writes)
+	}
+}
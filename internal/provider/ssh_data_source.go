@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -53,17 +52,7 @@ func (d *SshDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.Client
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
 
@@ -102,7 +91,7 @@ func (d *SshDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 
 	req.Config.Get(ctx, &state)
 
-	requestSsh, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/ssh?filter[ssh]=name==%s", d.endpoint, state.OrganizationId.ValueString(), state.Name.ValueString()), nil)
+	requestSsh, err := http.NewRequest(http.MethodGet, apiURL(d.endpoint, "organization", state.OrganizationId.ValueString(), "ssh")+fmt.Sprintf("?filter[ssh]=name==%s", state.Name.ValueString()), nil)
 	requestSsh.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
 	requestSsh.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -3,21 +3,23 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
 	"github.com/google/jsonapi"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -25,11 +27,13 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &TeamResource{}
 var _ resource.ResourceWithImportState = &TeamResource{}
+var _ resource.ResourceWithModifyPlan = &TeamResource{}
 
 type TeamResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type TeamResourceModel struct {
@@ -56,7 +60,7 @@ func (r *TeamResource) Metadata(ctx context.Context, req resource.MetadataReques
 
 func (r *TeamResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Create a team and bind it to an organization. Allows for fined grained access management.",
+		MarkdownDescription: "Create a team and bind it to an organization. Allows for fined grained access management. Permissions that are not set use the Terrakube API defaults, a permission removed from the configuration is revoked.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -66,10 +70,7 @@ func (r *TeamResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Team name",
@@ -81,54 +82,156 @@ func (r *TeamResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				Optional:    true,
 				Description: "Allow to manage Terraform/OpenTofu state",
 				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"manage_job": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Allow to manage and trigger jobs",
 				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"manage_collection": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Allow to manage variables collection",
 				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"manage_workspace": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Allow to manage workspaces",
 				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"manage_module": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Allow to manage modules",
 				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"manage_provider": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Allow to manage providers",
 				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"manage_vcs": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Allow to manage vcs connections",
 				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"manage_template": schema.BoolAttribute{
 				Optional:    true,
 				Description: "Allow to manage templates",
 				Computed:    true,
-				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
 	}
 }
 
+// knownBoolPointer returns nil for null or unknown values so the attribute is
+// left out of the payload and the API default is used.
+func knownBoolPointer(value types.Bool) *bool {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	return value.ValueBoolPointer()
+}
+
+// newTeamEntity returns the payload of the team, the permissions that are not
+// known are left out.
+func newTeamEntity(plan TeamResourceModel) *client.TeamEntity {
+	return &client.TeamEntity{
+		Name:             plan.Name.ValueString(),
+		ManageState:      knownBoolPointer(plan.ManageState),
+		ManageWorkspace:  knownBoolPointer(plan.ManageWorkspace),
+		ManageModule:     knownBoolPointer(plan.ManageModule),
+		ManageProvider:   knownBoolPointer(plan.ManageProvider),
+		ManageTemplate:   knownBoolPointer(plan.ManageTemplate),
+		ManageVcs:        knownBoolPointer(plan.ManageVcs),
+		ManageJob:        knownBoolPointer(plan.ManageJob),
+		ManageCollection: knownBoolPointer(plan.ManageCollection),
+	}
+}
+
+// teamPermissionValue maps a permission returned by the API, missing permissions are not granted.
+func teamPermissionValue(permission *bool) types.Bool {
+	return types.BoolValue(permission != nil && *permission)
+}
+
+// teamConfiguredPermissions are the permissions set in the configuration by
+// the last apply, see revokedTeamPermissions.
+var teamConfiguredPermissions = managedKeys{privateKey: "configured_permissions"}
+
+var teamPermissionAttributes = []string{
+	"manage_state",
+	"manage_workspace",
+	"manage_module",
+	"manage_provider",
+	"manage_vcs",
+	"manage_template",
+	"manage_job",
+	"manage_collection",
+}
+
+// configuredTeamPermissions returns the permissions set in the configuration.
+func configuredTeamPermissions(ctx context.Context, config tfsdk.Config) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	configured := []string{}
+
+	for _, name := range teamPermissionAttributes {
+		var value types.Bool
+		diags.Append(config.GetAttribute(ctx, path.Root(name), &value)...)
+		if !value.IsNull() {
+			configured = append(configured, name)
+		}
+	}
+
+	return configured, diags
+}
+
+// revokedTeamPermissions returns the permissions set by the last apply that
+// are missing from config. UseStateForUnknown would keep their previous value,
+// they are planned as false instead.
+func revokedTeamPermissions(ctx context.Context, private privateStateReader, config tfsdk.Config) ([]string, diag.Diagnostics) {
+	previous, tracked, diags := teamConfiguredPermissions.Read(ctx, private)
+	if !tracked || diags.HasError() {
+		return nil, diags
+	}
+
+	configured, configDiags := configuredTeamPermissions(ctx, config)
+	diags.Append(configDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	revoked := []string{}
+	for _, name := range previous {
+		if !slices.Contains(configured, name) {
+			revoked = append(revoked, name)
+		}
+	}
+
+	return revoked, diags
+}
+
 func (r *TeamResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -144,20 +247,11 @@ func (r *TeamResource) Configure(ctx context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Team resource", map[string]any{"success": true})
 }
@@ -171,17 +265,7 @@ func (r *TeamResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	bodyRequest := &client.TeamEntity{
-		Name:             plan.Name.ValueString(),
-		ManageState:      plan.ManageState.ValueBool(),
-		ManageWorkspace:  plan.ManageWorkspace.ValueBool(),
-		ManageModule:     plan.ManageModule.ValueBool(),
-		ManageProvider:   plan.ManageProvider.ValueBool(),
-		ManageTemplate:   plan.ManageTemplate.ValueBool(),
-		ManageVcs:        plan.ManageVcs.ValueBool(),
-		ManageJob:        plan.ManageJob.ValueBool(),
-		ManageCollection: plan.ManageCollection.ValueBool(),
-	}
+	bodyRequest := newTeamEntity(plan)
 
 	var out = new(bytes.Buffer)
 	err := jsonapi.MarshalPayload(out, bodyRequest)
@@ -191,7 +275,7 @@ func (r *TeamResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	teamRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/team", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	teamRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "team"), strings.NewReader(out.String()))
 	teamRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	teamRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -222,17 +306,21 @@ func (r *TeamResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	plan.ID = types.StringValue(newTeam.ID)
 	plan.Name = types.StringValue(newTeam.Name)
-	plan.ManageState = types.BoolValue(newTeam.ManageState)
-	plan.ManageWorkspace = types.BoolValue(newTeam.ManageWorkspace)
-	plan.ManageModule = types.BoolValue(newTeam.ManageModule)
-	plan.ManageVcs = types.BoolValue(newTeam.ManageVcs)
-	plan.ManageProvider = types.BoolValue(newTeam.ManageProvider)
-	plan.ManageTemplate = types.BoolValue(newTeam.ManageTemplate)
-	plan.ManageJob = types.BoolValue(newTeam.ManageJob)
-	plan.ManageCollection = types.BoolValue(newTeam.ManageCollection)
+	plan.ManageState = teamPermissionValue(newTeam.ManageState)
+	plan.ManageWorkspace = teamPermissionValue(newTeam.ManageWorkspace)
+	plan.ManageModule = teamPermissionValue(newTeam.ManageModule)
+	plan.ManageVcs = teamPermissionValue(newTeam.ManageVcs)
+	plan.ManageProvider = teamPermissionValue(newTeam.ManageProvider)
+	plan.ManageTemplate = teamPermissionValue(newTeam.ManageTemplate)
+	plan.ManageJob = teamPermissionValue(newTeam.ManageJob)
+	plan.ManageCollection = teamPermissionValue(newTeam.ManageCollection)
 
 	tflog.Info(ctx, "Team Resource Created", map[string]any{"success": true})
 
+	configured, diags := configuredTeamPermissions(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(teamConfiguredPermissions.Write(ctx, resp.Private, configured)...)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -244,7 +332,7 @@ func (r *TeamResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	teamRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/team/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	teamRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "team", state.ID.ValueString()), nil)
 	teamRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	teamRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -262,6 +350,11 @@ func (r *TeamResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	if err != nil {
 		tflog.Error(ctx, "Error reading team resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, teamResponse, bodyResponse, "team") {
+		return
+	}
+
 	team := &client.TeamEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -275,14 +368,14 @@ func (r *TeamResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
 	state.Name = types.StringValue(team.Name)
-	state.ManageState = types.BoolValue(team.ManageState)
-	state.ManageWorkspace = types.BoolValue(team.ManageWorkspace)
-	state.ManageModule = types.BoolValue(team.ManageModule)
-	state.ManageVcs = types.BoolValue(team.ManageVcs)
-	state.ManageProvider = types.BoolValue(team.ManageProvider)
-	state.ManageTemplate = types.BoolValue(team.ManageTemplate)
-	state.ManageJob = types.BoolValue(team.ManageJob)
-	state.ManageCollection = types.BoolValue(team.ManageCollection)
+	state.ManageState = teamPermissionValue(team.ManageState)
+	state.ManageWorkspace = teamPermissionValue(team.ManageWorkspace)
+	state.ManageModule = teamPermissionValue(team.ManageModule)
+	state.ManageVcs = teamPermissionValue(team.ManageVcs)
+	state.ManageProvider = teamPermissionValue(team.ManageProvider)
+	state.ManageTemplate = teamPermissionValue(team.ManageTemplate)
+	state.ManageJob = teamPermissionValue(team.ManageJob)
+	state.ManageCollection = teamPermissionValue(team.ManageCollection)
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -304,18 +397,9 @@ func (r *TeamResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	bodyRequest := &client.TeamEntity{
-		ManageState:      plan.ManageState.ValueBool(),
-		ManageWorkspace:  plan.ManageWorkspace.ValueBool(),
-		ManageModule:     plan.ManageModule.ValueBool(),
-		ManageProvider:   plan.ManageProvider.ValueBool(),
-		ManageTemplate:   plan.ManageTemplate.ValueBool(),
-		ManageVcs:        plan.ManageVcs.ValueBool(),
-		ManageJob:        plan.ManageJob.ValueBool(),
-		ManageCollection: plan.ManageCollection.ValueBool(),
-		ID:               state.ID.ValueString(),
-		Name:             state.Name.ValueString(),
-	}
+	bodyRequest := newTeamEntity(plan)
+	bodyRequest.ID = state.ID.ValueString()
+	bodyRequest.Name = state.Name.ValueString()
 
 	var out = new(bytes.Buffer)
 	err := jsonapi.MarshalPayload(out, bodyRequest)
@@ -325,7 +409,7 @@ func (r *TeamResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	teamRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/team/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	teamRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "team", state.ID.ValueString()), strings.NewReader(out.String()))
 	teamRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	teamRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -346,7 +430,7 @@ func (r *TeamResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	teamRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/team/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	teamRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "team", state.ID.ValueString()), nil)
 	teamRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	teamRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -377,14 +461,18 @@ func (r *TeamResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Name = types.StringValue(team.Name)
-	plan.ManageState = types.BoolValue(team.ManageState)
-	plan.ManageWorkspace = types.BoolValue(team.ManageWorkspace)
-	plan.ManageModule = types.BoolValue(team.ManageModule)
-	plan.ManageVcs = types.BoolValue(team.ManageVcs)
-	plan.ManageProvider = types.BoolValue(team.ManageProvider)
-	plan.ManageTemplate = types.BoolValue(team.ManageTemplate)
-	plan.ManageJob = types.BoolValue(team.ManageJob)
-	plan.ManageCollection = types.BoolValue(team.ManageCollection)
+	plan.ManageState = teamPermissionValue(team.ManageState)
+	plan.ManageWorkspace = teamPermissionValue(team.ManageWorkspace)
+	plan.ManageModule = teamPermissionValue(team.ManageModule)
+	plan.ManageVcs = teamPermissionValue(team.ManageVcs)
+	plan.ManageProvider = teamPermissionValue(team.ManageProvider)
+	plan.ManageTemplate = teamPermissionValue(team.ManageTemplate)
+	plan.ManageJob = teamPermissionValue(team.ManageJob)
+	plan.ManageCollection = teamPermissionValue(team.ManageCollection)
+
+	configured, diags := configuredTeamPermissions(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(teamConfiguredPermissions.Write(ctx, resp.Private, configured)...)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
@@ -399,18 +487,39 @@ func (r *TeamResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/team/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	reqOrg, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", data.OrganizationId.ValueString(), "team", data.ID.ValueString()), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating team resource request", fmt.Sprintf("Error creating team resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	response, err := r.client.Do(reqOrg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing team resource request", fmt.Sprintf("Error executing team resource request: %s", err))
 		return
 	}
+	defer response.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(response.Body)
+	if err = checkDeleteResponse(response, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing team resource request", fmt.Sprintf("Error executing team resource request: %s", err))
+		return
+	}
+}
+
+func (r *TeamResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
+
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	revoked, diags := revokedTeamPermissions(ctx, req.Private, req.Config)
+	resp.Diagnostics.Append(diags...)
+	for _, name := range revoked {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), types.BoolValue(false))...)
+	}
 }
 
 func (r *TeamResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestKnownBoolPointer(t *testing.T) {
+	tests := []struct {
+		name  string
+		value types.Bool
+		want  *bool
+	}{
+		{name: "null", value: types.BoolNull()},
+		{name: "unknown", value: types.BoolUnknown()},
+		{name: "true", value: types.BoolValue(true), want: types.BoolValue(true).ValueBoolPointer()},
+		{name: "false", value: types.BoolValue(false), want: types.BoolValue(false).ValueBoolPointer()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := knownBoolPointer(test.value); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("knownBoolPointer(%s) = %v, want %v", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTeamPermissionValue(t *testing.T) {
+	granted, denied := true, false
+
+	tests := []struct {
+		name       string
+		permission *bool
+		want       types.Bool
+	}{
+		{name: "missing", want: types.BoolValue(false)},
+		{name: "granted", permission: &granted, want: types.BoolValue(true)},
+		{name: "denied", permission: &denied, want: types.BoolValue(false)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := teamPermissionValue(test.permission); !got.Equal(test.want) {
+				t.Errorf("teamPermissionValue = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewTeamEntityPayload(t *testing.T) {
+	plan := TeamResourceModel{
+		Name:             types.StringValue("developers"),
+		ManageWorkspace:  types.BoolValue(true),
+		ManageState:      types.BoolValue(false),
+		ManageModule:     types.BoolUnknown(),
+		ManageProvider:   types.BoolNull(),
+		ManageVcs:        types.BoolUnknown(),
+		ManageTemplate:   types.BoolUnknown(),
+		ManageJob:        types.BoolUnknown(),
+		ManageCollection: types.BoolUnknown(),
+	}
+
+	var out bytes.Buffer
+	if err := jsonapi.MarshalPayload(&out, newTeamEntity(plan)); err != nil {
+		t.Fatalf("MarshalPayload returned %v", err)
+	}
+
+	var payload struct {
+		Data struct {
+			Attributes map[string]any `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &payload); err != nil {
+		t.Fatalf("unable to read payload %s: %s", out.String(), err)
+	}
+
+	want := map[string]any{"name": "developers", "manageWorkspace": true, "manageState": false}
+	if !reflect.DeepEqual(payload.Data.Attributes, want) {
+		t.Errorf("payload attributes = %v, want %v", payload.Data.Attributes, want)
+	}
+}
+
+func TestRevokedTeamPermissions(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		previous []string
+		config   map[string]bool
+		want     []string
+	}{
+		{name: "untracked", config: map[string]bool{}},
+		{name: "unchanged", previous: []string{"manage_workspace"}, config: map[string]bool{"manage_workspace": true}, want: []string{}},
+		{name: "set to false", previous: []string{"manage_workspace"}, config: map[string]bool{"manage_workspace": false}, want: []string{}},
+		{name: "removed", previous: []string{"manage_state", "manage_workspace"}, config: map[string]bool{"manage_state": true}, want: []string{"manage_workspace"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewTeamResource()
+			state := newTestState(t, r, map[string]string{"name": "developers", "organization_id": "org"})
+			for name, value := range test.config {
+				if diags := state.SetAttribute(ctx, path.Root(name), types.BoolValue(value)); diags.HasError() {
+					t.Fatalf("unable to set %s: %v", name, diags)
+				}
+			}
+			config := tfsdk.Config{Schema: state.Schema, Raw: state.Raw}
+
+			private := testPrivateState{}
+			if test.previous != nil {
+				if diags := teamConfiguredPermissions.Write(ctx, private, test.previous); diags.HasError() {
+					t.Fatalf("unable to write the configured permissions: %v", diags)
+				}
+			}
+
+			got, diags := revokedTeamPermissions(ctx, private, config)
+			if diags.HasError() {
+				t.Fatalf("revokedTeamPermissions returned %v", diags)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("revokedTeamPermissions = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
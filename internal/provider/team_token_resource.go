@@ -2,16 +2,12 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"strings"
 	"terraform-provider-terrakube/internal/client"
 	"terraform-provider-terrakube/internal/helpers"
 
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 
@@ -25,6 +21,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &TeamTokenResource{}
 var _ resource.ResourceWithImportState = &TeamTokenResource{}
+var _ resource.ResourceWithValidateConfig = &TeamTokenResource{}
+var _ resource.ResourceWithModifyPlan = &TeamTokenResource{}
 
 type TeamTokenResource struct {
 	client   *http.Client
@@ -33,13 +31,14 @@ type TeamTokenResource struct {
 }
 
 type TeamTokenResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Group       types.String `tfsdk:"team_name"`
-	Description types.String `tfsdk:"description"`
-	Days        types.Int32  `tfsdk:"days"`
-	Hours       types.Int32  `tfsdk:"hours"`
-	Minutes     types.Int32  `tfsdk:"minutes"`
-	Value       types.String `tfsdk:"value"`
+	ID           types.String `tfsdk:"id"`
+	Group        types.String `tfsdk:"team_name"`
+	Description  types.String `tfsdk:"description"`
+	Days         types.Int32  `tfsdk:"days"`
+	Hours        types.Int32  `tfsdk:"hours"`
+	Minutes      types.Int32  `tfsdk:"minutes"`
+	TotalMinutes types.Int64  `tfsdk:"total_minutes"`
+	Value        types.String `tfsdk:"value"`
 }
 
 func NewTeamTokenResource() resource.Resource {
@@ -71,41 +70,63 @@ func (r *TeamTokenResource) Schema(ctx context.Context, req resource.SchemaReque
 			},
 			"description": schema.StringAttribute{
 				Required:    true,
-				Description: "A description of this token.",
+				Description: "A description of this token. Changing it issues a new token with a new value.",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
-			"days": schema.Int32Attribute{
-				Required:    true,
-				Description: "The number of days this token is valid for.",
-				PlanModifiers: []planmodifier.Int32{
-					int32planmodifier.RequiresReplace(),
-				},
-			},
-			"hours": schema.Int32Attribute{
-				Required:    true,
-				Description: "The number of hours this token is valid for.",
-				PlanModifiers: []planmodifier.Int32{
-					int32planmodifier.RequiresReplace(),
-				},
-			},
-			"minutes": schema.Int32Attribute{
-				Required:    true,
-				Description: "The number of minutes this token is valid for.",
-				PlanModifiers: []planmodifier.Int32{
-					int32planmodifier.RequiresReplace(),
-				},
-			},
+			"days":          tokenDurationAttribute("days", tokenMaxDays),
+			"hours":         tokenDurationAttribute("hours", tokenMaxHours),
+			"minutes":       tokenDurationAttribute("minutes", tokenMaxMinutes),
+			"total_minutes": tokenTotalMinutesAttribute(),
 			"value": schema.StringAttribute{
 				Computed:    true,
 				Description: "The value of the token.",
 				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
 	}
 }
 
+func (r *TeamTokenResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config TeamTokenResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateTokenDuration(config.Days, config.Hours, config.Minutes)...)
+}
+
+func (r *TeamTokenResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan TeamTokenResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A token is only issued again when its duration changes
+	existing := false
+	if !req.State.Raw.IsNull() {
+		var state TeamTokenResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		existing = plan.Days.Equal(state.Days) && plan.Hours.Equal(state.Hours) && plan.Minutes.Equal(state.Minutes)
+	}
+
+	resp.Diagnostics.Append(checkTokenDurationLimit(plan.Days, plan.Hours, plan.Minutes, existing)...)
+}
+
 func (r *TeamTokenResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -121,17 +142,7 @@ func (r *TeamTokenResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -156,30 +167,17 @@ func (r *TeamTokenResource) Create(ctx context.Context, req resource.CreateReque
 		Group:       plan.Group.ValueString(),
 	}
 
-	bodyJson, err := json.Marshal(bodyRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal request ", fmt.Sprintf("Unable to marshal request, error: %s", err))
-		return
-	}
-
-	teamTokenRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/access-token/v1/teams", r.endpoint), strings.NewReader(string(bodyJson)))
-	teamTokenRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	teamTokenRequest.Header.Add("Content-Type", "application/vnd.api+json")
+	teamTokenResponse, bodyResponse, err := doTokenRequest(r.client, http.MethodPost, endpointURL(r.endpoint, "access-token", "v1", "teams"), r.token, bodyRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating team token resource request", fmt.Sprintf("Error creating team token resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing team token resource request", fmt.Sprintf("Error executing team token resource request: %s", err))
 		return
 	}
 
-	teamTokenResponse, err := r.client.Do(teamTokenRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing team token resource request", fmt.Sprintf("Error executing team token resource request: %s", err))
+	if err := client.CheckResponse(teamTokenResponse, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error creating team token", fmt.Sprintf("Error creating team token: %s", err))
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(teamTokenResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading team token resource response")
-	}
 	newTeamToken := &client.TeamTokenEntity{}
 
 	err = json.Unmarshal(bodyResponse, newTeamToken)
@@ -196,6 +194,7 @@ func (r *TeamTokenResource) Create(ctx context.Context, req resource.CreateReque
 		resp.Diagnostics.AddError("Error getting claim from token", fmt.Sprintf("Error getting claim from token: %s", err))
 	}
 	plan.ID = types.StringValue(id)
+	plan.TotalMinutes = tokenTotalMinutes(plan.Days, plan.Hours, plan.Minutes)
 	plan.Value = types.StringValue(newTeamToken.Value)
 
 	tflog.Info(ctx, "Team Token Resource Created", map[string]any{"success": true})
@@ -211,24 +210,16 @@ func (r *TeamTokenResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	teamTokenRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/access-token/v1/teams", r.endpoint), nil)
-	teamTokenRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	teamTokenRequest.Header.Add("Content-Type", "application/vnd.api+json")
+	teamTokenResponse, bodyResponse, err := doTokenRequest(r.client, http.MethodGet, endpointURL(r.endpoint, "access-token", "v1", "teams"), r.token, nil)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating team token resource request", fmt.Sprintf("Error creating team token resource request: %s", err))
+		resp.Diagnostics.AddError("Error executing team token resource request", fmt.Sprintf("Error executing team token resource request: %s", err))
 		return
 	}
 
-	teamTokenResponse, err := r.client.Do(teamTokenRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing team token resource request", fmt.Sprintf("Error executing team token resource request: %s", err))
+	if !checkReadResponse(ctx, resp, teamTokenResponse, bodyResponse, "team token") {
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(teamTokenResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading team token resource response, error: %s, response status %s", err, teamTokenResponse.Status))
-	}
 	teamTokens := &[]client.TeamTokenEntity{}
 
 	tflog.Debug(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -241,19 +232,29 @@ func (r *TeamTokenResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	tflog.Info(ctx, "Response status", map[string]any{"responseStatus": teamTokenResponse.Status})
 
+	found := false
 	for _, teamToken := range *teamTokens {
 		if teamToken.ID != state.ID.ValueString() {
 			continue
 		}
 
+		found = true
+
 		state.Description = types.StringValue(teamToken.Description)
 		state.Days = types.Int32Value(teamToken.Days)
 		state.Hours = types.Int32Value(teamToken.Hours)
 		state.Minutes = types.Int32Value(teamToken.Minutes)
+		state.TotalMinutes = tokenTotalMinutes(state.Days, state.Hours, state.Minutes)
 		state.Group = types.StringValue(teamToken.Group)
 		break
 	}
 
+	if !found {
+		tflog.Warn(ctx, "Team token not found, it was probably revoked, removing from state", map[string]any{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -278,16 +279,14 @@ func (r *TeamTokenResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 
-	reqToken, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/access-token/v1/teams/%s", r.endpoint, data.ID.ValueString()), nil)
-	reqToken.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	resToken, bodyResponse, err := doTokenRequest(r.client, http.MethodDelete, endpointURL(r.endpoint, "access-token", "v1", "teams", data.ID.ValueString()), r.token, nil)
 	if err != nil {
-		resp.Diagnostics.AddError("Error deleting team token resource request", fmt.Sprintf("Error deleting team token resource request: %s", err))
+		resp.Diagnostics.AddError("Error deleting team token", fmt.Sprintf("Error deleting team token, error: %s", err))
 		return
 	}
 
-	resToken, err := r.client.Do(reqToken)
-	if err != nil || resToken.StatusCode != http.StatusAccepted {
-		resp.Diagnostics.AddError("Error deleting team token", fmt.Sprintf("Error deleting team token, error: %s, response status: %s, response body: %s", err, resToken.Status, resToken.Body))
+	if err = checkDeleteResponse(resToken, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error deleting team token", fmt.Sprintf("Error deleting team token: %s", err))
 		return
 	}
 }
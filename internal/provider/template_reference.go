@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// templateUsagesPageSize is the number of objects requested on each page when looking for template usages.
+const templateUsagesPageSize = 100
+
+// checkTemplateReferences verifies that every template id belongs to the organization,
+// templates copied from another organization are accepted by some API versions and
+// only fail when a job runs. Empty ids are ignored.
+func checkTemplateReferences(httpClient *http.Client, endpoint string, token string, organizationId string, templateIds ...string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	checked := map[string]bool{}
+	for _, templateId := range templateIds {
+		if templateId == "" || checked[templateId] {
+			continue
+		}
+		checked[templateId] = true
+
+		templateRequest, err := http.NewRequest(http.MethodGet, apiURL(endpoint, "organization", organizationId, "template", templateId), nil)
+		if err != nil {
+			diags.AddError("Error creating template request", fmt.Sprintf("Error creating template request: %s", err))
+			return diags
+		}
+		templateRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+		templateRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+		templateResponse, err := httpClient.Do(templateRequest)
+		if err != nil {
+			diags.AddError("Error executing template request", fmt.Sprintf("Error executing template request: %s", err))
+			return diags
+		}
+
+		bodyResponse, _ := io.ReadAll(templateResponse.Body)
+		templateResponse.Body.Close()
+
+		err = client.CheckResponse(templateResponse, bodyResponse)
+		switch {
+		case err == nil:
+		case client.IsNotFound(err):
+			diags.AddError(
+				"Invalid template reference",
+				fmt.Sprintf("template %s not found in organization %s", templateId, organizationId),
+			)
+		default:
+			diags.AddError(
+				"Error validating template reference",
+				fmt.Sprintf("Error validating template %s, set validate_references = false in the provider to skip this check: %s", templateId, err),
+			)
+		}
+	}
+
+	return diags
+}
+
+// templateUsages returns a description of every workspace, webhook and
+// schedule of the organization that references the template. Deleted
+// workspaces are ignored. The workspaces are filtered by the API, the webhooks
+// and schedules are only listed for the workspaces that reference the template.
+func templateUsages(httpClient *http.Client, endpoint string, token string, organizationId string, templateId string) ([]string, error) {
+	quotedId := helpers.RsqlQuote(templateId)
+	workspacesURL := func(filter string) string {
+		return apiURL(endpoint, "organization", url.PathEscape(organizationId), "workspace") + fmt.Sprintf("?filter[workspace]=%s", url.QueryEscape("deleted==false;"+filter+"=="+quotedId))
+	}
+
+	var usages []string
+
+	workspaces, err := listAll(httpClient, token, workspacesURL("defaultTemplate"), reflect.TypeOf(new(client.WorkspaceEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("error reading workspaces: %w", err)
+	}
+	for _, item := range workspaces {
+		workspace := item.(*client.WorkspaceEntity)
+		usages = append(usages, fmt.Sprintf("workspace %s (%s) default template", workspace.Name, workspace.ID))
+	}
+
+	workspaces, err = listAll(httpClient, token, workspacesURL("webhook.templateId"), reflect.TypeOf(new(client.WorkspaceEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("error reading workspaces: %w", err)
+	}
+	for _, item := range workspaces {
+		workspace := item.(*client.WorkspaceEntity)
+		webhooks, err := listAll(httpClient, token, apiURL(endpoint, "organization", url.PathEscape(organizationId), "workspace", url.PathEscape(workspace.ID), "webhook")+fmt.Sprintf("?filter[webhook]=%s", url.QueryEscape("templateId=="+quotedId)), reflect.TypeOf(new(client.WorkspaceWebhookEntity)))
+		if err != nil {
+			return nil, fmt.Errorf("error reading webhooks of workspace %s: %w", workspace.ID, err)
+		}
+
+		for _, item := range webhooks {
+			webhook := item.(*client.WorkspaceWebhookEntity)
+			usages = append(usages, fmt.Sprintf("webhook %s of workspace %s (%s)", webhook.ID, workspace.Name, workspace.ID))
+		}
+	}
+
+	workspaces, err = listAll(httpClient, token, workspacesURL("schedule.templateReference"), reflect.TypeOf(new(client.WorkspaceEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("error reading workspaces: %w", err)
+	}
+	for _, item := range workspaces {
+		workspace := item.(*client.WorkspaceEntity)
+		schedules, err := listAll(httpClient, token, apiURL(endpoint, "workspace", url.PathEscape(workspace.ID), "schedule")+fmt.Sprintf("?filter[schedule]=%s", url.QueryEscape("templateReference=="+quotedId)), reflect.TypeOf(new(client.WorkspaceScheduleEntity)))
+		if err != nil {
+			return nil, fmt.Errorf("error reading schedules of workspace %s: %w", workspace.ID, err)
+		}
+
+		for _, item := range schedules {
+			schedule := item.(*client.WorkspaceScheduleEntity)
+			usages = append(usages, fmt.Sprintf("schedule %s of workspace %s (%s)", schedule.ID, workspace.Name, workspace.ID))
+		}
+	}
+
+	sort.Strings(usages)
+	return usages, nil
+}
+
+// listAll returns every object of the list at apiURL, requesting one page at a time.
+func listAll(httpClient *http.Client, token string, apiURL string, t reflect.Type) ([]interface{}, error) {
+	separator := "?"
+	if strings.Contains(apiURL, "?") {
+		separator = "&"
+	}
+
+	var all []interface{}
+	for page := 1; ; page++ {
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s%spage[size]=%d&page[number]=%d", apiURL, separator, templateUsagesPageSize, page), nil)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+		request.Header.Add("Content-Type", "application/vnd.api+json")
+
+		response, err := httpClient.Do(request)
+		if err != nil {
+			return nil, err
+		}
+
+		bodyResponse, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = client.CheckResponse(response, bodyResponse); err != nil {
+			return nil, err
+		}
+
+		items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), t)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal payload: %w", err)
+		}
+
+		all = append(all, items...)
+
+		if len(items) < templateUsagesPageSize {
+			return all, nil
+		}
+	}
+}
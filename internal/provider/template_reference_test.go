@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTemplateUsages(t *testing.T) {
+	const filter = `"template-1"`
+
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.URL.Path
+		for name, values := range r.URL.Query() {
+			if name != "page[size]" && name != "page[number]" {
+				request += " " + name + "=" + values[0]
+			}
+		}
+		requests = append(requests, request)
+
+		switch request {
+		case "/api/v1/organization/org/workspace filter[workspace]=deleted==false;defaultTemplate==" + filter:
+			_, _ = w.Write([]byte(`{"data":[{"type":"workspace","id":"ws-a","attributes":{"name":"networking"}}]}`))
+		case "/api/v1/organization/org/workspace filter[workspace]=deleted==false;webhook.templateId==" + filter:
+			_, _ = w.Write([]byte(`{"data":[{"type":"workspace","id":"ws-b","attributes":{"name":"compute"}}]}`))
+		case "/api/v1/organization/org/workspace filter[workspace]=deleted==false;schedule.templateReference==" + filter:
+			_, _ = w.Write([]byte(`{"data":[{"type":"workspace","id":"ws-c","attributes":{"name":"storage"}}]}`))
+		case "/api/v1/organization/org/workspace/ws-b/webhook filter[webhook]=templateId==" + filter:
+			_, _ = w.Write([]byte(`{"data":[{"type":"webhook","id":"webhook-1","attributes":{"templateId":"template-1"}}]}`))
+		case "/api/v1/workspace/ws-c/schedule filter[schedule]=templateReference==" + filter:
+			_, _ = w.Write([]byte(`{"data":[{"type":"schedule","id":"schedule-1","attributes":{"cron":"0 0 * * *","templateReference":"template-1"}}]}`))
+		default:
+			t.Errorf("unexpected request %s", request)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	usages, err := templateUsages(server.Client(), server.URL, "test-token", "org", "template-1")
+	if err != nil {
+		t.Fatalf("templateUsages returned %v", err)
+	}
+
+	want := []string{
+		"schedule schedule-1 of workspace storage (ws-c)",
+		"webhook webhook-1 of workspace compute (ws-b)",
+		"workspace networking (ws-a) default template",
+	}
+	if !reflect.DeepEqual(usages, want) {
+		t.Errorf("templateUsages = %v, want %v", usages, want)
+	}
+	if len(requests) != 5 {
+		t.Errorf("%d requests, want 5: %v", len(requests), requests)
+	}
+}
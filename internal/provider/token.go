@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	tokenMaxDays    = 365
+	tokenMaxHours   = 23
+	tokenMaxMinutes = 59
+
+	// tokenMaxTotalMinutes is the longest duration accepted for a token, larger
+	// values overflow the expiry computation of some Terrakube versions.
+	tokenMaxTotalMinutes = tokenMaxDays * 24 * 60
+)
+
+// tokenDurationAttribute returns the schema used by the days, hours and minutes
+// attributes of the team token resource.
+func tokenDurationAttribute(unit string, max int32) schema.Int32Attribute {
+	return schema.Int32Attribute{
+		Required:    true,
+		Description: fmt.Sprintf("The number of %s this token is valid for, maximum %d. The total duration must be greater than 0 and at most %d days. Changing it issues a new token with a new value.", unit, max, tokenMaxDays),
+		Validators: []validator.Int32{
+			int32validator.AtLeast(0),
+		},
+		PlanModifiers: []planmodifier.Int32{
+			int32planmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// tokenTotalMinutesAttribute returns the schema of the computed total_minutes attribute.
+func tokenTotalMinutesAttribute() schema.Int64Attribute {
+	return schema.Int64Attribute{
+		Computed:    true,
+		Description: "The total duration of the token in minutes.",
+		PlanModifiers: []planmodifier.Int64{
+			int64planmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// tokenTotalMinutes returns the duration of the token in minutes.
+func tokenTotalMinutes(days, hours, minutes types.Int32) types.Int64 {
+	return types.Int64Value(int64(days.ValueInt32())*24*60 + int64(hours.ValueInt32())*60 + int64(minutes.ValueInt32()))
+}
+
+// validateTokenDuration checks that the token is valid for a positive amount of time.
+func validateTokenDuration(days, hours, minutes types.Int32) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if days.IsUnknown() || hours.IsUnknown() || minutes.IsUnknown() {
+		return diags
+	}
+
+	if tokenTotalMinutes(days, hours, minutes).ValueInt64() <= 0 {
+		diags.AddAttributeError(
+			path.Root("days"),
+			"Invalid token duration",
+			"At least one of days, hours or minutes must be greater than 0.",
+		)
+	}
+
+	return diags
+}
+
+// checkTokenDurationLimit checks the token duration against the documented
+// limits. A new token longer than tokenMaxTotalMinutes is an error. A token
+// that already exists only gets a warning, so configurations created before
+// the limit keep working, as do units above their own maximum.
+func checkTokenDurationLimit(days, hours, minutes types.Int32, existing bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if days.IsUnknown() || hours.IsUnknown() || minutes.IsUnknown() {
+		return diags
+	}
+
+	totalMinutes := tokenTotalMinutes(days, hours, minutes).ValueInt64()
+	if totalMinutes > tokenMaxTotalMinutes && !existing {
+		diags.AddAttributeError(
+			path.Root("days"),
+			"Token duration above the supported maximum",
+			fmt.Sprintf("The token is valid for %d minutes, the maximum is %d days (%d minutes). Longer durations overflow the token expiry on some Terrakube versions.", totalMinutes, tokenMaxDays, tokenMaxTotalMinutes),
+		)
+		return diags
+	}
+
+	if totalMinutes > tokenMaxTotalMinutes || days.ValueInt32() > tokenMaxDays || hours.ValueInt32() > tokenMaxHours || minutes.ValueInt32() > tokenMaxMinutes {
+		diags.AddAttributeWarning(
+			path.Root("days"),
+			"Token duration above the supported maximum",
+			fmt.Sprintf("The token is valid for %d minutes. Durations above %d days, or more than %d hours or %d minutes, can overflow the token expiry on some Terrakube versions. A new token above %d days is rejected.", totalMinutes, tokenMaxDays, tokenMaxHours, tokenMaxMinutes, tokenMaxDays),
+		)
+	}
+
+	return diags
+}
+
+// doTokenRequest sends a request to the access token API and returns the response with its body.
+func doTokenRequest(httpClient *http.Client, method string, url string, token string, body interface{}) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		bodyJson, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(bodyJson)
+	}
+
+	tokenRequest, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokenRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	tokenRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+	tokenResponse, err := httpClient.Do(tokenRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tokenResponse.Body.Close()
+
+	bodyResponse, err := io.ReadAll(tokenResponse.Body)
+	if err != nil {
+		return tokenResponse, nil, err
+	}
+
+	return tokenResponse, bodyResponse, nil
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTokenDuration(t *testing.T) {
+	tests := []struct {
+		name                 string
+		days, hours, minutes int32
+		existing             bool
+		wantErr              bool
+		wantWarning          bool
+	}{
+		{name: "one day", days: 1},
+		{name: "maximum", days: tokenMaxDays},
+		{name: "zero", wantErr: true},
+		{name: "units above their maximum", hours: 48, minutes: 90, wantWarning: true},
+		{name: "new token above the maximum", days: 999, hours: 999, minutes: 999, wantErr: true},
+		{name: "existing token above the maximum", days: 999, hours: 999, minutes: 999, existing: true, wantWarning: true},
+		{name: "new token one minute above the maximum", days: tokenMaxDays, minutes: 1, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			days, hours, minutes := types.Int32Value(test.days), types.Int32Value(test.hours), types.Int32Value(test.minutes)
+
+			diags := validateTokenDuration(days, hours, minutes)
+			if !diags.HasError() {
+				diags.Append(checkTokenDurationLimit(days, hours, minutes, test.existing)...)
+			}
+
+			if diags.HasError() != test.wantErr {
+				t.Errorf("diagnostics = %v, want error %t", diags, test.wantErr)
+			}
+			if got := diags.WarningsCount() > 0; got != test.wantWarning {
+				t.Errorf("diagnostics = %v, want warning %t", diags, test.wantWarning)
+			}
+		})
+	}
+}
+
+func TestTokenDurationUnknown(t *testing.T) {
+	diags := validateTokenDuration(types.Int32Unknown(), types.Int32Value(0), types.Int32Value(0))
+	diags.Append(checkTokenDurationLimit(types.Int32Value(999), types.Int32Unknown(), types.Int32Value(0), false)...)
+
+	if len(diags) > 0 {
+		t.Errorf("unknown durations returned diagnostics: %v", diags)
+	}
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Values of the network_protocol provider attribute.
+const (
+	networkProtocolAuto = "auto"
+	networkProtocolIPv4 = "ipv4"
+	networkProtocolIPv6 = "ipv6"
+)
+
+// defaultDialTimeout and defaultDialKeepAlive are the dialer settings of
+// http.DefaultTransport.
+const (
+	defaultDialTimeout   = 30 * time.Second
+	defaultDialKeepAlive = 30 * time.Second
+)
+
+// newBaseTransport returns the transport the provider transports wrap.
+// http.DefaultTransport is returned unchanged unless a setting requires a
+// custom transport. With networkProtocol ipv4 or ipv6 only addresses of that
+// family are dialed, so a host resolving to both A and AAAA records doesn't
+// wait on an unreachable family. A zero dialTimeout keeps the default timeout.
+func newBaseTransport(insecure bool, networkProtocol string, dialTimeout time.Duration) http.RoundTripper {
+	restrictNetwork := networkProtocol == networkProtocolIPv4 || networkProtocol == networkProtocolIPv6
+	if !insecure && !restrictNetwork && dialTimeout == 0 {
+		return http.DefaultTransport
+	}
+
+	custom, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return http.DefaultTransport
+	}
+	transport := custom.Clone()
+
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	if restrictNetwork || dialTimeout != 0 {
+		if dialTimeout == 0 {
+			dialTimeout = defaultDialTimeout
+		}
+		dialer := &net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: defaultDialKeepAlive,
+		}
+		transport.DialContext = func(ctx context.Context, network string, address string) (net.Conn, error) {
+			return dialer.DialContext(ctx, restrictDialNetwork(network, networkProtocol), address)
+		}
+	}
+
+	return transport
+}
+
+// restrictDialNetwork returns the network dialed for a tcp connection
+// restricted to the address family of networkProtocol.
+func restrictDialNetwork(network string, networkProtocol string) string {
+	if network != "tcp" {
+		return network
+	}
+
+	switch networkProtocol {
+	case networkProtocolIPv4:
+		return "tcp4"
+	case networkProtocolIPv6:
+		return "tcp6"
+	}
+
+	return network
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// variableKeyPattern matches the keys that can be used both as a Terraform
+// variable name and, apart from dots and dashes, as an environment variable.
+var variableKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// variableKeyValidator warns about variable keys with other characters. The
+// API stores them, but jobs can't always expose them to Terraform or to the
+// shell of the executor.
+func variableKeyValidator() validator.String {
+	return variableKeyFormatValidator{}
+}
+
+type variableKeyFormatValidator struct{}
+
+func (v variableKeyFormatValidator) Description(_ context.Context) string {
+	return "value should only contain letters, digits, underscores, dots and dashes"
+}
+
+func (v variableKeyFormatValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v variableKeyFormatValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if key := req.ConfigValue.ValueString(); !variableKeyPattern.MatchString(key) {
+		resp.Diagnostics.AddAttributeWarning(
+			req.Path,
+			"Unusual variable key",
+			fmt.Sprintf("Variable key %q contains characters other than letters, digits, underscores, dots and dashes. It may not be usable as a Terraform variable or an environment variable in jobs.", key),
+		)
+	}
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestVariableKeyValidator(t *testing.T) {
+	tests := []struct {
+		key         types.String
+		wantWarning bool
+	}{
+		{key: types.StringValue("TF_VAR_region")},
+		{key: types.StringValue("vault.addr")},
+		{key: types.StringValue("my-key")},
+		{key: types.StringValue("vault/addr"), wantWarning: true},
+		{key: types.StringValue("my key"), wantWarning: true},
+		{key: types.StringValue("clé"), wantWarning: true},
+		{key: types.StringNull()},
+		{key: types.StringUnknown()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key.String(), func(t *testing.T) {
+			resp := validator.StringResponse{}
+			variableKeyValidator().ValidateString(context.Background(), validator.StringRequest{Path: path.Root("key"), ConfigValue: test.key}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("variableKeyValidator returned errors: %v", resp.Diagnostics)
+			}
+			if got := resp.Diagnostics.WarningsCount() > 0; got != test.wantWarning {
+				t.Errorf("variableKeyValidator(%s) warned = %t, want %t", test.key, got, test.wantWarning)
+			}
+		})
+	}
+}
+
+func TestVariableUrlEscaping(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	r := NewOrganizationVariableResource()
+	configureTestResource(t, r, server)
+	deleteTestResource(t, r, map[string]string{"id": "vault/addr", "organization_id": "org 1"})
+
+	want := "/api/v1/organization/org%201/globalvar/vault%2Faddr"
+	if len(paths) != 1 || paths[0] != want {
+		t.Errorf("Delete requested %v, want [%s]", paths, want)
+	}
+}
+
+// Values of variables can hold secrets even when sensitive is false, they are
+// never shown in plans.
+func TestVariableValueSensitive(t *testing.T) {
+	resources := []struct {
+		name        string
+		newResource func() resource.Resource
+	}{
+		{name: "workspace_variable", newResource: NewWorkspaceVariableResource},
+		{name: "organization_variable", newResource: NewOrganizationVariableResource},
+		{name: "collection_item", newResource: NewCollectionItemResource},
+	}
+
+	for _, test := range resources {
+		t.Run(test.name, func(t *testing.T) {
+			var resp resource.SchemaResponse
+			test.newResource().Schema(context.Background(), resource.SchemaRequest{}, &resp)
+
+			attribute, ok := resp.Schema.Attributes["value"]
+			if !ok {
+				t.Fatal("schema has no value attribute")
+			}
+			if !attribute.IsSensitive() {
+				t.Error("value is not sensitive")
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -59,17 +58,7 @@ func (d *VcsDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			d.client = &http.Client{Transport: customTransport}
-		} else {
-			d.client = &http.Client{}
-		}
-	} else {
-		d.client = &http.Client{}
-	}
+	d.client = providerData.Client
 
 	d.endpoint = providerData.Endpoint
 	d.token = providerData.Token
@@ -125,8 +114,8 @@ func (d *VcsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 
 	req.Config.Get(ctx, &state)
 
-	apiURL := fmt.Sprintf("%s/api/v1/organization/%s/vcs?filter[vcs]=name=='%s'", d.endpoint, state.OrganizationId.ValueString(), url.PathEscape(state.Name.ValueString()))
-	requestVcs, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	vcsURL := apiURL(d.endpoint, "organization", state.OrganizationId.ValueString(), "vcs") + fmt.Sprintf("?filter[vcs]=name=='%s'", url.PathEscape(state.Name.ValueString()))
+	requestVcs, err := http.NewRequest(http.MethodGet, vcsURL, nil)
 	requestVcs.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
 	requestVcs.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
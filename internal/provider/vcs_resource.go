@@ -3,7 +3,7 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,9 +11,12 @@ import (
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 	"terraform-provider-terrakube/internal/helpers"
+	"time"
 
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -29,11 +32,16 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &VcsResource{}
 var _ resource.ResourceWithImportState = &VcsResource{}
+var _ resource.ResourceWithModifyPlan = &VcsResource{}
+var _ resource.ResourceWithValidateConfig = &VcsResource{}
+var _ resource.ResourceWithConfigValidators = &VcsResource{}
+var _ resource.ResourceWithUpgradeState = &VcsResource{}
 
 type VcsResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type VcsResourceModel struct {
@@ -42,6 +50,7 @@ type VcsResourceModel struct {
 	Name           types.String `tfsdk:"name"`
 	Description    types.String `tfsdk:"description"`
 	VcsType        types.String `tfsdk:"vcs_type"`
+	Type           types.String `tfsdk:"type"`
 	ConnectionType types.String `tfsdk:"connection_type"`
 	ClientId       types.String `tfsdk:"client_id"`
 	ClientSecret   types.String `tfsdk:"client_secret"`
@@ -50,6 +59,68 @@ type VcsResourceModel struct {
 	ApiUrl         types.String `tfsdk:"api_url"`
 	Status         types.String `tfsdk:"status"`
 	ConnectUrl     types.String `tfsdk:"connect_url"`
+	SecretVersion  types.String `tfsdk:"client_secret_version"`
+	WaitForConnect types.Bool   `tfsdk:"wait_for_connection"`
+}
+
+// vcsUpdatedDateKey is the private state key holding the updatedDate of the VCS
+// connection the last time the provider wrote it.
+const vcsUpdatedDateKey = "updated_date"
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func setVcsUpdatedDate(ctx context.Context, private privateStateSetter, updatedDate string) diag.Diagnostics {
+	value, err := json.Marshal(updatedDate)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to store VCS updated date", err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, vcsUpdatedDateKey, value)
+}
+
+// vcsUpdatedAfter reports whether the VCS connection was updated after the last
+// write done by the provider.
+func vcsUpdatedAfter(updatedDate string, lastWrite string) bool {
+	if updatedDate == "" || lastWrite == "" {
+		return false
+	}
+
+	updated, errUpdated := time.Parse(time.RFC3339, updatedDate)
+	written, errWritten := time.Parse(time.RFC3339, lastWrite)
+	if errUpdated != nil || errWritten != nil {
+		return updatedDate != lastWrite
+	}
+
+	return updated.After(written)
+}
+
+// checkVcsUpdatedDate warns when the VCS connection was updated after the last
+// date stored in the private state, then stores updatedDate so the same change
+// is only reported once.
+func checkVcsUpdatedDate(ctx context.Context, private privateStateSetter, lastWrite []byte, vcsId string, updatedDate string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var lastWriteDate string
+	if len(lastWrite) > 0 {
+		if err := json.Unmarshal(lastWrite, &lastWriteDate); err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Unable to read VCS updated date from private state: %s", err))
+		}
+	}
+
+	if !vcsUpdatedAfter(updatedDate, lastWriteDate) {
+		return diags
+	}
+
+	diags.AddWarning(
+		"VCS connection changed outside Terraform",
+		fmt.Sprintf("VCS connection %s was updated at %s, after the last change made by Terraform at %s. The client secret or private key may have been changed outside Terraform, change client_secret_version to send the configured values again.", vcsId, updatedDate, lastWriteDate),
+	)
+	diags.Append(setVcsUpdatedDate(ctx, private, updatedDate)...)
+	return diags
 }
 
 func NewVcsResource() resource.Resource {
@@ -65,18 +136,16 @@ func (r *VcsResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 		MarkdownDescription: "Create a VCS provider for the desired organization. VCS are used by VCS workspace to read " +
 			"github repositories at run time.",
 
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:    true,
-				Description: "Variable Id",
+				Description: "VCS connection Id",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "The name of the VCS connection",
@@ -86,13 +155,27 @@ func (r *VcsResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				Description: "The description of the VCS connection",
 			},
 			"vcs_type": schema.StringAttribute{
+				Optional:           true,
+				Computed:           true,
+				Description:        "The VCS provider of the connection",
+				DeprecationMessage: renamedAttributeDeprecation("type"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("GITHUB", "GITLAB", "BITBUCKET", "AZURE_DEVOPS"),
+				},
+				PlanModifiers: []planmodifier.String{
+					renamedAttributePlanModifier("type", "GITHUB"),
+				},
+			},
+			"type": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
-				Default:     stringdefault.StaticString("GITHUB"),
-				Description: "Variable description",
+				Description: "The VCS provider of the connection, valid values are `GITHUB`, `GITLAB`, `BITBUCKET` and `AZURE_DEVOPS`, default is `GITHUB`",
 				Validators: []validator.String{
 					stringvalidator.OneOf("GITHUB", "GITLAB", "BITBUCKET", "AZURE_DEVOPS"),
 				},
+				PlanModifiers: []planmodifier.String{
+					renamedAttributePlanModifier("vcs_type", "GITHUB"),
+				},
 			},
 			"connection_type": schema.StringAttribute{
 				Optional:    true,
@@ -150,6 +233,14 @@ func (r *VcsResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				Computed:    true,
 				Description: "The connect URL of the VCS connection, after adding the VCS connection, please logon to this URL to connect.",
 			},
+			"client_secret_version": schema.StringAttribute{
+				Optional:    true,
+				Description: "An arbitrary value that sends the client secret and private key again when changed, useful after rotating the secret outside Terraform.",
+			},
+			"wait_for_connection": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Wait after creating the VCS connection until it is connected with the connect_url, up to 10 minutes. Default is `false`.",
+			},
 			"status": schema.StringAttribute{
 				Computed: true,
 				Default:  stringdefault.StaticString("PENDING"),
@@ -162,6 +253,89 @@ func (r *VcsResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 	}
 }
 
+const (
+	// vcsConnectionPollInterval is the first delay between two checks of the VCS connection status.
+	vcsConnectionPollInterval = 5 * time.Second
+	// vcsConnectionWaitTimeout bounds how long wait_for_connection waits.
+	vcsConnectionWaitTimeout = 10 * time.Minute
+)
+
+// vcsClientIdPatterns describes the usual shape of the client id of each VCS type.
+// Formats change over time, so a mismatch is only reported as a warning.
+var vcsClientIdPatterns = map[string]struct {
+	pattern     *regexp.Regexp
+	description string
+}{
+	"GITHUB":       {regexp.MustCompile(`^(Iv1\.[0-9a-f]{16}|(Iv23|Ov23)[0-9A-Za-z]{16}|[0-9a-f]{20}|[0-9]+)$`), "an OAuth app client id like Ov23li... or Iv1..., or a numeric GitHub App id"},
+	"GITLAB":       {regexp.MustCompile(`^[0-9a-f]{64}$`), "a 64 character hexadecimal application id"},
+	"BITBUCKET":    {regexp.MustCompile(`^[0-9A-Za-z]{18}$`), "an 18 character OAuth consumer key"},
+	"AZURE_DEVOPS": {regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`), "an application id in GUID format"},
+}
+
+// vcsEndpointHints maps well known host names to the VCS type they belong to.
+var vcsEndpointHints = map[string]string{
+	"github":           "GITHUB",
+	"gitlab":           "GITLAB",
+	"bitbucket":        "BITBUCKET",
+	"dev.azure.com":    "AZURE_DEVOPS",
+	"visualstudio.com": "AZURE_DEVOPS",
+}
+
+func (r *VcsResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("vcs_type"),
+			path.MatchRoot("type"),
+		),
+	}
+}
+
+func (r *VcsResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: renamedAttributeStateUpgrader("vcs_type", "type"),
+	}
+}
+
+func (r *VcsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config VcsResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.VcsType.IsUnknown() || config.Type.IsUnknown() {
+		return
+	}
+
+	vcsType := "GITHUB"
+	if !config.Type.IsNull() {
+		vcsType = config.Type.ValueString()
+	} else if !config.VcsType.IsNull() {
+		vcsType = config.VcsType.ValueString()
+	}
+
+	if clientId, ok := vcsClientIdPatterns[vcsType]; ok && !config.ClientId.IsNull() && !config.ClientId.IsUnknown() && !clientId.pattern.MatchString(config.ClientId.ValueString()) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("client_id"),
+			"Unexpected client id format",
+			fmt.Sprintf("The client id doesn't look like a %s client id, which is usually %s. Check that the client id belongs to the %s application.", vcsType, clientId.description, vcsType),
+		)
+	}
+
+	if config.Endpoint.IsNull() || config.Endpoint.IsUnknown() {
+		return
+	}
+
+	endpoint := strings.ToLower(config.Endpoint.ValueString())
+	for host, hostVcsType := range vcsEndpointHints {
+		if strings.Contains(endpoint, host) && hostVcsType != vcsType {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("endpoint"),
+				"Endpoint doesn't match type",
+				fmt.Sprintf("The endpoint %s looks like a %s endpoint but type is %s.", config.Endpoint.ValueString(), hostVcsType, vcsType),
+			)
+			return
+		}
+	}
+}
+
 func (r *VcsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -177,20 +351,11 @@ func (r *VcsResource) Configure(ctx context.Context, req resource.ConfigureReque
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Organization Variable resource", map[string]any{"success": true})
 }
@@ -224,7 +389,7 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 		return
 	}
 
-	vcsRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/vcs", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	vcsRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "vcs"), strings.NewReader(out.String()))
 	vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	vcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -234,7 +399,7 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	vcsResponse, err := r.client.Do(vcsRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, %s, error: %s", formatAPIError(vcsResponse, nil), err))
 		return
 	}
 
@@ -242,12 +407,33 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 	if err != nil {
 		tflog.Error(ctx, fmt.Sprintf("Error reading VCS resource response, error: %s, response status: %s", err, vcsResponse.Status))
 	}
+	if vcsResponse.StatusCode != http.StatusCreated {
+		resp.Diagnostics.AddError("Error creating VCS resource", fmt.Sprintf("Error creating VCS resource, response status: %s, response body: %s", vcsResponse.Status, string(bodyResponse)))
+		return
+	}
+
+	// Save the id as soon as it is known, so a parsing problem below does not
+	// leave an untracked VCS connection in the organization.
+	vcsId := helpers.GetIDFromResponse(vcsResponse, bodyResponse)
+	if vcsId != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), vcsId)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), plan.OrganizationId)...)
+	}
+
 	vcs := &client.VcsEntity{}
 
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), vcs)
 	tflog.Info(ctx, string(bodyResponse))
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, vcsResponse.Status))
+		if vcsId == "" {
+			resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, error: %s, response status: %s", err, vcsResponse.Status))
+			return
+		}
+
+		resp.Diagnostics.AddWarning("Unable to read created VCS", fmt.Sprintf("VCS %s was created but the response could not be parsed, the remaining attributes will be refreshed on the next plan. Error: %s", vcsId, err))
+		plan.ID = types.StringValue(vcsId)
+		nullUnknownValues(&plan)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 		return
 	}
 
@@ -255,8 +441,9 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	plan.ID = types.StringValue(vcs.ID)
 	plan.Name = types.StringValue(vcs.Name)
-	plan.Description = types.StringValue(vcs.Description)
+	plan.Description = optionalStringValue(plan.Description, vcs.Description)
 	plan.VcsType = types.StringValue(vcs.VcsType)
+	plan.Type = types.StringValue(vcs.VcsType)
 	plan.ClientId = types.StringValue(vcs.ClientId)
 	plan.Endpoint = types.StringValue(vcs.Endpoint)
 	plan.ApiUrl = types.StringValue(vcs.ApiUrl)
@@ -273,7 +460,17 @@ func (r *VcsResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	if vcs.Status == "PENDING" {
 		tflog.Warn(ctx, fmt.Sprintf("VCS connection is pending, please logon to %s to connect. Check doc here %s", plan.ConnectUrl, helpers.GetVCSProviderDoc()))
+
+		if plan.WaitForConnect.ValueBool() {
+			status, err := r.waitForConnection(ctx, plan.OrganizationId.ValueString(), vcs.ID)
+			plan.Status = types.StringValue(status)
+			if err != nil {
+				resp.Diagnostics.AddError("VCS connection not connected", fmt.Sprintf("VCS connection %s is %s, logon to %s to connect it: %s", vcs.ID, status, plan.ConnectUrl.ValueString(), err))
+			}
+		}
 	}
+
+	resp.Diagnostics.Append(setVcsUpdatedDate(ctx, resp.Private, vcs.UpdatedDate)...)
 	tflog.Info(ctx, "VCS Resource Created", map[string]any{"success": true})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -287,7 +484,7 @@ func (r *VcsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
-	vcsRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/vcs/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	vcsRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "vcs", state.ID.ValueString()), nil)
 	vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	vcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -297,7 +494,7 @@ func (r *VcsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 	vcsResponse, err := r.client.Do(vcsRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, %s, error: %s", formatAPIError(vcsResponse, nil), err))
 		return
 	}
 
@@ -305,6 +502,11 @@ func (r *VcsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	if err != nil {
 		tflog.Error(ctx, fmt.Sprintf("Error reading organization variable resource response, error: %s, response status: %s", err, vcsResponse.Status))
 	}
+
+	if !checkReadResponse(ctx, resp, vcsResponse, bodyResponse, "VCS") {
+		return
+	}
+
 	vcs := &client.VcsEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -319,14 +521,19 @@ func (r *VcsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 	state.ID = types.StringValue(vcs.ID)
 	state.Name = types.StringValue(vcs.Name)
-	state.Description = types.StringValue(vcs.Description)
+	state.Description = optionalStringValue(state.Description, vcs.Description)
 	state.VcsType = types.StringValue(vcs.VcsType)
+	state.Type = types.StringValue(vcs.VcsType)
 	state.ConnectionType = types.StringValue(vcs.ConnectionType)
 	state.ClientId = types.StringValue(vcs.ClientId)
 	state.Endpoint = types.StringValue(vcs.Endpoint)
 	state.ApiUrl = types.StringValue(vcs.ApiUrl)
 	state.Status = types.StringValue(vcs.Status)
 
+	lastWrite, diags := req.Private.GetKey(ctx, vcsUpdatedDateKey)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(checkVcsUpdatedDate(ctx, resp.Private, lastWrite, vcs.ID, vcs.UpdatedDate)...)
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -373,9 +580,9 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		return
 	}
 
-	tflog.Info(ctx, "Body Update Request: "+out.String())
+	tflog.Debug(ctx, "Body Update Request: "+helpers.RedactPayload(out.String()))
 
-	vcsRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/vcs/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	vcsRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "vcs", state.ID.ValueString()), strings.NewReader(out.String()))
 	vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	vcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -385,7 +592,7 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	vcsResponse, err := r.client.Do(vcsRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, %s, error: %s", formatAPIError(vcsResponse, nil), err))
 		return
 	}
 
@@ -396,7 +603,7 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	vcsRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/vcs/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	vcsRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "vcs", state.ID.ValueString()), nil)
 	vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	vcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -406,7 +613,7 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	vcsResponse, err = r.client.Do(vcsRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, %s, error: %s", formatAPIError(vcsResponse, nil), err))
 		return
 	}
 
@@ -426,9 +633,10 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	plan.ID = types.StringValue(state.ID.ValueString())
 	plan.Name = types.StringValue(vcs.Name)
-	plan.Description = types.StringValue(vcs.Description)
+	plan.Description = optionalStringValue(plan.Description, vcs.Description)
 	plan.ConnectionType = types.StringValue(vcs.ConnectionType)
 	plan.VcsType = types.StringValue(vcs.VcsType)
+	plan.Type = types.StringValue(vcs.VcsType)
 	plan.ClientId = types.StringValue(vcs.ClientId)
 	if plan.ClientSecret.ValueString() != "" {
 		tflog.Info(ctx, "Client secret is not available in the response, setting to original value set in the request.")
@@ -447,6 +655,8 @@ func (r *VcsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		tflog.Warn(ctx, fmt.Sprintf("VCS connection is pending, please logon to %s to connect. Check doc here %s", plan.ConnectUrl, helpers.GetVCSProviderDoc()))
 	}
 
+	resp.Diagnostics.Append(setVcsUpdatedDate(ctx, resp.Private, vcs.UpdatedDate)...)
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -460,7 +670,7 @@ func (r *VcsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		return
 	}
 
-	vcsRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/vcs/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), nil)
+	vcsRequest, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", data.OrganizationId.ValueString(), "vcs", data.ID.ValueString()), nil)
 	vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating VCS resource request", fmt.Sprintf("Error creating VCS resource request: %s", err))
@@ -468,8 +678,14 @@ func (r *VcsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	}
 
 	vcsResponse, err := r.client.Do(vcsRequest)
-	if err != nil || vcsResponse.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, response status %s, response body %s, error: %s", vcsResponse.Status, vcsResponse.Request.Body, err))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request, error: %s", err))
+		return
+	}
+
+	bodyResponse, _ := io.ReadAll(vcsResponse.Body)
+	if err = checkDeleteResponse(vcsResponse, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing VCS resource request", fmt.Sprintf("Error executing VCS resource request: %s", err))
 		return
 	}
 }
@@ -533,8 +749,14 @@ func (r VcsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequ
 	if req.Plan.Raw.IsNull() {
 		return
 	}
+
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var plan VcsResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
 	// If it's not a create operation, we don't need to update the status
 	if !req.State.Raw.IsNull() {
 		var state VcsResourceModel
@@ -555,8 +777,62 @@ func (r VcsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequ
 	}
 	if plan.ConnectionType.Equal(types.StringValue("STANDALONE")) {
 		plan.Status = types.StringValue("COMPLETED")
+	} else if req.State.Raw.IsNull() && plan.WaitForConnect.ValueBool() {
+		// The status is only known once the connection is done
+		plan.Status = types.StringUnknown()
 	}
 	plan.ConnectUrl = types.StringValue(connectUrl)
 
 	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
 }
+
+// waitForConnection polls the VCS connection until it leaves the PENDING
+// status and returns the last status read.
+func (r *VcsResource) waitForConnection(ctx context.Context, organizationId string, id string) (string, error) {
+	status := "PENDING"
+
+	err := helpers.Poll(ctx, vcsConnectionPollInterval, vcsConnectionWaitTimeout, func(ctx context.Context) (helpers.PollResult, error) {
+		vcsRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", organizationId, "vcs", id), nil)
+		if err != nil {
+			return helpers.PollFatal, err
+		}
+		vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+		vcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+		vcsResponse, err := r.client.Do(vcsRequest)
+		if err != nil {
+			return helpers.PollRetry, err
+		}
+		defer vcsResponse.Body.Close()
+
+		bodyResponse, err := io.ReadAll(vcsResponse.Body)
+		if err != nil {
+			return helpers.PollRetry, err
+		}
+
+		if err = client.CheckResponse(vcsResponse, bodyResponse); err != nil {
+			if client.IsUnauthorized(err) || client.IsNotFound(err) {
+				return helpers.PollFatal, err
+			}
+			return helpers.PollRetry, err
+		}
+
+		vcs := &client.VcsEntity{}
+		if err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), vcs); err != nil {
+			return helpers.PollFatal, err
+		}
+
+		status = vcs.Status
+		switch status {
+		case "PENDING":
+			tflog.Info(ctx, fmt.Sprintf("Waiting for VCS connection %s to be connected", id))
+			return helpers.PollRetry, nil
+		case "COMPLETED":
+			return helpers.PollDone, nil
+		default:
+			return helpers.PollFatal, fmt.Errorf("connection failed with status %s", status)
+		}
+	})
+
+	return status, err
+}
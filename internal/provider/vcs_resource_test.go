@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCheckVcsUpdatedDateWarnsOnce(t *testing.T) {
+	ctx := context.Background()
+	private := testPrivateState{}
+	if diags := setVcsUpdatedDate(ctx, private, "2024-01-01T10:00:00Z"); diags.HasError() {
+		t.Fatalf("setVcsUpdatedDate returned %v", diags)
+	}
+
+	diags := checkVcsUpdatedDate(ctx, private, private[vcsUpdatedDateKey], "vcs-id", "2024-02-01T10:00:00Z")
+	if diags.WarningsCount() != 1 {
+		t.Fatalf("first read returned %d warnings, want 1", diags.WarningsCount())
+	}
+	if got := string(private[vcsUpdatedDateKey]); got != `"2024-02-01T10:00:00Z"` {
+		t.Errorf("private updated date = %s, want the date of the change", got)
+	}
+
+	diags = checkVcsUpdatedDate(ctx, private, private[vcsUpdatedDateKey], "vcs-id", "2024-02-01T10:00:00Z")
+	if diags.WarningsCount() != 0 {
+		t.Errorf("second read of the same change returned %d warnings, want 0", diags.WarningsCount())
+	}
+}
+
+func TestCheckVcsUpdatedDateWithoutBaseline(t *testing.T) {
+	private := testPrivateState{}
+
+	diags := checkVcsUpdatedDate(context.Background(), private, nil, "vcs-id", "2024-02-01T10:00:00Z")
+	if diags.WarningsCount() != 0 {
+		t.Errorf("read without a stored date returned %d warnings, want 0", diags.WarningsCount())
+	}
+	if len(private) > 0 {
+		t.Errorf("private state written without a change: %v", private)
+	}
+}
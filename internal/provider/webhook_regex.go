@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// javaOnlyRegexSyntax lists constructs supported by the Java regex engine of
+// the Terrakube API but not by Go regexp (RE2). A pattern using them can't be
+// checked here, it is reported as a warning instead of an error.
+var javaOnlyRegexSyntax = map[string]*regexp.Regexp{
+	"lookaround":                       regexp.MustCompile(`\(\?<?[=!]`),
+	"atomic group":                     regexp.MustCompile(`\(\?>`),
+	"possessive quantifier":            regexp.MustCompile(`[*+?}]\+`),
+	"backreference":                    regexp.MustCompile(`\\[1-9]|\\k<`),
+	"\\Z, \\G, \\h, \\R or \\X escape": regexp.MustCompile(`\\[ZGhRX]`),
+}
+
+// goOnlyRegexSyntax lists constructs accepted by Go regexp that the Java regex
+// engine rejects or reads differently.
+var goOnlyRegexSyntax = map[string]*regexp.Regexp{
+	"(?P<name>) named group": regexp.MustCompile(`\(\?P<`),
+	"(?U) flag":              regexp.MustCompile(`\(\?[a-zA-Z-]*U`),
+}
+
+// webhookRegexListValidator checks that each entry of a webhook branch or path
+// list is a valid regular expression. The Terrakube API evaluates them with
+// Java, Go regexp is used as a best effort check: a pattern that doesn't
+// compile is an error, unless it uses Java only syntax which is a warning.
+func webhookRegexListValidator() validator.List {
+	return webhookRegexValidator{}
+}
+
+type webhookRegexValidator struct{}
+
+func (v webhookRegexValidator) Description(_ context.Context) string {
+	return "each value must be a valid regular expression"
+}
+
+func (v webhookRegexValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v webhookRegexValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for i, element := range req.ConfigValue.Elements() {
+		value, ok := element.(types.String)
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		pattern := value.ValueString()
+		elementPath := req.Path.AtListIndex(i)
+
+		if _, err := regexp.Compile(pattern); err != nil {
+			if construct := matchRegexSyntax(javaOnlyRegexSyntax, pattern); construct != "" {
+				resp.Diagnostics.AddAttributeWarning(
+					elementPath,
+					"Regular expression not checked",
+					fmt.Sprintf("%q uses a %s, which the Terrakube API supports but can't be checked by the provider. Make sure it is a valid Java regular expression.", pattern, construct),
+				)
+				continue
+			}
+
+			resp.Diagnostics.AddAttributeError(
+				elementPath,
+				"Invalid regular expression",
+				fmt.Sprintf("%q is not a valid regular expression, it would never match: %s", pattern, err),
+			)
+			continue
+		}
+
+		if construct := matchRegexSyntax(goOnlyRegexSyntax, pattern); construct != "" {
+			resp.Diagnostics.AddAttributeWarning(
+				elementPath,
+				"Regular expression may not work in Terrakube",
+				fmt.Sprintf("%q uses a %s, which Java regular expressions used by the Terrakube API don't support the same way.", pattern, construct),
+			)
+		}
+	}
+}
+
+// matchRegexSyntax returns the name of the first construct of syntax used by pattern.
+func matchRegexSyntax(syntax map[string]*regexp.Regexp, pattern string) string {
+	for name, construct := range syntax {
+		if construct.MatchString(pattern) {
+			return name
+		}
+	}
+	return ""
+}
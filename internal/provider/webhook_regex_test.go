@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestWebhookRegexListValidator(t *testing.T) {
+	tests := []struct {
+		name        string
+		patterns    []string
+		wantErr     bool
+		wantWarning bool
+	}{
+		{name: "plain", patterns: []string{"main", "feature/.*", "^release-[0-9]+$"}},
+		{name: "invalid", patterns: []string{"main", "feature/(.*"}, wantErr: true},
+		{name: "lookahead", patterns: []string{"^(?!main$).*"}, wantWarning: true},
+		{name: "lookbehind", patterns: []string{"(?<=release-)[0-9]+"}, wantWarning: true},
+		{name: "go named group", patterns: []string{"(?P<team>[a-z]+)/.*"}, wantWarning: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			elements := []attr.Value{}
+			for _, pattern := range test.patterns {
+				elements = append(elements, types.StringValue(pattern))
+			}
+
+			resp := validator.ListResponse{}
+			webhookRegexListValidator().ValidateList(context.Background(), validator.ListRequest{
+				Path:        path.Root("branch"),
+				ConfigValue: types.ListValueMust(types.StringType, elements),
+			}, &resp)
+
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Errorf("ValidateList errors = %v, want error %t", resp.Diagnostics.Errors(), test.wantErr)
+			}
+			if (resp.Diagnostics.WarningsCount() > 0) != test.wantWarning {
+				t.Errorf("ValidateList warnings = %v, want warning %t", resp.Diagnostics.Warnings(), test.wantWarning)
+			}
+		})
+	}
+}
+
+func TestWebhookRegexListValidatorNull(t *testing.T) {
+	for _, value := range []types.List{types.ListNull(types.StringType), types.ListUnknown(types.StringType)} {
+		resp := validator.ListResponse{}
+		webhookRegexListValidator().ValidateList(context.Background(), validator.ListRequest{Path: path.Root("branch"), ConfigValue: value}, &resp)
+
+		if len(resp.Diagnostics) > 0 {
+			t.Errorf("ValidateList(%s) returned %v", value, resp.Diagnostics)
+		}
+	}
+}
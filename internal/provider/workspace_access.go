@@ -0,0 +1,310 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// workspaceAccessTeams are the names of the teams whose access is managed by
+// the access attribute.
+var workspaceAccessTeams = managedKeys{privateKey: "access_teams"}
+
+// workspaceAccessModel is an element of the access attribute.
+type workspaceAccessModel struct {
+	TeamName        types.String `tfsdk:"team_name"`
+	ManageState     types.Bool   `tfsdk:"manage_state"`
+	ManageJob       types.Bool   `tfsdk:"manage_job"`
+	ManageWorkspace types.Bool   `tfsdk:"manage_workspace"`
+}
+
+var workspaceAccessType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"team_name":        types.StringType,
+		"manage_state":     types.BoolType,
+		"manage_job":       types.BoolType,
+		"manage_workspace": types.BoolType,
+	},
+}
+
+// workspaceAccessAttributes returns the access and exclusive_access attributes
+// shared by the workspace resources.
+func workspaceAccessAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"access": schema.ListNestedAttribute{
+			Optional:    true,
+			Description: "Teams granted access to the workspace. Only the teams listed here are managed, access granted to other teams, for example from the UI, is left untouched unless `exclusive_access` is `true`. A team removed from the list loses its access. A team that already has access when it is added to the list is an error, unless `exclusive_access` is `true`, so the same team must not be granted access both here and from another configuration.",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"team_name": schema.StringAttribute{
+						Required:    true,
+						Description: "Name of the team",
+					},
+					"manage_state": schema.BoolAttribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+						Description: "Allow the team to manage the workspace state, default is `false`.",
+					},
+					"manage_job": schema.BoolAttribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+						Description: "Allow the team to run jobs on the workspace, default is `false`.",
+					},
+					"manage_workspace": schema.BoolAttribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+						Description: "Allow the team to manage the workspace settings, default is `false`.",
+					},
+				},
+			},
+		},
+		"exclusive_access": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "Manage the access of every team to the workspace with `access`, access of teams not listed is removed. Default is `false`.",
+		},
+	}
+}
+
+// workspaceAccess reconciles the team access of a workspace with the access attribute.
+type workspaceAccess struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func (w *workspaceAccess) accessURL(organizationId string, workspaceId string) string {
+	return apiURL(w.endpoint, "organization", url.PathEscape(organizationId), "workspace", url.PathEscape(workspaceId), "access")
+}
+
+// teamAccess returns the access of the workspace by team name.
+func (w *workspaceAccess) teamAccess(organizationId string, workspaceId string) (map[string]*client.WorkspaceAccessEntity, error) {
+	items, err := listAll(w.client, w.token, w.accessURL(organizationId, workspaceId), reflect.TypeOf(new(client.WorkspaceAccessEntity)))
+	if err != nil {
+		return nil, err
+	}
+
+	byTeam := map[string]*client.WorkspaceAccessEntity{}
+	for _, item := range items {
+		access := item.(*client.WorkspaceAccessEntity)
+		byTeam[access.Name] = access
+	}
+
+	return byTeam, nil
+}
+
+func (w *workspaceAccess) do(method string, url string, body interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		var out = new(bytes.Buffer)
+		if err := jsonapi.MarshalPayload(out, body); err != nil {
+			return fmt.Errorf("unable to marshal payload: %w", err)
+		}
+		reader = out
+	}
+
+	request, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", w.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if method == http.MethodDelete {
+		return checkDeleteResponse(response, bodyResponse)
+	}
+
+	return client.CheckResponse(response, bodyResponse)
+}
+
+// Sync makes the team access of the workspace match the access list, see
+// reconcileKeys. managed are the teams granted by a previous Sync, they are
+// written back to the private state with the teams granted now.
+func (w *workspaceAccess) Sync(ctx context.Context, organizationId string, workspaceId string, access types.List, exclusive bool, managed []string, private privateStateWriter) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	grants := []workspaceAccessModel{}
+	if !access.IsNull() {
+		diags.Append(access.ElementsAs(ctx, &grants, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	if len(grants) == 0 && len(managed) == 0 && !exclusive {
+		return diags
+	}
+
+	desired := map[string]*client.WorkspaceAccessEntity{}
+	desiredTeams := []string{}
+	for _, grant := range grants {
+		team := grant.TeamName.ValueString()
+		if _, ok := desired[team]; ok {
+			diags.AddError("Duplicate workspace access", fmt.Sprintf("Team %s is listed more than once in access of workspace %s.", team, workspaceId))
+			return diags
+		}
+		desired[team] = &client.WorkspaceAccessEntity{
+			Name:            team,
+			ManageState:     grant.ManageState.ValueBool(),
+			ManageWorkspace: grant.ManageWorkspace.ValueBool(),
+			ManageJob:       grant.ManageJob.ValueBool(),
+		}
+		desiredTeams = append(desiredTeams, team)
+	}
+
+	existing, err := w.teamAccess(organizationId, workspaceId)
+	if err != nil {
+		diags.AddError("Error reading workspace access", err.Error())
+		return diags
+	}
+
+	existingTeams := make([]string, 0, len(existing))
+	for team := range existing {
+		existingTeams = append(existingTeams, team)
+	}
+	reconciliation := reconcileKeys(desiredTeams, existingTeams, managed, exclusive)
+
+	for _, team := range reconciliation.Conflict {
+		diags.AddError("Workspace access already exists", fmt.Sprintf("Team %s already has access %s to workspace %s, granted outside access. Remove the team from access, delete its access first or set exclusive_access = true.", team, existing[team].ID, workspaceId))
+	}
+	if len(reconciliation.Conflict) > 0 {
+		return diags
+	}
+
+	isManaged := map[string]bool{}
+	for _, team := range managed {
+		isManaged[team] = true
+	}
+
+	accessURL := w.accessURL(organizationId, workspaceId)
+	teams := []string{}
+
+	for _, team := range reconciliation.Create {
+		if err := w.do(http.MethodPost, accessURL, desired[team]); err != nil {
+			diags.AddError("Error setting workspace access", fmt.Sprintf("Error setting access of team %s to workspace %s: %s", team, workspaceId, err))
+			if isManaged[team] {
+				teams = append(teams, team)
+			}
+			continue
+		}
+		teams = append(teams, team)
+	}
+
+	for _, team := range reconciliation.Update {
+		teams = append(teams, team)
+		current, body := existing[team], desired[team]
+		if current.ManageState == body.ManageState && current.ManageWorkspace == body.ManageWorkspace && current.ManageJob == body.ManageJob {
+			continue
+		}
+		body.ID = current.ID
+		if err := w.do(http.MethodPatch, endpointURL(accessURL, url.PathEscape(current.ID)), body); err != nil {
+			diags.AddError("Error setting workspace access", fmt.Sprintf("Error setting access of team %s to workspace %s: %s", team, workspaceId, err))
+		}
+	}
+
+	for _, team := range reconciliation.Delete {
+		if err := w.do(http.MethodDelete, endpointURL(accessURL, url.PathEscape(existing[team].ID)), nil); err != nil {
+			diags.AddError("Error deleting workspace access", fmt.Sprintf("Error deleting access of team %s to workspace %s: %s", team, workspaceId, err))
+			if isManaged[team] {
+				teams = append(teams, team)
+			}
+			continue
+		}
+		tflog.Info(ctx, "Workspace access removed", map[string]any{"team": team})
+	}
+
+	// Managed teams that failed are kept so the next apply retries them
+	diags.Append(workspaceAccessTeams.Write(ctx, private, teams)...)
+
+	return diags
+}
+
+// Read returns the access of the owned teams, see ownedKeys, in the order of
+// current. Teams missing from current are appended sorted, so with exclusive
+// the access granted outside Terraform shows as drift.
+func (w *workspaceAccess) Read(ctx context.Context, organizationId string, workspaceId string, current types.List, exclusive bool, managed []string) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	existing, err := w.teamAccess(organizationId, workspaceId)
+	if err != nil {
+		diags.AddError("Error reading workspace access", err.Error())
+		return current, diags
+	}
+
+	grants := []workspaceAccessModel{}
+	if !current.IsNull() && !current.IsUnknown() {
+		diags.Append(current.ElementsAs(ctx, &grants, false)...)
+		if diags.HasError() {
+			return current, diags
+		}
+	}
+
+	existingTeams := make([]string, 0, len(existing))
+	for team := range existing {
+		existingTeams = append(existingTeams, team)
+	}
+	owned := map[string]bool{}
+	for _, team := range ownedKeys(existingTeams, managed, exclusive) {
+		owned[team] = true
+	}
+
+	teams := []string{}
+	for _, grant := range grants {
+		if team := grant.TeamName.ValueString(); owned[team] {
+			teams = append(teams, team)
+			delete(owned, team)
+		}
+	}
+	others := make([]string, 0, len(owned))
+	for team := range owned {
+		others = append(others, team)
+	}
+	sort.Strings(others)
+	teams = append(teams, others...)
+
+	values := []workspaceAccessModel{}
+	for _, team := range teams {
+		access := existing[team]
+		values = append(values, workspaceAccessModel{
+			TeamName:        types.StringValue(team),
+			ManageState:     types.BoolValue(access.ManageState),
+			ManageJob:       types.BoolValue(access.ManageJob),
+			ManageWorkspace: types.BoolValue(access.ManageWorkspace),
+		})
+	}
+
+	if current.IsNull() && len(values) == 0 {
+		return current, diags
+	}
+
+	return types.ListValueFrom(ctx, workspaceAccessType, values)
+}
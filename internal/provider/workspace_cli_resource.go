@@ -3,21 +3,22 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/rand"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -25,21 +26,37 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceCliResource{}
 var _ resource.ResourceWithImportState = &WorkspaceCliResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceCliResource{}
 
 type WorkspaceCliResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	uiEndpoint            string
+	fetchWorkspaceStatus  bool
+	defaultOrganizationId string
+	variables             *workspaceVariableCache
 }
 
 type WorkspaceCliResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	Description    types.String `tfsdk:"description"`
-	IaCType        types.String `tfsdk:"iac_type"`
-	IaCVersion     types.String `tfsdk:"iac_version"`
-	ExecutionMode  types.String `tfsdk:"execution_mode"`
+	ID                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	OrganizationId       types.String `tfsdk:"organization_id"`
+	Description          types.String `tfsdk:"description"`
+	IaCType              types.String `tfsdk:"iac_type"`
+	IaCVersion           types.String `tfsdk:"iac_version"`
+	ExecutionMode        types.String `tfsdk:"execution_mode"`
+	TagNames             types.Set    `tfsdk:"tag_names"`
+	Environment          types.Map    `tfsdk:"environment"`
+	Access               types.List   `tfsdk:"access"`
+	ExclusiveAccess      types.Bool   `tfsdk:"exclusive_access"`
+	CreateMissingTags    types.Bool   `tfsdk:"create_missing_tags"`
+	ExclusiveTags        types.Bool   `tfsdk:"exclusive_tags"`
+	ExclusiveEnvironment types.Bool   `tfsdk:"exclusive_environment"`
+	DestroyProtection    types.String `tfsdk:"destroy_protection"`
+	WebUrl               types.String `tfsdk:"web_url"`
+	CurrentStateSerial   types.Int64  `tfsdk:"current_state_serial"`
+	LastJobDate          types.String `tfsdk:"last_job_date"`
 }
 
 func NewWorkspaceCliResource() resource.Resource {
@@ -64,10 +81,7 @@ func (r *WorkspaceCliResource) Schema(ctx context.Context, req resource.SchemaRe
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Workspace CLI name",
@@ -77,12 +91,20 @@ func (r *WorkspaceCliResource) Schema(ctx context.Context, req resource.SchemaRe
 				Description: "Workspace CLI description",
 			},
 			"execution_mode": schema.StringAttribute{
-				Required:    true,
-				Description: "Workspace CLI execution mode (remote or local). Remote execution will require setting up executor.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("remote"),
+				Description: "Workspace CLI execution mode (remote or local), default is `remote`. Remote execution will require setting up executor.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("remote", "local"),
+				},
 			},
 			"iac_type": schema.StringAttribute{
 				Required:    true,
 				Description: "Workspace CLI IaC type (Supported values terraform or tofu)",
+				Validators: []validator.String{
+					stringvalidator.OneOf("terraform", "tofu"),
+				},
 			},
 			"iac_version": schema.StringAttribute{
 				Required:    true,
@@ -90,6 +112,21 @@ func (r *WorkspaceCliResource) Schema(ctx context.Context, req resource.SchemaRe
 			},
 		},
 	}
+
+	for name, attribute := range workspaceTagNamesAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	resp.Schema.Attributes["destroy_protection"] = destroyProtectionAttribute()
+	for name, attribute := range workspaceEnvironmentAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range workspaceAccessAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	resp.Schema.Attributes["web_url"] = workspaceWebUrlAttribute()
+	for name, attribute := range workspaceStatusAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
 }
 
 func (r *WorkspaceCliResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -107,20 +144,14 @@ func (r *WorkspaceCliResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
+	r.variables = providerData.WorkspaceVariables
+	r.uiEndpoint = providerData.UiEndpoint
+	r.fetchWorkspaceStatus = providerData.FetchWorkspaceStatus
 
 	tflog.Debug(ctx, "Configuring Workspace CLI resource", map[string]any{"success": true})
 }
@@ -152,7 +183,7 @@ func (r *WorkspaceCliResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	workspaceCliRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
+	workspaceCliRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "workspace"), strings.NewReader(out.String()))
 	workspaceCliRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceCliRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -183,12 +214,34 @@ func (r *WorkspaceCliResource) Create(ctx context.Context, req resource.CreateRe
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
 	plan.ID = types.StringValue(newWorkspaceCli.ID)
+	plan.WebUrl = types.StringValue(workspaceWebUrl(r.uiEndpoint, plan.OrganizationId.ValueString(), plan.ID.ValueString()))
+
+	status := &workspaceStatus{client: r.client, endpoint: r.endpoint, token: r.token, fetch: r.fetchWorkspaceStatus}
+	var statusDiags diag.Diagnostics
+	plan.CurrentStateSerial, plan.LastJobDate, statusDiags = status.Read(ctx, plan.OrganizationId.ValueString(), newWorkspaceCli)
+	resp.Diagnostics.Append(statusDiags...)
+
 	plan.Name = types.StringValue(newWorkspaceCli.Name)
 	plan.Description = types.StringValue(newWorkspaceCli.Description)
 	plan.IaCType = types.StringValue(newWorkspaceCli.IaCType)
 	plan.IaCVersion = types.StringValue(newWorkspaceCli.IaCVersion)
 	plan.ExecutionMode = types.StringValue(newWorkspaceCli.ExecutionMode)
 
+	if !plan.TagNames.IsNull() || plan.ExclusiveTags.ValueBool() {
+		tags := &workspaceTags{client: r.client, endpoint: r.endpoint, token: r.token}
+		resp.Diagnostics.Append(tags.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.TagNames, plan.CreateMissingTags.ValueBool(), plan.ExclusiveTags.ValueBool(), nil, resp.Private)...)
+	}
+
+	if !plan.Environment.IsNull() || plan.ExclusiveEnvironment.ValueBool() {
+		environment := &workspaceEnvironment{client: r.client, endpoint: r.endpoint, token: r.token, variables: r.variables}
+		resp.Diagnostics.Append(environment.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.Environment, plan.ExclusiveEnvironment.ValueBool(), nil, resp.Private)...)
+	}
+
+	if !plan.Access.IsNull() || plan.ExclusiveAccess.ValueBool() {
+		access := &workspaceAccess{client: r.client, endpoint: r.endpoint, token: r.token}
+		resp.Diagnostics.Append(access.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.Access, plan.ExclusiveAccess.ValueBool(), nil, resp.Private)...)
+	}
+
 	tflog.Info(ctx, "Workspace Cli Resource Created", map[string]any{"success": true})
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -202,7 +255,7 @@ func (r *WorkspaceCliResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	workspaceRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.ID.ValueString()), nil)
 	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -220,6 +273,11 @@ func (r *WorkspaceCliResource) Read(ctx context.Context, req resource.ReadReques
 	if err != nil {
 		tflog.Error(ctx, "Error reading workspace cli resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, workspaceResponse, bodyResponse, "workspace cli") {
+		return
+	}
+
 	workspace := &client.WorkspaceEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -230,6 +288,12 @@ func (r *WorkspaceCliResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	if workspace.Deleted {
+		tflog.Warn(ctx, "workspace cli resource is deleted, removing from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
 	state.Name = types.StringValue(workspace.Name)
@@ -238,6 +302,65 @@ func (r *WorkspaceCliResource) Read(ctx context.Context, req resource.ReadReques
 	state.IaCType = types.StringValue(workspace.IaCType)
 	state.IaCVersion = types.StringValue(workspace.IaCVersion)
 	state.ID = types.StringValue(workspace.ID)
+	state.WebUrl = types.StringValue(workspaceWebUrl(r.uiEndpoint, state.OrganizationId.ValueString(), state.ID.ValueString()))
+
+	status := &workspaceStatus{client: r.client, endpoint: r.endpoint, token: r.token, fetch: r.fetchWorkspaceStatus}
+	var statusDiags diag.Diagnostics
+	state.CurrentStateSerial, state.LastJobDate, statusDiags = status.Read(ctx, state.OrganizationId.ValueString(), workspace)
+	resp.Diagnostics.Append(statusDiags...)
+
+	if state.CreateMissingTags.IsNull() {
+		state.CreateMissingTags = types.BoolValue(false)
+	}
+	if state.ExclusiveTags.IsNull() {
+		state.ExclusiveTags = types.BoolValue(false)
+	}
+	if state.ExclusiveEnvironment.IsNull() {
+		state.ExclusiveEnvironment = types.BoolValue(false)
+	}
+	if state.ExclusiveAccess.IsNull() {
+		state.ExclusiveAccess = types.BoolValue(false)
+	}
+
+	if state.DestroyProtection.IsNull() {
+		state.DestroyProtection = types.StringValue(destroyProtectionNone)
+	}
+
+	tags := &workspaceTags{client: r.client, endpoint: r.endpoint, token: r.token}
+	managedTags, tagDiags := tags.ManagedNames(ctx, req.Private, state.TagNames)
+	resp.Diagnostics.Append(tagDiags...)
+	if !state.TagNames.IsNull() || len(managedTags) > 0 || state.ExclusiveTags.ValueBool() {
+		tagNames, tagDiags := tags.Names(ctx, state.OrganizationId.ValueString(), state.ID.ValueString(), state.ExclusiveTags.ValueBool(), managedTags)
+		resp.Diagnostics.Append(tagDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.TagNames = tagNames
+	}
+
+	environment := &workspaceEnvironment{client: r.client, endpoint: r.endpoint, token: r.token, variables: r.variables}
+	managedKeys, _, environmentDiags := workspaceEnvironmentKeys.Read(ctx, req.Private)
+	resp.Diagnostics.Append(environmentDiags...)
+	if !state.Environment.IsNull() || len(managedKeys) > 0 || state.ExclusiveEnvironment.ValueBool() {
+		environmentValues, environmentDiags := environment.Read(ctx, state.OrganizationId.ValueString(), state.ID.ValueString(), state.ExclusiveEnvironment.ValueBool(), managedKeys)
+		resp.Diagnostics.Append(environmentDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.Environment = environmentValues
+	}
+
+	access := &workspaceAccess{client: r.client, endpoint: r.endpoint, token: r.token}
+	managedTeams, _, accessDiags := workspaceAccessTeams.Read(ctx, req.Private)
+	resp.Diagnostics.Append(accessDiags...)
+	if !state.Access.IsNull() || len(managedTeams) > 0 || state.ExclusiveAccess.ValueBool() {
+		accessValues, accessDiags := access.Read(ctx, state.OrganizationId.ValueString(), state.ID.ValueString(), state.Access, state.ExclusiveAccess.ValueBool(), managedTeams)
+		resp.Diagnostics.Append(accessDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.Access = accessValues
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -278,7 +401,7 @@ func (r *WorkspaceCliResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
-	organizationRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	organizationRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.ID.ValueString()), strings.NewReader(out.String()))
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -299,7 +422,7 @@ func (r *WorkspaceCliResource) Update(ctx context.Context, req resource.UpdateRe
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	organizationRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	organizationRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.ID.ValueString()), nil)
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -329,12 +452,43 @@ func (r *WorkspaceCliResource) Update(ctx context.Context, req resource.UpdateRe
 	}
 
 	plan.ID = types.StringValue(state.ID.ValueString())
+	plan.WebUrl = types.StringValue(workspaceWebUrl(r.uiEndpoint, plan.OrganizationId.ValueString(), plan.ID.ValueString()))
+
+	// The status keeps the values planned from the state, the next refresh updates them
+	if plan.CurrentStateSerial.IsUnknown() || plan.LastJobDate.IsUnknown() {
+		status := &workspaceStatus{client: r.client, endpoint: r.endpoint, token: r.token, fetch: r.fetchWorkspaceStatus}
+		var statusDiags diag.Diagnostics
+		plan.CurrentStateSerial, plan.LastJobDate, statusDiags = status.Read(ctx, plan.OrganizationId.ValueString(), workspace)
+		resp.Diagnostics.Append(statusDiags...)
+	}
+
 	plan.Name = types.StringValue(workspace.Name)
 	plan.Description = types.StringValue(workspace.Description)
 	plan.IaCType = types.StringValue(workspace.IaCType)
 	plan.IaCVersion = types.StringValue(workspace.IaCVersion)
 	plan.ExecutionMode = types.StringValue(workspace.ExecutionMode)
 
+	tags := &workspaceTags{client: r.client, endpoint: r.endpoint, token: r.token}
+	managedTags, tagDiags := tags.ManagedNames(ctx, req.Private, state.TagNames)
+	resp.Diagnostics.Append(tagDiags...)
+	if !tagDiags.HasError() {
+		resp.Diagnostics.Append(tags.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.TagNames, plan.CreateMissingTags.ValueBool(), plan.ExclusiveTags.ValueBool(), managedTags, resp.Private)...)
+	}
+
+	environment := &workspaceEnvironment{client: r.client, endpoint: r.endpoint, token: r.token, variables: r.variables}
+	managedKeys, _, environmentDiags := workspaceEnvironmentKeys.Read(ctx, req.Private)
+	resp.Diagnostics.Append(environmentDiags...)
+	if !environmentDiags.HasError() {
+		resp.Diagnostics.Append(environment.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.Environment, plan.ExclusiveEnvironment.ValueBool(), managedKeys, resp.Private)...)
+	}
+
+	access := &workspaceAccess{client: r.client, endpoint: r.endpoint, token: r.token}
+	managedTeams, _, accessDiags := workspaceAccessTeams.Read(ctx, req.Private)
+	resp.Diagnostics.Append(accessDiags...)
+	if !accessDiags.HasError() {
+		resp.Diagnostics.Append(access.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.Access, plan.ExclusiveAccess.ValueBool(), managedTeams, resp.Private)...)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -348,62 +502,31 @@ func (r *WorkspaceCliResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
-	var chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
-
-	ll := len(chars)
-	b := make([]byte, 4)
-
-	if _, err := rand.Read(b); err != nil {
-		resp.Diagnostics.AddError("Error generating random string to delete workspace", fmt.Sprintf("Error generating random string to delete workspace: %s", err))
+	deleteWorkspace, diags := checkDestroyProtection(ctx, data.DestroyProtection, data.ID.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if !deleteWorkspace {
 		return
 	}
 
-	for i := 0; i < 4; i++ {
-		b[i] = chars[int(b[i])%ll]
-	}
-
-	tflog.Info(ctx, "Send patch request to mark as deleted...")
-	tflog.Info(ctx, fmt.Sprintf("%s_DEL_%s", data.Name.ValueString(), string(b)))
-
 	bodyRequest := &client.WorkspaceEntity{
 		ID:            data.ID.ValueString(),
-		Name:          fmt.Sprintf("%s_DEL_%s", data.Name.ValueString(), string(b)), // FORCE A NAME CHANGE WITH THE SAME LOGIC THAT IN THE UI
+		Name:          data.Name.ValueString(),
 		Description:   data.Description.ValueString(),
 		Source:        "empty",
 		Branch:        "remote-content",
 		IaCType:       data.IaCType.ValueString(),
 		IaCVersion:    data.IaCVersion.ValueString(),
 		ExecutionMode: data.ExecutionMode.ValueString(),
-		Deleted:       true,
 	}
 
-	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
-
-	tflog.Info(ctx, "Request Body...")
-	tflog.Info(ctx, out.String())
-
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
+	if err := softDeleteWorkspace(ctx, r.client, r.endpoint, r.token, data.OrganizationId.ValueString(), bodyRequest); err != nil {
+		resp.Diagnostics.AddError("Error deleting workspace cli resource", fmt.Sprintf("Error deleting workspace cli resource: %s", err))
 		return
 	}
+}
 
-	workspaceCliRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), strings.NewReader(out.String()))
-	workspaceCliRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	workspaceCliRequest.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating cli resource request", fmt.Sprintf("Error creating cli resource request: %s", err))
-		return
-	}
-
-	workspaceCliResponse, err := r.client.Do(workspaceCliRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing cli resource request", fmt.Sprintf("Error executing cli resource request: %s", err))
-		return
-	}
-
-	tflog.Info(ctx, "Delete response code: "+strconv.Itoa(workspaceCliResponse.StatusCode))
-
+func (r *WorkspaceCliResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
 }
 
 func (r *WorkspaceCliResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// softDeleteWorkspace marks the workspace as deleted. Terrakube keeps deleted
+// workspaces and their names, so the name also gets a random _DEL_ suffix like
+// the UI does, otherwise a workspace with the same name can't be created again.
+// The name is never used to detect deleted workspaces, the deleted attribute is.
+func softDeleteWorkspace(ctx context.Context, httpClient *http.Client, endpoint string, token string, organizationId string, workspace *client.WorkspaceEntity) error {
+	var chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+
+	ll := len(chars)
+	b := make([]byte, 4)
+
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("error generating random string to delete workspace: %w", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		b[i] = chars[int(b[i])%ll]
+	}
+
+	workspace.Name = fmt.Sprintf("%s_DEL_%s", workspace.Name, string(b))
+	workspace.Deleted = true
+
+	tflog.Info(ctx, "Send patch request to mark as deleted...")
+	tflog.Info(ctx, workspace.Name)
+
+	var out = new(bytes.Buffer)
+	if err := jsonapi.MarshalPayload(out, workspace); err != nil {
+		return fmt.Errorf("unable to marshal payload: %w", err)
+	}
+
+	tflog.Debug(ctx, "Request Body...")
+	tflog.Debug(ctx, helpers.RedactPayload(out.String()))
+
+	workspaceRequest, err := http.NewRequest(http.MethodPatch, apiURL(endpoint, "organization", organizationId, "workspace", workspace.ID), out)
+	if err != nil {
+		return fmt.Errorf("error creating workspace request: %w", err)
+	}
+	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	workspaceRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+	workspaceResponse, err := httpClient.Do(workspaceRequest)
+	if err != nil {
+		return fmt.Errorf("error executing workspace request: %w", err)
+	}
+	defer workspaceResponse.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(workspaceResponse.Body)
+	if err = checkDeleteResponse(workspaceResponse, bodyResponse); err != nil {
+		return err
+	}
+
+	tflog.Info(ctx, "Delete response code: "+strconv.Itoa(workspaceResponse.StatusCode))
+
+	return nil
+}
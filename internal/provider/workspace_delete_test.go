@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"testing"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func TestWorkspaceCliReadDeleted(t *testing.T) {
+	tests := []struct {
+		name        string
+		workspace   string
+		deleted     bool
+		wantRemoved bool
+	}{
+		{name: "active", workspace: "production"},
+		{name: "deleted", workspace: "production_DEL_a1B2", deleted: true, wantRemoved: true},
+		{name: "deleted suffix in the name", workspace: "x_DEL_prod"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/workspace/workspace") {
+					_ = jsonapi.MarshalPayload(w, &client.WorkspaceEntity{ID: "workspace", Name: test.workspace, Deleted: test.deleted, IaCType: "terraform", IaCVersion: "1.5.7", ExecutionMode: "remote"})
+					return
+				}
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			}))
+			defer server.Close()
+
+			r := NewWorkspaceCliResource()
+			configureTestResource(t, r, server)
+
+			state := newTestState(t, r, map[string]string{"id": "workspace", "organization_id": "org", "name": test.workspace})
+			resp := resource.ReadResponse{State: state}
+			r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Read diagnostics: %v", resp.Diagnostics)
+			}
+			if removed := resp.State.Raw.IsNull(); removed != test.wantRemoved {
+				t.Errorf("Read removed the workspace = %t, want %t", removed, test.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestSoftDeleteWorkspace(t *testing.T) {
+	var sent client.WorkspaceEntity
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/v1/organization/org/workspace/workspace" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := jsonapi.UnmarshalPayload(strings.NewReader(string(body)), &sent); err != nil {
+			t.Errorf("unable to unmarshal request: %s", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := softDeleteWorkspace(context.Background(), server.Client(), server.URL, "test-token", "org", &client.WorkspaceEntity{ID: "workspace", Name: "production"})
+	if err != nil {
+		t.Fatalf("softDeleteWorkspace returned %v", err)
+	}
+
+	if !sent.Deleted {
+		t.Error("softDeleteWorkspace didn't set deleted")
+	}
+	if !regexp.MustCompile(`^production_DEL_[a-zA-Z0-9]{4}$`).MatchString(sent.Name) {
+		t.Errorf("softDeleteWorkspace renamed the workspace to %q, want production_DEL_ and 4 random characters", sent.Name)
+	}
+}
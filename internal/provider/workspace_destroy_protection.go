@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	destroyProtectionNone  = "none"
+	destroyProtectionSoft  = "soft"
+	destroyProtectionError = "error"
+)
+
+// destroyProtectionAttribute returns the destroy_protection attribute shared by the workspace resources.
+func destroyProtectionAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional: true,
+		Computed: true,
+		Default:  stringdefault.StaticString(destroyProtectionNone),
+		Description: "Behavior when the workspace is destroyed. `none` deletes the workspace, `soft` only removes it from the Terraform state " +
+			"and keeps it in Terrakube, `error` fails the destroy until the value is changed back to `none`. Default is `none`.",
+		Validators: []validator.String{
+			stringvalidator.OneOf(destroyProtectionNone, destroyProtectionSoft, destroyProtectionError),
+		},
+	}
+}
+
+// checkDestroyProtection returns true when the workspace must be deleted in Terrakube.
+// With soft protection the workspace is only removed from state, with error
+// protection an error diagnostic is returned and the workspace is kept in state.
+func checkDestroyProtection(ctx context.Context, destroyProtection types.String, workspaceId string) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	switch destroyProtection.ValueString() {
+	case destroyProtectionSoft:
+		tflog.Warn(ctx, fmt.Sprintf("Workspace %s has soft destroy protection, removing it from state without deleting it", workspaceId))
+		return false, diags
+	case destroyProtectionError:
+		diags.AddError(
+			"Workspace is protected",
+			fmt.Sprintf("Workspace %s has destroy_protection set to \"error\". Set destroy_protection to \"none\" and apply before destroying it, or to \"soft\" to only remove it from the state.", workspaceId),
+		)
+		return false, diags
+	}
+
+	return true, diags
+}
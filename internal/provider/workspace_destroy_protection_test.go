@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func TestWorkspaceDestroyProtection(t *testing.T) {
+	tests := []struct {
+		protection   string
+		wantRequests bool
+		wantErr      bool
+	}{
+		{protection: destroyProtectionNone, wantRequests: true},
+		{protection: destroyProtectionSoft},
+		{protection: destroyProtectionError, wantErr: true},
+	}
+
+	resources := []struct {
+		name        string
+		newResource func() resource.Resource
+	}{
+		{name: "workspace_cli", newResource: NewWorkspaceCliResource},
+		{name: "workspace_vcs", newResource: NewWorkspaceVcsResource},
+	}
+
+	for _, workspace := range resources {
+		for _, test := range tests {
+			t.Run(workspace.name+"/"+test.protection, func(t *testing.T) {
+				requests := 0
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests++
+					w.WriteHeader(http.StatusNoContent)
+				}))
+				defer server.Close()
+
+				r := workspace.newResource()
+				configureTestResource(t, r, server)
+
+				state := newTestState(t, r, map[string]string{"id": "workspace", "organization_id": "org", "name": "production"})
+				if diags := state.SetAttribute(context.Background(), path.Root("destroy_protection"), test.protection); diags.HasError() {
+					t.Fatalf("unable to set destroy_protection: %v", diags)
+				}
+
+				resp := resource.DeleteResponse{State: state}
+				r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+
+				if resp.Diagnostics.HasError() != test.wantErr {
+					t.Errorf("Delete diagnostics = %v, want error %t", resp.Diagnostics, test.wantErr)
+				}
+				if (requests > 0) != test.wantRequests {
+					t.Errorf("Delete sent %d requests, want requests %t", requests, test.wantRequests)
+				}
+			})
+		}
+	}
+}
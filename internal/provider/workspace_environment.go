@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// workspaceEnvironmentKeys are the keys of the ENV variables managed by the
+// environment attribute.
+var workspaceEnvironmentKeys = managedKeys{privateKey: "environment_keys"}
+
+// workspaceEnvironmentAttributes returns the environment and
+// exclusive_environment attributes shared by the workspace resources.
+func workspaceEnvironmentAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"environment": schema.MapAttribute{
+			Optional:    true,
+			ElementType: types.StringType,
+			Description: "ENV variables of the workspace, for example `TF_LOG` or `TF_CLI_ARGS_plan`. Only the keys set here are managed, other variables of the workspace, for example created with `terrakube_workspace_variable`, are left untouched unless `exclusive_environment` is `true`. A key removed from the map deletes its variable. A key used by an ENV variable created outside `environment` is an error, unless `exclusive_environment` is `true`. Values are not sensitive.",
+		},
+		"exclusive_environment": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "Manage every ENV variable of the workspace with `environment`, ENV variables missing from the map are deleted. Default is `false`.",
+		},
+	}
+}
+
+// workspaceEnvironment reconciles the ENV variables of a workspace with the
+// environment attribute.
+type workspaceEnvironment struct {
+	client    *http.Client
+	endpoint  string
+	token     string
+	variables *workspaceVariableCache
+}
+
+// environmentVariables returns the ENV variables of the workspace by key.
+func (w *workspaceEnvironment) environmentVariables(organizationId string, workspaceId string) (map[string]*client.WorkspaceVariableEntity, error) {
+	variables, err := listWorkspaceVariables(w.client, w.endpoint, w.token, organizationId, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := map[string]*client.WorkspaceVariableEntity{}
+	for _, variable := range variables {
+		if variable.Category == "ENV" {
+			byKey[variable.Key] = variable
+		}
+	}
+
+	return byKey, nil
+}
+
+func (w *workspaceEnvironment) do(method string, url string, body interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		var out = new(bytes.Buffer)
+		if err := jsonapi.MarshalPayload(out, body); err != nil {
+			return fmt.Errorf("unable to marshal payload: %w", err)
+		}
+		reader = out
+	}
+
+	request, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", w.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if method == http.MethodDelete {
+		return checkDeleteResponse(response, bodyResponse)
+	}
+
+	return client.CheckResponse(response, bodyResponse)
+}
+
+// Sync makes the ENV variables of the workspace match the environment map,
+// see reconcileKeys. managed are the keys set by a previous Sync, they are
+// written back to the private state with the keys set now.
+func (w *workspaceEnvironment) Sync(ctx context.Context, organizationId string, workspaceId string, environment types.Map, exclusive bool, managed []string, private privateStateWriter) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	values := map[string]string{}
+	if !environment.IsNull() {
+		diags.Append(environment.ElementsAs(ctx, &values, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	if len(values) == 0 && len(managed) == 0 && !exclusive {
+		return diags
+	}
+
+	defer w.variables.Invalidate(organizationId, workspaceId)
+
+	existing, err := w.environmentVariables(organizationId, workspaceId)
+	if err != nil {
+		diags.AddError("Error reading workspace variables", err.Error())
+		return diags
+	}
+
+	desiredKeys := make([]string, 0, len(values))
+	for key := range values {
+		desiredKeys = append(desiredKeys, key)
+	}
+	existingKeys := make([]string, 0, len(existing))
+	for key := range existing {
+		existingKeys = append(existingKeys, key)
+	}
+	reconciliation := reconcileKeys(desiredKeys, existingKeys, managed, exclusive)
+
+	for _, key := range reconciliation.Conflict {
+		diags.AddError("Workspace variable already exists", fmt.Sprintf("ENV variable %s of workspace %s is not managed by environment, remove it from environment, delete the variable %s first or set exclusive_environment = true.", key, workspaceId, existing[key].ID))
+	}
+	if len(reconciliation.Conflict) > 0 {
+		return diags
+	}
+
+	isManaged := map[string]bool{}
+	for _, key := range managed {
+		isManaged[key] = true
+	}
+
+	variablesURL := apiURL(w.endpoint, "organization", url.PathEscape(organizationId), "workspace", url.PathEscape(workspaceId), "variable")
+	keys := []string{}
+
+	for _, key := range reconciliation.Create {
+		if err := w.do(http.MethodPost, variablesURL, &client.WorkspaceVariableEntity{Key: key, Value: values[key], Category: "ENV"}); err != nil {
+			diags.AddError("Error setting workspace variable", fmt.Sprintf("Error setting ENV variable %s of workspace %s: %s", key, workspaceId, err))
+			if isManaged[key] {
+				keys = append(keys, key)
+			}
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	for _, key := range reconciliation.Update {
+		// A sensitive or HCL variable is set back to a plain value
+		keys = append(keys, key)
+		variable, value := existing[key], values[key]
+		if variable.Value == value && !variable.Sensitive && !variable.Hcl {
+			continue
+		}
+		if err := w.do(http.MethodPatch, endpointURL(variablesURL, url.PathEscape(variable.ID)), &client.WorkspaceVariableEntity{ID: variable.ID, Key: key, Value: value, Description: variable.Description, Category: "ENV"}); err != nil {
+			diags.AddError("Error setting workspace variable", fmt.Sprintf("Error setting ENV variable %s of workspace %s: %s", key, workspaceId, err))
+		}
+	}
+
+	for _, key := range reconciliation.Delete {
+		if err := w.do(http.MethodDelete, endpointURL(variablesURL, url.PathEscape(existing[key].ID)), nil); err != nil {
+			diags.AddError("Error deleting workspace variable", fmt.Sprintf("Error deleting ENV variable %s of workspace %s: %s", key, workspaceId, err))
+			if isManaged[key] {
+				keys = append(keys, key)
+			}
+			continue
+		}
+		tflog.Info(ctx, "Workspace environment variable removed", map[string]any{"key": key})
+	}
+
+	// Managed keys that failed are kept so the next apply retries them
+	diags.Append(workspaceEnvironmentKeys.Write(ctx, private, keys)...)
+
+	return diags
+}
+
+// Read returns the values of the owned ENV variables of the workspace, see ownedKeys.
+func (w *workspaceEnvironment) Read(ctx context.Context, organizationId string, workspaceId string, exclusive bool, managed []string) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	existing, err := w.environmentVariables(organizationId, workspaceId)
+	if err != nil {
+		diags.AddError("Error reading workspace variables", err.Error())
+		return types.MapNull(types.StringType), diags
+	}
+
+	existingKeys := make([]string, 0, len(existing))
+	for key := range existing {
+		existingKeys = append(existingKeys, key)
+	}
+
+	values := map[string]string{}
+	for _, key := range ownedKeys(existingKeys, managed, exclusive) {
+		values[key] = existing[key].Value
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, values)
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// workspaceIdPathPattern finds the workspace id in a Terrakube UI URL, an API
+// selflink or a path like org/<uuid>/workspace/<uuid>.
+var workspaceIdPathPattern = regexp.MustCompile(`(?i)(?:^|/)workspaces?/([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})(?:[/?#]|$)`)
+
+// workspaceIdValidator rejects a workspace URL or path used as workspace_id.
+// The configured value can't be replaced at plan time, so the error quotes
+// the workspace id to use instead. Any other value is left untouched.
+func workspaceIdValidator() validator.String {
+	return workspaceIdFormatValidator{}
+}
+
+type workspaceIdFormatValidator struct{}
+
+func (v workspaceIdFormatValidator) Description(_ context.Context) string {
+	return "value must be a workspace id, not a workspace URL or path"
+}
+
+func (v workspaceIdFormatValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v workspaceIdFormatValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if !strings.Contains(value, "/") {
+		return
+	}
+
+	if match := workspaceIdPathPattern.FindStringSubmatch(value); match != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Workspace URL used as workspace id",
+			fmt.Sprintf("%q is a workspace URL or path, set %s to the workspace id %q instead.", value, req.Path, match[1]),
+		)
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid workspace id",
+		fmt.Sprintf("%q looks like a URL or path, %s must be the workspace id, for example 00000000-0000-0000-0000-000000000000.", value, req.Path),
+	)
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestWorkspaceIdValidator(t *testing.T) {
+	const workspaceId = "5c1f6c2a-3d4e-4f5a-8b9c-0d1e2f3a4b5c"
+
+	tests := []struct {
+		name       string
+		value      types.String
+		wantErr    bool
+		wantDetail string
+	}{
+		{name: "ui url", value: types.StringValue("https://terrakube-ui.example.com/organizations/0b7e0d2e-8e4b-4a52-9c3a-4a6f2c1d9e10/workspaces/" + workspaceId), wantErr: true, wantDetail: workspaceId},
+		{name: "ui url with tab", value: types.StringValue("https://terrakube-ui.example.com/organizations/0b7e0d2e-8e4b-4a52-9c3a-4a6f2c1d9e10/workspaces/" + workspaceId + "/runs?page=2"), wantErr: true, wantDetail: workspaceId},
+		{name: "api selflink", value: types.StringValue("https://terrakube-api.example.com/api/v1/organization/0b7e0d2e-8e4b-4a52-9c3a-4a6f2c1d9e10/workspace/" + strings.ToUpper(workspaceId)), wantErr: true, wantDetail: strings.ToUpper(workspaceId)},
+		{name: "organization path", value: types.StringValue("organization/0b7e0d2e-8e4b-4a52-9c3a-4a6f2c1d9e10/workspace/" + workspaceId), wantErr: true, wantDetail: workspaceId},
+		{name: "non uuid path", value: types.StringValue("organization/simple/workspace/networking"), wantErr: true, wantDetail: "looks like a URL or path"},
+		{name: "uuid", value: types.StringValue(workspaceId)},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := validator.StringResponse{}
+			workspaceIdValidator().ValidateString(context.Background(), validator.StringRequest{Path: path.Root("workspace_id"), ConfigValue: test.value}, &resp)
+
+			if resp.Diagnostics.HasError() != test.wantErr {
+				t.Fatalf("workspaceIdValidator(%s) diagnostics = %v, want error %t", test.value, resp.Diagnostics, test.wantErr)
+			}
+			if test.wantErr && !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), test.wantDetail) {
+				t.Errorf("error detail %q doesn't contain %q", resp.Diagnostics.Errors()[0].Detail(), test.wantDetail)
+			}
+		})
+	}
+}
+
+func TestWorkspaceIdPathPattern(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "/workspaces/5c1f6c2a-3d4e-4f5a-8b9c-0d1e2f3a4b5c", want: "5c1f6c2a-3d4e-4f5a-8b9c-0d1e2f3a4b5c"},
+		{value: "workspace/5c1f6c2a-3d4e-4f5a-8b9c-0d1e2f3a4b5c#settings", want: "5c1f6c2a-3d4e-4f5a-8b9c-0d1e2f3a4b5c"},
+		{value: "myworkspace/5c1f6c2a-3d4e-4f5a-8b9c-0d1e2f3a4b5c"},
+		{value: "workspace/5c1f6c2a-3d4e-4f5a-8b9c-0d1e2f3a4b5cd"},
+		{value: "workspace/networking"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got := ""
+			if match := workspaceIdPathPattern.FindStringSubmatch(test.value); match != nil {
+				got = match[1]
+			}
+			if got != test.want {
+				t.Errorf("workspace id of %q = %q, want %q", test.value, got, test.want)
+			}
+		})
+	}
+}
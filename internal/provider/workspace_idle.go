@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
+	"time"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// workspaceIdlePollInterval is the delay between two checks of the workspace jobs.
+const workspaceIdlePollInterval = 10 * time.Second
+
+// activeJobStatuses are the job statuses of a job that has not finished yet.
+var activeJobStatuses = []string{"pending", "waitingApproval", "approved", "queued", "running"}
+
+// workspaceIdleWaitAttribute returns the update_wait_for_idle_minutes attribute.
+func workspaceIdleWaitAttribute() schema.Int64Attribute {
+	return schema.Int64Attribute{
+		Optional: true,
+		Description: "Minutes to wait for running jobs of the workspace to finish before changing `working_directory` or `iac_version`. " +
+			"When omitted or `0` the update fails right away if a job is running.",
+		Validators: []validator.Int64{
+			int64validator.AtLeast(0),
+		},
+	}
+}
+
+// waitForIdleWorkspace waits up to waitMinutes for the workspace to have no
+// running job. An error diagnostic naming the running job is returned when the
+// workspace is still busy after that time.
+func waitForIdleWorkspace(ctx context.Context, httpClient *http.Client, endpoint string, token string, organizationId string, workspaceId string, waitMinutes types.Int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	err := helpers.Poll(ctx, workspaceIdlePollInterval, time.Duration(waitMinutes.ValueInt64())*time.Minute, func(ctx context.Context) (helpers.PollResult, error) {
+		job, err := activeWorkspaceJob(httpClient, endpoint, token, organizationId, workspaceId)
+		if err != nil {
+			return helpers.PollFatal, fmt.Errorf("error reading jobs of workspace %s: %w", workspaceId, err)
+		}
+
+		if job == nil {
+			return helpers.PollDone, nil
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("Waiting for job %s of workspace %s to finish", job.ID, workspaceId), map[string]any{"status": job.Status})
+		return helpers.PollRetry, fmt.Errorf("job %s of workspace %s is %s", job.ID, workspaceId, job.Status)
+	})
+
+	switch {
+	case errors.Is(err, helpers.ErrPollTimeout):
+		diags.AddError(
+			"Workspace has a running job",
+			fmt.Sprintf("working_directory and iac_version can't be changed until the job finishes, set update_wait_for_idle_minutes to wait for it: %s", err),
+		)
+	case err != nil:
+		diags.AddError("Error reading workspace jobs", err.Error())
+	}
+
+	return diags
+}
+
+// activeWorkspaceJob returns a job of the workspace that has not finished, or nil when there is none.
+func activeWorkspaceJob(httpClient *http.Client, endpoint string, token string, organizationId string, workspaceId string) (*client.JobEntity, error) {
+	filter := fmt.Sprintf("workspace.id==%s;status=in=(%s)", workspaceId, strings.Join(activeJobStatuses, ","))
+	request, err := http.NewRequest(http.MethodGet, apiURL(endpoint, "organization", organizationId, "job")+fmt.Sprintf("?filter[job]=%s&page[size]=1", filter), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating job request: %w", err)
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error executing job request: %w", err)
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading job response body: %w", err)
+	}
+
+	if err = client.CheckResponse(response, bodyResponse); err != nil {
+		return nil, err
+	}
+
+	jobs, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), reflect.TypeOf(new(client.JobEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal jobs: %w", err)
+	}
+
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	return jobs[0].(*client.JobEntity), nil
+}
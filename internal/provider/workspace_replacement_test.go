@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// workspaceTestConfiguration creates the organization and template the
+// configuration of the replacement tests refers to.
+func workspaceTestConfiguration(fake *fakeTerrakube) {
+	fake.add("organization/org", fakeObject{Type: "organization", ID: "org", Attributes: map[string]any{"name": "org", "executionMode": "remote"}})
+	fake.add("organization/org/template/tpl", fakeObject{Type: "template", ID: "tpl", Attributes: map[string]any{"name": "apply"}})
+}
+
+func workspaceVcsTestConfig(s *testProviderServer) tftypes.Value {
+	accessType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"team_name": tftypes.String, "manage_state": tftypes.Bool, "manage_job": tftypes.Bool, "manage_workspace": tftypes.Bool}}
+
+	return s.config("terrakube_workspace_vcs", map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "org"),
+		"name":            tftypes.NewValue(tftypes.String, "simple"),
+		"repository":      tftypes.NewValue(tftypes.String, "https://github.com/AzBuilder/terrakube-docker-compose.git"),
+		"iac_version":     tftypes.NewValue(tftypes.String, "1.5.7"),
+		"template_id":     tftypes.NewValue(tftypes.String, "tpl"),
+		"access": tftypes.NewValue(tftypes.List{ElementType: accessType}, []tftypes.Value{
+			tftypes.NewValue(accessType, map[string]tftypes.Value{
+				"team_name":        tftypes.NewValue(tftypes.String, "DEVELOPERS"),
+				"manage_state":     tftypes.NewValue(tftypes.Bool, true),
+				"manage_job":       tftypes.NewValue(tftypes.Bool, true),
+				"manage_workspace": tftypes.NewValue(tftypes.Bool, false),
+			}),
+		}),
+	})
+}
+
+// workspaceChildTestConfigs returns the configuration of the variables and
+// webhook of the workspace, workspaceId is unknown while the workspace is
+// being replaced.
+func workspaceChildTestConfigs(s *testProviderServer, workspaceId tftypes.Value) map[string]tftypes.Value {
+	configs := map[string]tftypes.Value{}
+	for _, key := range []string{"region", "zone", "size"} {
+		configs["terrakube_workspace_variable."+key] = s.config("terrakube_workspace_variable", map[string]tftypes.Value{
+			"organization_id": tftypes.NewValue(tftypes.String, "org"),
+			"workspace_id":    workspaceId,
+			"key":             tftypes.NewValue(tftypes.String, key),
+			"value":           tftypes.NewValue(tftypes.String, key+"-value"),
+			"description":     tftypes.NewValue(tftypes.String, key),
+			"category":        tftypes.NewValue(tftypes.String, "TERRAFORM"),
+			"sensitive":       tftypes.NewValue(tftypes.Bool, false),
+			"hcl":             tftypes.NewValue(tftypes.Bool, false),
+		})
+	}
+	configs["terrakube_workspace_webhook.push"] = s.config("terrakube_workspace_webhook", map[string]tftypes.Value{
+		"organization_id": tftypes.NewValue(tftypes.String, "org"),
+		"workspace_id":    workspaceId,
+		"template_id":     tftypes.NewValue(tftypes.String, "tpl"),
+		"branch":          tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "main")}),
+	})
+
+	return configs
+}
+
+// Replacing a workspace replaces its variables and webhook: Terraform deletes
+// them before the workspace, which only succeeds while the workspace exists,
+// and creates them again under the new workspace.
+func TestWorkspaceReplacementOrdering(t *testing.T) {
+	fake := newFakeTerrakube(t)
+	workspaceTestConfiguration(fake)
+	s := newTestProviderServer(t, fake.URL)
+
+	workspace := s.apply("terrakube_workspace_vcs", nil, workspaceVcsTestConfig(s))
+	oldId := workspace.attribute(t, "id")
+	children := map[string]*testResourceInstance{}
+	for address, config := range workspaceChildTestConfigs(s, tftypes.NewValue(tftypes.String, oldId)) {
+		children[address] = s.apply(strings.Split(address, ".")[0], nil, config)
+	}
+
+	// terraform apply -replace=terrakube_workspace_vcs.workspace, the id of the
+	// workspace is unknown until it is created again.
+	for address, config := range workspaceChildTestConfigs(s, tftypes.NewValue(tftypes.String, tftypes.UnknownValue)) {
+		plan := s.plan(children[address].typeName, children[address], config)
+		if !slices.ContainsFunc(plan.RequiresReplace, func(attributePath *tftypes.AttributePath) bool {
+			return attributePath.Equal(tftypes.NewAttributePath().WithAttributeName("workspace_id"))
+		}) {
+			t.Errorf("%s requires replacement of %v, want workspace_id", address, plan.RequiresReplace)
+		}
+	}
+
+	start := len(fake.log())
+	for _, child := range children {
+		s.destroy(child)
+	}
+	s.destroy(workspace)
+	workspace = s.apply("terrakube_workspace_vcs", nil, workspaceVcsTestConfig(s))
+	newId := workspace.attribute(t, "id")
+	for address, config := range workspaceChildTestConfigs(s, tftypes.NewValue(tftypes.String, newId)) {
+		children[address] = s.apply(strings.Split(address, ".")[0], nil, config)
+	}
+
+	oldWorkspace := "/api/v1/organization/org/workspace/" + oldId
+	workspaceDeleted := false
+	for _, request := range fake.log()[start:] {
+		switch {
+		case request == "PATCH "+oldWorkspace:
+			workspaceDeleted = true
+		case workspaceDeleted && strings.HasPrefix(request, "DELETE "+oldWorkspace+"/"):
+			t.Errorf("%s after the workspace was deleted", request)
+		}
+	}
+	if !workspaceDeleted {
+		t.Errorf("workspace %s was not deleted", oldId)
+	}
+
+	for _, collection := range []string{"variable", "webhook"} {
+		if remaining := fake.list("organization/org/workspace/" + oldId + "/" + collection + "/"); len(remaining) > 0 {
+			t.Errorf("%s objects of the replaced workspace remain: %v", collection, remaining)
+		}
+	}
+	for address, child := range children {
+		if got := child.attribute(t, "workspace_id"); got != newId {
+			t.Errorf("%s workspace_id = %s, want %s", address, got, newId)
+		}
+	}
+	if got := len(fake.list("organization/org/workspace/" + newId + "/variable/")); got != 3 {
+		t.Errorf("%d variables in the new workspace, want 3", got)
+	}
+	if got := len(fake.list("organization/org/workspace/" + newId + "/webhook/")); got != 1 {
+		t.Errorf("%d webhooks in the new workspace, want 1", got)
+	}
+}
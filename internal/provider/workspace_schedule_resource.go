@@ -3,7 +3,6 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -69,6 +69,9 @@ func (r *WorkspaceScheduleResource) Schema(ctx context.Context, req resource.Sch
 			"workspace_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Workspace Id",
+				Validators: []validator.String{
+					workspaceIdValidator(),
+				},
 			},
 		},
 	}
@@ -89,17 +92,7 @@ func (r *WorkspaceScheduleResource) Configure(ctx context.Context, req resource.
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
@@ -129,7 +122,7 @@ func (r *WorkspaceScheduleResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
-	workspaceScheduleRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/workspace/%s/schedule", r.endpoint, plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
+	workspaceScheduleRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "workspace", plan.WorkspaceId.ValueString(), "schedule"), strings.NewReader(out.String()))
 	workspaceScheduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceScheduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -177,7 +170,7 @@ func (r *WorkspaceScheduleResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
-	workspaceScheduleRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/workspace/%s/schedule/%s", r.endpoint, state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	workspaceScheduleRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "workspace", state.WorkspaceId.ValueString(), "schedule", state.ID.ValueString()), nil)
 	workspaceScheduleRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceScheduleRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -195,6 +188,11 @@ func (r *WorkspaceScheduleResource) Read(ctx context.Context, req resource.ReadR
 	if err != nil {
 		tflog.Error(ctx, "Error reading workspace schedule resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, workspaceScheduleResponse, bodyResponse, "workspace schedule") {
+		return
+	}
+
 	workspaceSchedule := &client.WorkspaceScheduleEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -245,7 +243,7 @@ func (r *WorkspaceScheduleResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 
-	workspaceScheduleReq, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/workspace/%s/schedule/%s", r.endpoint, state.WorkspaceId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	workspaceScheduleReq, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "workspace", state.WorkspaceId.ValueString(), "schedule", state.ID.ValueString()), strings.NewReader(out.String()))
 	workspaceScheduleReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceScheduleReq.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -266,7 +264,7 @@ func (r *WorkspaceScheduleResource) Update(ctx context.Context, req resource.Upd
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	workspaceScheduleReq, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/workspace/%s/schedule/%s", r.endpoint, state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	workspaceScheduleReq, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "workspace", state.WorkspaceId.ValueString(), "schedule", state.ID.ValueString()), nil)
 	workspaceScheduleReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceScheduleReq.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -312,18 +310,25 @@ func (r *WorkspaceScheduleResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/workspace/%s/schedule/%s", r.endpoint, data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
+	workspaceRequest, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "workspace", data.WorkspaceId.ValueString(), "schedule", data.ID.ValueString()), nil)
 	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating Workspace schedule resource request", fmt.Sprintf("Error creating schedule schedule resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(workspaceRequest)
+	response, err := r.client.Do(workspaceRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing Workspace schedule resource request", fmt.Sprintf("Error executing Workspace schedule resource request: %s", err))
 		return
 	}
+	defer response.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(response.Body)
+	if err = checkDeleteResponse(response, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing Workspace schedule resource request", fmt.Sprintf("Error executing Workspace schedule resource request: %s", err))
+		return
+	}
 }
 
 func (r *WorkspaceScheduleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
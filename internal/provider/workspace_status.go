@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// workspaceStatusAttributes returns the current_state_serial and last_job_date
+// attributes shared by the workspace resources. They are only computed, a new
+// job or state never plans a change, and an update of the workspace settings
+// keeps the values read last instead of planning them as unknown.
+func workspaceStatusAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"current_state_serial": schema.Int64Attribute{
+			Computed:    true,
+			Description: "Serial of the latest state of the workspace, null when the workspace has no state. Only read when the provider `fetch_workspace_status` is `true`.",
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.UseStateForUnknown(),
+			},
+		},
+		"last_job_date": schema.StringAttribute{
+			Computed:    true,
+			Description: "Date of the last job of the workspace, null when the workspace has no job",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+	}
+}
+
+// workspaceStatus reads the state and job status of a workspace.
+type workspaceStatus struct {
+	client   *http.Client
+	endpoint string
+	token    string
+	// fetch enables the extra request to read the latest state
+	fetch bool
+}
+
+// Read returns the current_state_serial and last_job_date values of the
+// workspace. A failure to read the latest state is only a warning, the
+// workspace itself was read.
+func (w *workspaceStatus) Read(ctx context.Context, organizationId string, workspace *client.WorkspaceEntity) (types.Int64, types.String, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	lastJobDate := types.StringNull()
+	if workspace.LastJobDate != "" {
+		lastJobDate = types.StringValue(workspace.LastJobDate)
+	}
+
+	if !w.fetch {
+		return types.Int64Null(), lastJobDate, diags
+	}
+
+	history, err := w.latestHistory(organizationId, workspace.ID)
+	if err != nil {
+		diags.AddWarning("Unable to read workspace state", fmt.Sprintf("current_state_serial of workspace %s is not set: %s", workspace.ID, err))
+		return types.Int64Null(), lastJobDate, diags
+	}
+
+	if history == nil {
+		return types.Int64Null(), lastJobDate, diags
+	}
+
+	return types.Int64Value(history.Serial), lastJobDate, diags
+}
+
+// latestHistory returns the latest state of the workspace, or nil when it has none.
+func (w *workspaceStatus) latestHistory(organizationId string, workspaceId string) (*client.HistoryEntity, error) {
+	request, err := http.NewRequest(http.MethodGet, apiURL(w.endpoint, "organization", organizationId, "workspace", workspaceId, "history")+"?sort=-createdDate&page[size]=1", nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", w.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.CheckResponse(response, bodyResponse); err != nil {
+		return nil, err
+	}
+
+	histories, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), reflect.TypeOf(new(client.HistoryEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal workspace history: %w", err)
+	}
+
+	if len(histories) == 0 {
+		return nil, nil
+	}
+
+	return histories[0].(*client.HistoryEntity), nil
+}
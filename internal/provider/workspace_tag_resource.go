@@ -3,10 +3,10 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -23,11 +24,13 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceTagResource{}
 var _ resource.ResourceWithImportState = &WorkspaceTagResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceTagResource{}
 
 type WorkspaceTagResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	defaultOrganizationId string
 }
 
 type WorkspaceTagResourceModel struct {
@@ -47,7 +50,7 @@ func (r *WorkspaceTagResource) Metadata(ctx context.Context, req resource.Metada
 
 func (r *WorkspaceTagResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Adds a tag to a workspace resource.",
+		MarkdownDescription: "Adds a tag to a workspace resource. It can be used together with the `tag_names` attribute of the workspace for other tags, unless `exclusive_tags` is `true` on the workspace.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -61,13 +64,13 @@ func (r *WorkspaceTagResource) Schema(ctx context.Context, req resource.SchemaRe
 				Required:    true,
 				Description: "Tag Id",
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"workspace_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Terrakube workspace id",
+				Validators: []validator.String{
+					workspaceIdValidator(),
+				},
 			},
 		},
 	}
@@ -88,20 +91,11 @@ func (r *WorkspaceTagResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
 
 	tflog.Debug(ctx, "Configuring Workspace Tag resource", map[string]any{"success": true})
 }
@@ -127,7 +121,7 @@ func (r *WorkspaceTagResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	workspaceTagRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/workspaceTag", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
+	workspaceTagRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", url.PathEscape(plan.OrganizationId.ValueString()), "workspace", url.PathEscape(plan.WorkspaceId.ValueString()), "workspaceTag"), strings.NewReader(out.String()))
 	workspaceTagRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceTagRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -176,7 +170,7 @@ func (r *WorkspaceTagResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	workspaceTagRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/workspaceTag/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	workspaceTagRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "workspace", url.PathEscape(state.WorkspaceId.ValueString()), "workspaceTag", url.PathEscape(state.ID.ValueString())), nil)
 	workspaceTagRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceTagRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -194,6 +188,11 @@ func (r *WorkspaceTagResource) Read(ctx context.Context, req resource.ReadReques
 	if err != nil {
 		tflog.Error(ctx, "Error reading workspace tag resource response")
 	}
+
+	if !checkReadResponse(ctx, resp, workspaceTagResponse, bodyResponse, "workspace tag") {
+		return
+	}
+
 	workspaceTag := &client.WorkspaceTagEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -229,18 +228,29 @@ func (r *WorkspaceTagResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
-	reqOrg, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/workspaceTag/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.TagID.ValueString()), nil)
+	reqOrg, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", url.PathEscape(data.OrganizationId.ValueString()), "workspace", url.PathEscape(data.WorkspaceId.ValueString()), "workspaceTag", url.PathEscape(data.TagID.ValueString())), nil)
 	reqOrg.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating workspace tag resource request", fmt.Sprintf("Error creating workspace tag resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(reqOrg)
+	response, err := r.client.Do(reqOrg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing workspace tag resource request", fmt.Sprintf("Error executing workspace tag resource request: %s", err))
 		return
 	}
+	defer response.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(response.Body)
+	if err = checkDeleteResponse(response, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error executing workspace tag resource request", fmt.Sprintf("Error executing workspace tag resource request: %s", err))
+		return
+	}
+}
+
+func (r *WorkspaceTagResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
 }
 
 func (r *WorkspaceTagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -0,0 +1,293 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// workspaceTagNames are the names of the tags attached by the tag_names attribute.
+var workspaceTagNames = managedKeys{privateKey: "tag_names"}
+
+// workspaceTagNamesAttributes returns the tag_names, create_missing_tags and
+// exclusive_tags attributes shared by the workspace resources.
+func workspaceTagNamesAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"tag_names": schema.SetAttribute{
+			Optional:    true,
+			ElementType: types.StringType,
+			Description: "Names of the organization tags attached to the workspace. Only the tags listed here are managed, other tags of the workspace, for example attached with `terrakube_workspace_tag`, are left untouched unless `exclusive_tags` is `true`. A tag removed from the set is detached. A tag already attached outside `tag_names` is an error, unless `exclusive_tags` is `true`.",
+		},
+		"create_missing_tags": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "Create the organization tags listed in `tag_names` that don't exist yet, default is `false`.",
+		},
+		"exclusive_tags": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+			Description: "Manage every tag of the workspace with `tag_names`, tags missing from the set are detached. Default is `false`.",
+		},
+	}
+}
+
+// workspaceTags reconciles the tags attached to a workspace with a set of tag names.
+type workspaceTags struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func (w *workspaceTags) do(method string, url string, body interface{}) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		var out = new(bytes.Buffer)
+		if err := jsonapi.MarshalPayload(out, body); err != nil {
+			return nil, nil, fmt.Errorf("unable to marshal payload: %w", err)
+		}
+		reader = out
+	}
+
+	request, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", w.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, bodyResponse, nil
+}
+
+func (w *workspaceTags) organizationTags(organizationId string) ([]*client.OrganizationTagEntity, error) {
+	response, body, err := w.do(http.MethodGet, apiURL(w.endpoint, "organization", url.PathEscape(organizationId), "tag"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(body)), reflect.TypeOf(new(client.OrganizationTagEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal organization tags, response status: %s, error: %w", response.Status, err)
+	}
+
+	tags := make([]*client.OrganizationTagEntity, 0, len(items))
+	for _, item := range items {
+		tags = append(tags, item.(*client.OrganizationTagEntity))
+	}
+
+	return tags, nil
+}
+
+func (w *workspaceTags) attachedTags(organizationId string, workspaceId string) ([]*client.WorkspaceTagEntity, error) {
+	response, body, err := w.do(http.MethodGet, apiURL(w.endpoint, "organization", url.PathEscape(organizationId), "workspace", url.PathEscape(workspaceId), "workspaceTag"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(body)), reflect.TypeOf(new(client.WorkspaceTagEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal workspace tags, response status: %s, error: %w", response.Status, err)
+	}
+
+	tags := make([]*client.WorkspaceTagEntity, 0, len(items))
+	for _, item := range items {
+		tags = append(tags, item.(*client.WorkspaceTagEntity))
+	}
+
+	return tags, nil
+}
+
+// ManagedNames returns the names of the tags attached by tag_names. Before
+// they were tracked tag_names managed every tag, the tags of current are
+// then the managed ones.
+func (w *workspaceTags) ManagedNames(ctx context.Context, private privateStateReader, current types.Set) ([]string, diag.Diagnostics) {
+	names, tracked, diags := workspaceTagNames.Read(ctx, private)
+	if tracked || diags.HasError() || current.IsNull() || current.IsUnknown() {
+		return names, diags
+	}
+
+	diags.Append(current.ElementsAs(ctx, &names, false)...)
+	return names, diags
+}
+
+// attachedTagsByName returns the tags attached to the workspace by tag name.
+func (w *workspaceTags) attachedTagsByName(organizationId string, workspaceId string, organizationTags []*client.OrganizationTagEntity) (map[string]*client.WorkspaceTagEntity, error) {
+	attached, err := w.attachedTags(organizationId, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	tagNames := map[string]string{}
+	for _, tag := range organizationTags {
+		tagNames[tag.ID] = tag.Name
+	}
+
+	byName := map[string]*client.WorkspaceTagEntity{}
+	for _, workspaceTag := range attached {
+		if name, ok := tagNames[workspaceTag.TagID]; ok {
+			byName[name] = workspaceTag
+		}
+	}
+
+	return byName, nil
+}
+
+// Sync makes the workspace tags match the given tag names, see reconcileKeys.
+// managed are the names attached by a previous Sync, they are written back to
+// the private state with the names attached now.
+func (w *workspaceTags) Sync(ctx context.Context, organizationId string, workspaceId string, tagNames types.Set, createMissing bool, exclusive bool, managed []string, private privateStateWriter) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	names := []string{}
+	if !tagNames.IsNull() {
+		diags.Append(tagNames.ElementsAs(ctx, &names, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	if len(names) == 0 && len(managed) == 0 && !exclusive {
+		return diags
+	}
+
+	organizationTags, err := w.organizationTags(organizationId)
+	if err != nil {
+		diags.AddError("Error reading organization tags", err.Error())
+		return diags
+	}
+
+	tagIds := map[string]string{}
+	for _, tag := range organizationTags {
+		tagIds[tag.Name] = tag.ID
+	}
+
+	attached, err := w.attachedTagsByName(organizationId, workspaceId, organizationTags)
+	if err != nil {
+		diags.AddError("Error reading workspace tags", err.Error())
+		return diags
+	}
+
+	attachedNames := make([]string, 0, len(attached))
+	for name := range attached {
+		attachedNames = append(attachedNames, name)
+	}
+	reconciliation := reconcileKeys(names, attachedNames, managed, exclusive)
+
+	for _, name := range reconciliation.Create {
+		if _, ok := tagIds[name]; !ok && !createMissing {
+			diags.AddError("Tag not found", fmt.Sprintf("Tag %s doesn't exist in organization %s, create it first or set create_missing_tags = true", name, organizationId))
+			return diags
+		}
+	}
+
+	for _, name := range reconciliation.Conflict {
+		diags.AddError("Workspace tag already attached", fmt.Sprintf("Tag %s is attached to workspace %s outside tag_names, remove it from tag_names, detach it first or set exclusive_tags = true.", name, workspaceId))
+	}
+	if len(reconciliation.Conflict) > 0 {
+		return diags
+	}
+
+	isManaged := map[string]bool{}
+	for _, name := range managed {
+		isManaged[name] = true
+	}
+
+	// Tags attached or detached before an error are still tracked
+	kept := append([]string{}, reconciliation.Update...)
+	defer func() {
+		diags.Append(workspaceTagNames.Write(ctx, private, kept)...)
+	}()
+
+	for _, name := range reconciliation.Delete {
+		response, body, err := w.do(http.MethodDelete, apiURL(w.endpoint, "organization", url.PathEscape(organizationId), "workspace", url.PathEscape(workspaceId), "workspaceTag", url.PathEscape(attached[name].ID)), nil)
+		if err == nil {
+			err = checkDeleteResponse(response, body)
+		}
+		if err != nil {
+			diags.AddError("Error removing workspace tag", fmt.Sprintf("Error removing tag %s from workspace %s, error: %s", name, workspaceId, err))
+			if isManaged[name] {
+				kept = append(kept, name)
+			}
+		}
+	}
+
+	for _, name := range reconciliation.Create {
+		if _, ok := tagIds[name]; !ok {
+			response, body, err := w.do(http.MethodPost, apiURL(w.endpoint, "organization", url.PathEscape(organizationId), "tag"), &client.OrganizationTagEntity{Name: name})
+			if err != nil {
+				diags.AddError("Error creating organization tag", err.Error())
+				return diags
+			}
+
+			newTag := &client.OrganizationTagEntity{}
+			if err = jsonapi.UnmarshalPayload(strings.NewReader(string(body)), newTag); err != nil {
+				diags.AddError("Error creating organization tag", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", response.Status, string(body), err))
+				return diags
+			}
+
+			tflog.Info(ctx, "Organization tag created for workspace", map[string]any{"tag": name})
+			tagIds[name] = newTag.ID
+		}
+
+		response, body, err := w.do(http.MethodPost, apiURL(w.endpoint, "organization", url.PathEscape(organizationId), "workspace", url.PathEscape(workspaceId), "workspaceTag"), &client.WorkspaceTagEntity{TagID: tagIds[name]})
+		if err != nil || response.StatusCode != http.StatusCreated {
+			diags.AddError("Error adding workspace tag", fmt.Sprintf("Error adding tag %s to workspace %s, error: %s, response body: %s", name, workspaceId, err, string(body)))
+			if isManaged[name] {
+				kept = append(kept, name)
+			}
+			continue
+		}
+		kept = append(kept, name)
+	}
+
+	return diags
+}
+
+// Names returns the names of the owned tags attached to the workspace, see ownedKeys.
+func (w *workspaceTags) Names(ctx context.Context, organizationId string, workspaceId string, exclusive bool, managed []string) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	organizationTags, err := w.organizationTags(organizationId)
+	if err != nil {
+		diags.AddError("Error reading organization tags", err.Error())
+		return types.SetNull(types.StringType), diags
+	}
+
+	attached, err := w.attachedTagsByName(organizationId, workspaceId, organizationTags)
+	if err != nil {
+		diags.AddError("Error reading workspace tags", err.Error())
+		return types.SetNull(types.StringType), diags
+	}
+
+	attachedNames := make([]string, 0, len(attached))
+	for name := range attached {
+		attachedNames = append(attachedNames, name)
+	}
+
+	return types.SetValueFrom(ctx, types.StringType, ownedKeys(attachedNames, managed, exclusive))
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type testPrivateState map[string][]byte
+
+func (p testPrivateState) SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics {
+	p[key] = value
+	return nil
+}
+
+func (p testPrivateState) GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func TestWorkspaceTagsSyncConflict(t *testing.T) {
+	changes := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/tag"):
+			_, _ = w.Write([]byte(`{"data":[{"type":"tag","id":"tag-shared","attributes":{"name":"shared"}},{"type":"tag","id":"tag-old","attributes":{"name":"old"}}]}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/workspaceTag"):
+			_, _ = w.Write([]byte(`{"data":[{"type":"workspacetag","id":"wt-shared","attributes":{"tagId":"tag-shared"}},{"type":"workspacetag","id":"wt-old","attributes":{"tagId":"tag-old"}}]}`))
+		default:
+			changes = append(changes, r.Method+" "+r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	tags := &workspaceTags{client: server.Client(), endpoint: server.URL, token: "test-token"}
+	ctx := context.Background()
+
+	// shared is attached outside tag_names, old was attached by a previous
+	// Sync and would be detached, new would be created and attached.
+	tagNames := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("shared"), types.StringValue("new")})
+	private := testPrivateState{}
+	diags := tags.Sync(ctx, "org", "ws", tagNames, true, false, []string{"old"}, private)
+
+	if !diags.HasError() {
+		t.Fatal("Sync returned no error for a tag attached outside tag_names")
+	}
+	if len(changes) > 0 {
+		t.Errorf("Sync changed the workspace after a conflict: %v", changes)
+	}
+	if len(private) > 0 {
+		t.Errorf("Sync wrote the private state after a conflict: %v", private)
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &WorkspaceUrlFunction{}
+
+type WorkspaceUrlFunction struct{}
+
+func NewWorkspaceUrlFunction() function.Function {
+	return &WorkspaceUrlFunction{}
+}
+
+func (f *WorkspaceUrlFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "workspace_url"
+}
+
+func (f *WorkspaceUrlFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Build the UI link of a workspace",
+		Description: "Returns the Terrakube UI URL of a workspace, `<endpoint>/organizations/<organization_id>/workspaces/<workspace_id>`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "endpoint",
+				Description: "Terrakube UI endpoint, for example `https://terrakube-ui.minikube.net`",
+			},
+			function.StringParameter{
+				Name:        "organization_id",
+				Description: "Organization ID",
+			},
+			function.StringParameter{
+				Name:        "workspace_id",
+				Description: "Workspace ID",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *WorkspaceUrlFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var endpoint, organizationId, workspaceId string
+
+	resp.Error = req.Arguments.Get(ctx, &endpoint, &organizationId, &workspaceId)
+	if resp.Error != nil {
+		return
+	}
+
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+
+	for position, value := range []string{endpoint, organizationId, workspaceId} {
+		if strings.TrimSpace(value) == "" {
+			resp.Error = function.NewArgumentFuncError(int64(position), "Value must not be empty")
+			return
+		}
+	}
+
+	resp.Error = resp.Result.Set(ctx, workspaceWebUrl(endpoint, organizationId, workspaceId))
+}
+
+// workspaceWebUrl returns the Terrakube UI route of a workspace.
+func workspaceWebUrl(endpoint string, organizationId string, workspaceId string) string {
+	return fmt.Sprintf("%s/organizations/%s/workspaces/%s", strings.TrimRight(strings.TrimSpace(endpoint), "/"), organizationId, workspaceId)
+}
+
+// uiEndpointFromApi returns the scheme and host of the API endpoint, used when
+// the provider doesn't configure ui_endpoint.
+func uiEndpointFromApi(endpoint string) string {
+	apiUrl, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil || apiUrl.Host == "" {
+		return strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	}
+
+	return fmt.Sprintf("%s://%s", apiUrl.Scheme, apiUrl.Host)
+}
+
+// workspaceWebUrlAttribute returns the computed web_url attribute of the workspace resources.
+func workspaceWebUrlAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Computed:    true,
+		Description: "Terrakube UI URL of the workspace, built from the provider `ui_endpoint`",
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
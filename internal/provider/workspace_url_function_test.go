@@ -0,0 +1,45 @@
+package provider
+
+import "testing"
+
+func TestWorkspaceWebUrl(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{endpoint: "https://terrakube.example.com", want: "https://terrakube.example.com/organizations/org/workspaces/ws"},
+		{endpoint: "https://terrakube.example.com/", want: "https://terrakube.example.com/organizations/org/workspaces/ws"},
+		{endpoint: "https://terrakube.example.com///", want: "https://terrakube.example.com/organizations/org/workspaces/ws"},
+		{endpoint: " https://example.com/terrakube/ ", want: "https://example.com/terrakube/organizations/org/workspaces/ws"},
+		{endpoint: "http://localhost:3000/ui", want: "http://localhost:3000/ui/organizations/org/workspaces/ws"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.endpoint, func(t *testing.T) {
+			if got := workspaceWebUrl(test.endpoint, "org", "ws"); got != test.want {
+				t.Errorf("workspaceWebUrl(%q) = %q, want %q", test.endpoint, got, test.want)
+			}
+		})
+	}
+}
+
+func TestUiEndpointFromApi(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{endpoint: "https://terrakube-api.example.com", want: "https://terrakube-api.example.com"},
+		{endpoint: "https://terrakube-api.example.com/", want: "https://terrakube-api.example.com"},
+		{endpoint: "https://example.com/terrakube/api", want: "https://example.com"},
+		{endpoint: "http://localhost:8080", want: "http://localhost:8080"},
+		{endpoint: "terrakube.example.com/", want: "terrakube.example.com"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.endpoint, func(t *testing.T) {
+			if got := uiEndpointFromApi(test.endpoint); got != test.want {
+				t.Errorf("uiEndpointFromApi(%q) = %q, want %q", test.endpoint, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"terraform-provider-terrakube/internal/client"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// workspaceVariableCacheTTL bounds how long a workspace variable list is reused.
+const workspaceVariableCacheTTL = time.Minute
+
+// workspaceVariableCache keeps the variable list of each workspace during a
+// Terraform operation, so refreshing many variables of the same workspace
+// sends a single list request instead of one request per variable.
+type workspaceVariableCache struct {
+	mu      sync.Mutex
+	entries map[string]*workspaceVariableCacheEntry
+}
+
+type workspaceVariableCacheEntry struct {
+	mu        sync.Mutex
+	fetchedAt time.Time
+	variables map[string]*client.WorkspaceVariableEntity
+}
+
+func newWorkspaceVariableCache() *workspaceVariableCache {
+	return &workspaceVariableCache{entries: map[string]*workspaceVariableCacheEntry{}}
+}
+
+func (c *workspaceVariableCache) entry(organizationId string, workspaceId string) *workspaceVariableCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := organizationId + "/" + workspaceId
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &workspaceVariableCacheEntry{}
+		c.entries[key] = entry
+	}
+
+	return entry
+}
+
+// Get returns the variable from the cached list of the workspace, fetching the
+// list when it is missing or expired. It returns false when the variable is not
+// in the list or the list can't be fetched, callers then request the variable
+// directly.
+func (c *workspaceVariableCache) Get(ctx context.Context, httpClient *http.Client, endpoint string, token string, organizationId string, workspaceId string, variableId string) (*client.WorkspaceVariableEntity, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	entry := c.entry(organizationId, workspaceId)
+
+	// Concurrent reads of the same workspace wait here for a single list request.
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.variables == nil || time.Since(entry.fetchedAt) > workspaceVariableCacheTTL {
+		variables, err := listWorkspaceVariables(httpClient, endpoint, token, organizationId, workspaceId)
+		if err != nil {
+			tflog.Warn(ctx, "Unable to list workspace variables, reading the variable directly", map[string]any{"error": err.Error()})
+			return nil, false
+		}
+
+		entry.variables = variables
+		entry.fetchedAt = time.Now()
+	}
+
+	variable, ok := entry.variables[variableId]
+	return variable, ok
+}
+
+// Invalidate drops the cached variables of the workspace, it must be called on every write.
+func (c *workspaceVariableCache) Invalidate(organizationId string, workspaceId string) {
+	if c == nil {
+		return
+	}
+
+	entry := c.entry(organizationId, workspaceId)
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	entry.variables = nil
+}
+
+func listWorkspaceVariables(httpClient *http.Client, endpoint string, token string, organizationId string, workspaceId string) (map[string]*client.WorkspaceVariableEntity, error) {
+	items, err := listAll(httpClient, token, apiURL(endpoint, "organization", url.PathEscape(organizationId), "workspace", url.PathEscape(workspaceId), "variable"), reflect.TypeOf(new(client.WorkspaceVariableEntity)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list workspace variables: %w", err)
+	}
+
+	variables := make(map[string]*client.WorkspaceVariableEntity, len(items))
+	for _, item := range items {
+		variable := item.(*client.WorkspaceVariableEntity)
+		variables[variable.ID] = variable
+	}
+
+	return variables, nil
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func TestWorkspaceVariableCache(t *testing.T) {
+	var mutex sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests[r.Method+" "+r.URL.Path]++
+		mutex.Unlock()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/organization/org/workspace/workspace/variable":
+			if r.URL.Query().Get("page[number]") != "1" {
+				t.Errorf("unexpected page %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"data":[
+				{"type":"variable","id":"var-1","attributes":{"key":"region","value":"eu-west-1","category":"TERRAFORM"}},
+				{"type":"variable","id":"var-2","attributes":{"key":"zone","value":"a","category":"TERRAFORM"}},
+				{"type":"variable","id":"var-3","attributes":{"key":"size","value":"small","category":"TERRAFORM"}}
+			]}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	r := NewWorkspaceVariableResource()
+	configureTestResource(t, r, server)
+
+	read := func(id string) {
+		t.Helper()
+
+		state := newTestState(t, r, map[string]string{"id": id, "organization_id": "org", "workspace_id": "workspace"})
+		resp := resource.ReadResponse{State: state}
+		r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Errorf("Read of %s returned %v", id, resp.Diagnostics)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"var-1", "var-2", "var-3", "var-1", "var-2"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			read(id)
+		}(id)
+	}
+	wg.Wait()
+
+	if got := requests["GET /api/v1/organization/org/workspace/workspace/variable"]; got != 1 {
+		t.Errorf("%d list requests for 5 reads, want 1", got)
+	}
+	if len(requests) != 1 {
+		t.Errorf("requests other than the list: %v", requests)
+	}
+
+	resp := deleteTestResource(t, r, map[string]string{"id": "var-3", "organization_id": "org", "workspace_id": "workspace"})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete returned %v", resp.Diagnostics)
+	}
+	read("var-1")
+
+	if got := requests["GET /api/v1/organization/org/workspace/workspace/variable"]; got != 2 {
+		t.Errorf("%d list requests after a write, want 2", got)
+	}
+}
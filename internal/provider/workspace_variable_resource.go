@@ -3,12 +3,14 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
 
 	"github.com/google/jsonapi"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -17,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -24,11 +27,14 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceVariableResource{}
 var _ resource.ResourceWithImportState = &WorkspaceVariableResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceVariableResource{}
 
 type WorkspaceVariableResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	variables             *workspaceVariableCache
+	defaultOrganizationId string
 }
 
 type WorkspaceVariableResourceModel struct {
@@ -63,21 +69,28 @@ func (r *WorkspaceVariableResource) Schema(ctx context.Context, req resource.Sch
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"workspace_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Terrakube workspace id",
+				Validators: []validator.String{
+					workspaceIdValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"key": schema.StringAttribute{
 				Required:    true,
 				Description: "Variable key",
+				Validators: []validator.String{
+					variableKeyValidator(),
+				},
 			},
 			"value": schema.StringAttribute{
 				Required:    true,
-				Description: "Variable value",
+				Sensitive:   true,
+				Description: "Variable value, always hidden in the plan output. `sensitive` only sets whether the API hides it.",
 			},
 			"description": schema.StringAttribute{
 				Required:    true,
@@ -114,20 +127,12 @@ func (r *WorkspaceVariableResource) Configure(ctx context.Context, req resource.
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
+	r.variables = providerData.WorkspaceVariables
 
 	tflog.Debug(ctx, "Configuring Workspace Variable resource", map[string]any{"success": true})
 }
@@ -158,7 +163,9 @@ func (r *WorkspaceVariableResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
-	workspaceVarRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
+	defer r.variables.Invalidate(plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString())
+
+	workspaceVarRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", url.PathEscape(plan.OrganizationId.ValueString()), "workspace", url.PathEscape(plan.WorkspaceId.ValueString()), "variable"), strings.NewReader(out.String()))
 	workspaceVarRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceVarRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -215,24 +222,77 @@ func (r *WorkspaceVariableResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
-	workspaceVariableRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	workspaceVariable, ok := r.variables.Get(ctx, r.client, r.endpoint, r.token, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString())
+	if !ok {
+		workspaceVariable, ok = r.getWorkspaceVariable(ctx, resp, state)
+		if !ok {
+			return
+		}
+	}
+
+	if workspaceVariable.Sensitive && state.Value.IsNull() {
+		// The variable was just imported, the value is only known once it is applied
+		resp.Diagnostics.AddWarning(
+			"Sensitive variable value not imported",
+			fmt.Sprintf("The value of sensitive variable %s can't be read from the API, it is set to the configured value on the next apply.", workspaceVariable.Key),
+		)
+	} else if workspaceVariable.Sensitive {
+		tflog.Info(ctx, "Variable value is not included in response, setting values the same as the current state value")
+		state.Value = types.StringValue(state.Value.ValueString())
+	} else {
+		tflog.Info(ctx, "Variable value is included in response...")
+		state.Value = types.StringValue(workspaceVariable.Value)
+	}
+
+	state.Key = types.StringValue(workspaceVariable.Key)
+	state.Description = types.StringValue(workspaceVariable.Description)
+	state.Category = types.StringValue(workspaceVariable.Category)
+	state.Sensitive = types.BoolValue(workspaceVariable.Sensitive)
+	state.Hcl = types.BoolValue(workspaceVariable.Hcl)
+	state.ID = types.StringValue(workspaceVariable.ID)
+
+	// Set refreshed state
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Workspace variable Resource reading", map[string]any{"success": true})
+}
+
+// getWorkspaceVariable requests a single variable, it is used when the variable
+// is not found in the cached list of the workspace.
+func (r *WorkspaceVariableResource) getWorkspaceVariable(ctx context.Context, resp *resource.ReadResponse, state WorkspaceVariableResourceModel) (*client.WorkspaceVariableEntity, bool) {
+	workspaceVariableRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "workspace", url.PathEscape(state.WorkspaceId.ValueString()), "variable", url.PathEscape(state.ID.ValueString())), nil)
 	workspaceVariableRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceVariableRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating workspace variable resource request", fmt.Sprintf("Error creating workspace variable resource request: %s", err))
-		return
+		return nil, false
 	}
 
 	workspaceVariableResponse, err := r.client.Do(workspaceVariableRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing workspace variable resource request", fmt.Sprintf("Error executing workspace variable resource request: %s", err))
-		return
+		return nil, false
 	}
 
 	bodyResponse, err := io.ReadAll(workspaceVariableResponse.Body)
 	if err != nil {
 		tflog.Error(ctx, "Error reading workspace variable resource response")
 	}
+
+	if err = client.CheckResponse(workspaceVariableResponse, bodyResponse); err != nil && !client.IsNotFound(err) && workspaceGone(ctx, r.client, r.endpoint, r.token, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString()) {
+		tflog.Debug(ctx, "Workspace of the variable was deleted, removing from state", map[string]any{"workspaceId": state.WorkspaceId.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return nil, false
+	}
+
+	if !checkReadResponse(ctx, resp, workspaceVariableResponse, bodyResponse, "workspace variable") {
+		return nil, false
+	}
+
 	workspaceVariable := &client.WorkspaceVariableEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -240,34 +300,12 @@ func (r *WorkspaceVariableResource) Read(ctx context.Context, req resource.ReadR
 
 	if err != nil {
 		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
-		return
+		return nil, false
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
-	if workspaceVariable.Sensitive {
-		tflog.Info(ctx, "Variable value is not included in response, setting values the same as the current state value")
-		state.Value = types.StringValue(state.Value.ValueString())
-	} else {
-		tflog.Info(ctx, "Variable value is included in response...")
-		state.Value = types.StringValue(workspaceVariable.Value)
-	}
-
-	state.Key = types.StringValue(workspaceVariable.Key)
-	state.Description = types.StringValue(workspaceVariable.Description)
-	state.Category = types.StringValue(workspaceVariable.Category)
-	state.Sensitive = types.BoolValue(workspaceVariable.Sensitive)
-	state.Hcl = types.BoolValue(workspaceVariable.Hcl)
-	state.ID = types.StringValue(workspaceVariable.ID)
-
-	// Set refreshed state
-	diags = resp.State.Set(ctx, &state)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	tflog.Info(ctx, "Workspace variable Resource reading", map[string]any{"success": true})
+	return workspaceVariable, true
 }
 
 func (r *WorkspaceVariableResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -298,7 +336,9 @@ func (r *WorkspaceVariableResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 
-	workspaceVariableReq, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	defer r.variables.Invalidate(state.OrganizationId.ValueString(), state.WorkspaceId.ValueString())
+
+	workspaceVariableReq, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "workspace", url.PathEscape(state.WorkspaceId.ValueString()), "variable", url.PathEscape(state.ID.ValueString())), strings.NewReader(out.String()))
 	workspaceVariableReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceVariableReq.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -319,7 +359,7 @@ func (r *WorkspaceVariableResource) Update(ctx context.Context, req resource.Upd
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	workspaceVariableReq, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	workspaceVariableReq, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", url.PathEscape(state.OrganizationId.ValueString()), "workspace", url.PathEscape(state.WorkspaceId.ValueString()), "variable", url.PathEscape(state.ID.ValueString())), nil)
 	workspaceVariableReq.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceVariableReq.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -376,27 +416,65 @@ func (r *WorkspaceVariableResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/variable/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
+	defer r.variables.Invalidate(data.OrganizationId.ValueString(), data.WorkspaceId.ValueString())
+
+	workspaceRequest, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", url.PathEscape(data.OrganizationId.ValueString()), "workspace", url.PathEscape(data.WorkspaceId.ValueString()), "variable", url.PathEscape(data.ID.ValueString())), nil)
 	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating Workspace variable resource request", fmt.Sprintf("Error creating Workspace variable resource request: %s", err))
 		return
 	}
 
-	_, err = r.client.Do(workspaceRequest)
+	workspaceVariableResponse, err := r.client.Do(workspaceRequest)
 	if err != nil {
 		resp.Diagnostics.AddError("Error executing Workspace variable resource request", fmt.Sprintf("Error executing Workspace variable resource request: %s", err))
 		return
 	}
+
+	bodyResponse, _ := io.ReadAll(workspaceVariableResponse.Body)
+	if err = checkDeleteResponse(workspaceVariableResponse, bodyResponse); err != nil {
+		if workspaceGone(ctx, r.client, r.endpoint, r.token, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString()) {
+			tflog.Debug(ctx, "Workspace of the variable was deleted, nothing to delete", map[string]any{"workspaceId": data.WorkspaceId.ValueString()})
+			return
+		}
+		resp.Diagnostics.AddError("Error executing Workspace variable resource request", fmt.Sprintf("Error executing Workspace variable resource request: %s", err))
+		return
+	}
+}
+
+func (r *WorkspaceVariableResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
 }
 
 func (r *WorkspaceVariableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !strings.Contains(req.ID, ",") && strings.Count(req.ID, "/") >= 2 {
+		nameParts := strings.SplitN(req.ID, "/", 3)
+		if nameParts[0] == "" || nameParts[1] == "" || nameParts[2] == "" {
+			resp.Diagnostics.AddError(
+				"Unexpected Import Identifier",
+				fmt.Sprintf("Expected import identifier with format: 'organization_name/workspace_name/key', Got: %q", req.ID),
+			)
+			return
+		}
+
+		organizationId, workspaceId, id, err := r.findVariableByName(nameParts[0], nameParts[1], nameParts[2])
+		if err != nil {
+			resp.Diagnostics.AddError("Error importing workspace variable", fmt.Sprintf("Unable to find workspace variable %q: %s", req.ID, err))
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), organizationId)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), workspaceId)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+		return
+	}
+
 	idParts := strings.Split(req.ID, ",")
 
 	if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
 		resp.Diagnostics.AddError(
 			"Unexpected Import Identifier",
-			fmt.Sprintf("Expected import identifier with format: 'organization_ID,workspace_ID, ID', Got: %q", req.ID),
+			fmt.Sprintf("Expected import identifier with format: 'organization_ID,workspace_ID,ID' or 'organization_name/workspace_name/key', Got: %q", req.ID),
 		)
 		return
 	}
@@ -405,3 +483,59 @@ func (r *WorkspaceVariableResource) ImportState(ctx context.Context, req resourc
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_id"), idParts[1])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[2])...)
 }
+
+// findVariableByName returns the organization, workspace and variable ids of
+// the variable key of a workspace, both found by name. A key used by both an
+// ENV and a TERRAFORM variable is an error, the variable must be imported by id.
+func (r *WorkspaceVariableResource) findVariableByName(organizationName string, workspaceName string, key string) (string, string, string, error) {
+	organizations, err := listAll(r.client, r.token, apiURL(r.endpoint, "organization")+fmt.Sprintf("?filter[organization]=%s", url.QueryEscape("name=="+helpers.RsqlQuote(organizationName))), reflect.TypeOf(new(client.OrganizationEntity)))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	organizationId := ""
+	for _, item := range organizations {
+		if organization := item.(*client.OrganizationEntity); organization.Name == organizationName {
+			organizationId = organization.ID
+		}
+	}
+	if organizationId == "" {
+		return "", "", "", fmt.Errorf("organization %s not found", organizationName)
+	}
+
+	workspaces, err := listAll(r.client, r.token, apiURL(r.endpoint, "organization", url.PathEscape(organizationId), "workspace")+fmt.Sprintf("?filter[workspace]=%s", url.QueryEscape("deleted==false;name=="+helpers.RsqlQuote(workspaceName))), reflect.TypeOf(new(client.WorkspaceEntity)))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	workspaceId := ""
+	for _, item := range workspaces {
+		if workspace := item.(*client.WorkspaceEntity); workspace.Name == workspaceName {
+			workspaceId = workspace.ID
+		}
+	}
+	if workspaceId == "" {
+		return "", "", "", fmt.Errorf("workspace %s not found in organization %s", workspaceName, organizationName)
+	}
+
+	variables, err := listAll(r.client, r.token, apiURL(r.endpoint, "organization", url.PathEscape(organizationId), "workspace", url.PathEscape(workspaceId), "variable"), reflect.TypeOf(new(client.WorkspaceVariableEntity)))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	var ids []string
+	for _, item := range variables {
+		if variable := item.(*client.WorkspaceVariableEntity); variable.Key == key {
+			ids = append(ids, variable.ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", "", "", fmt.Errorf("variable %s not found in workspace %s", key, workspaceName)
+	case 1:
+		return organizationId, workspaceId, ids[0], nil
+	default:
+		return "", "", "", fmt.Errorf("workspace %s has several variables with key %s (%s), import it with organization_ID,workspace_ID,ID", workspaceName, key, strings.Join(ids, ", "))
+	}
+}
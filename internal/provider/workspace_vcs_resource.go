@@ -3,17 +3,18 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/rand"
-	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
+	"reflect"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
+	"terraform-provider-terrakube/internal/helpers"
 
 	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -28,26 +29,50 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceVcsResource{}
 var _ resource.ResourceWithImportState = &WorkspaceVcsResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceVcsResource{}
+var _ resource.ResourceWithConfigValidators = &WorkspaceVcsResource{}
+var _ resource.ResourceWithValidateConfig = &WorkspaceVcsResource{}
+var _ resource.ResourceWithUpgradeState = &WorkspaceVcsResource{}
 
 type WorkspaceVcsResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	validateReferences    bool
+	uiEndpoint            string
+	fetchWorkspaceStatus  bool
+	defaultOrganizationId string
+	variables             *workspaceVariableCache
 }
 
 type WorkspaceVcsResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	Name           types.String `tfsdk:"name"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	Description    types.String `tfsdk:"description"`
-	IaCType        types.String `tfsdk:"iac_type"`
-	TemplateId     types.String `tfsdk:"template_id"`
-	IaCVersion     types.String `tfsdk:"iac_version"`
-	Repository     types.String `tfsdk:"repository"`
-	Branch         types.String `tfsdk:"branch"`
-	Folder         types.String `tfsdk:"folder"`
-	ExecutionMode  types.String `tfsdk:"execution_mode"`
-	VcsId          types.String `tfsdk:"vcs_id"`
+	ID                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	OrganizationId       types.String `tfsdk:"organization_id"`
+	Description          types.String `tfsdk:"description"`
+	IaCType              types.String `tfsdk:"iac_type"`
+	TemplateId           types.String `tfsdk:"template_id"`
+	IaCVersion           types.String `tfsdk:"iac_version"`
+	Repository           types.String `tfsdk:"repository"`
+	Branch               types.String `tfsdk:"branch"`
+	Folder               types.String `tfsdk:"folder"`
+	WorkingDirectory     types.String `tfsdk:"working_directory"`
+	ExecutionMode        types.String `tfsdk:"execution_mode"`
+	EffectiveMode        types.String `tfsdk:"effective_execution_mode"`
+	VcsId                types.String `tfsdk:"vcs_id"`
+	SshId                types.String `tfsdk:"ssh_id"`
+	TagNames             types.Set    `tfsdk:"tag_names"`
+	Environment          types.Map    `tfsdk:"environment"`
+	Access               types.List   `tfsdk:"access"`
+	ExclusiveAccess      types.Bool   `tfsdk:"exclusive_access"`
+	CreateMissingTags    types.Bool   `tfsdk:"create_missing_tags"`
+	ExclusiveTags        types.Bool   `tfsdk:"exclusive_tags"`
+	ExclusiveEnvironment types.Bool   `tfsdk:"exclusive_environment"`
+	DestroyProtection    types.String `tfsdk:"destroy_protection"`
+	WebUrl               types.String `tfsdk:"web_url"`
+	CurrentStateSerial   types.Int64  `tfsdk:"current_state_serial"`
+	LastJobDate          types.String `tfsdk:"last_job_date"`
+	UpdateWaitForIdle    types.Int64  `tfsdk:"update_wait_for_idle_minutes"`
 }
 
 func NewWorkspaceVcsResource() resource.Resource {
@@ -64,6 +89,7 @@ func (r *WorkspaceVcsResource) Schema(ctx context.Context, req resource.SchemaRe
 			"it will compare files contained in github repository with the cloud provider. If you only want to compare state " +
 			"with cloud provider API use CLI workspace instead.",
 
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:    true,
@@ -72,10 +98,7 @@ func (r *WorkspaceVcsResource) Schema(ctx context.Context, req resource.SchemaRe
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Workspace VCS name",
@@ -87,11 +110,20 @@ func (r *WorkspaceVcsResource) Schema(ctx context.Context, req resource.SchemaRe
 			"execution_mode": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
-				Default:     stringdefault.StaticString("remote"),
-				Description: "Workspace VCS execution mode (remote or local)",
+				Description: "Workspace VCS execution mode (remote or local), when omitted the organization execution mode is used",
 				Validators: []validator.String{
 					stringvalidator.OneOf("remote", "local"),
 				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"effective_execution_mode": schema.StringAttribute{
+				Computed:    true,
+				Description: "Execution mode applied to the workspace, either the workspace execution mode or the one inherited from the organization",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"iac_type": schema.StringAttribute{
 				Optional:    true,
@@ -104,7 +136,7 @@ func (r *WorkspaceVcsResource) Schema(ctx context.Context, req resource.SchemaRe
 			},
 			"iac_version": schema.StringAttribute{
 				Required:    true,
-				Description: "Workspace VCS VCS type",
+				Description: "Terraform or OpenTofu version used by the workspace",
 			},
 			"repository": schema.StringAttribute{
 				Required:    true,
@@ -121,17 +153,93 @@ func (r *WorkspaceVcsResource) Schema(ctx context.Context, req resource.SchemaRe
 				Description: "Workspace VCS branch",
 			},
 			"folder": schema.StringAttribute{
+				Optional:           true,
+				Computed:           true,
+				Description:        "Workspace VCS folder",
+				DeprecationMessage: renamedAttributeDeprecation("working_directory"),
+				PlanModifiers: []planmodifier.String{
+					renamedAttributePlanModifier("working_directory", "/"),
+				},
+			},
+			"working_directory": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
-				Default:     stringdefault.StaticString("/"),
-				Description: "Workspace VCS folder",
+				Description: "Directory of the repository where the IaC commands run, default is `/`",
+				PlanModifiers: []planmodifier.String{
+					renamedAttributePlanModifier("folder", "/"),
+				},
 			},
 			"vcs_id": schema.StringAttribute{
 				Optional:    true,
-				Description: "VCS connection ID for private workspaces",
+				Computed:    true,
+				Description: "VCS connection ID for private workspaces. When omitted and the repository requires a VCS connection, the only `COMPLETED` VCS connection of the organization is used.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ssh_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "SSH key ID used to clone the repository over SSH, the repository must use a `ssh://` or `git@` source. Setting it removes the VCS connection of the workspace, removing it removes the SSH key.",
 			},
 		},
 	}
+
+	for name, attribute := range workspaceTagNamesAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	resp.Schema.Attributes["destroy_protection"] = destroyProtectionAttribute()
+	for name, attribute := range workspaceEnvironmentAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	for name, attribute := range workspaceAccessAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	resp.Schema.Attributes["web_url"] = workspaceWebUrlAttribute()
+	for name, attribute := range workspaceStatusAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+	resp.Schema.Attributes["update_wait_for_idle_minutes"] = workspaceIdleWaitAttribute()
+}
+
+func (r *WorkspaceVcsResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("vcs_id"),
+			path.MatchRoot("ssh_id"),
+		),
+		resourcevalidator.Conflicting(
+			path.MatchRoot("folder"),
+			path.MatchRoot("working_directory"),
+		),
+	}
+}
+
+func (r *WorkspaceVcsResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: renamedAttributeStateUpgrader("folder", "working_directory"),
+	}
+}
+
+func (r *WorkspaceVcsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config WorkspaceVcsResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.SshId.IsNull() || config.Repository.IsUnknown() || config.Repository.IsNull() {
+		return
+	}
+
+	repository := config.Repository.ValueString()
+	if !strings.HasPrefix(repository, "ssh://") && !strings.HasPrefix(repository, "git@") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("repository"),
+			"Invalid repository for SSH",
+			fmt.Sprintf("Repository must use a ssh:// or git@ source when ssh_id is set, got: %s", repository),
+		)
+	}
 }
 
 func (r *WorkspaceVcsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -149,20 +257,15 @@ func (r *WorkspaceVcsResource) Configure(ctx context.Context, req resource.Confi
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
+	r.variables = providerData.WorkspaceVariables
+	r.uiEndpoint = providerData.UiEndpoint
+	r.fetchWorkspaceStatus = providerData.FetchWorkspaceStatus
+	r.validateReferences = providerData.ValidateReferences
 
 	tflog.Debug(ctx, "Configuring Workspace VCS resource", map[string]any{"success": true})
 }
@@ -176,6 +279,12 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
+	resp.Diagnostics.Append(r.checkTemplateReferences(plan)...)
+	resp.Diagnostics.Append(r.checkVcsConnection(plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	bodyRequest := &client.WorkspaceEntity{
 		Name:          plan.Name.ValueString(),
 		Description:   plan.Description.ValueString(),
@@ -188,66 +297,121 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 		ExecutionMode: plan.ExecutionMode.ValueString(),
 	}
 
-	if !plan.VcsId.IsNull() {
+	if !plan.SshId.IsNull() {
+		tflog.Info(ctx, fmt.Sprintf("Workspace using ssh id: %s", plan.SshId.ValueString()))
+		bodyRequest.Ssh = &client.SshEntity{ID: plan.SshId.ValueString()}
+	} else if !plan.VcsId.IsNull() && !plan.VcsId.IsUnknown() {
 		tflog.Info(ctx, fmt.Sprintf("Workspace using Vcs connection id: %s", plan.VcsId.ValueString()))
 		bodyRequest.Vcs = &client.VcsEntity{ID: plan.VcsId.ValueString()}
 	}
 
-	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
-
+	workspaceVcsResponse, bodyResponse, err := r.postWorkspace(plan.OrganizationId.ValueString(), bodyRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
+		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request: %s", err))
 		return
 	}
 
-	workspaceVcsRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace", r.endpoint, plan.OrganizationId.ValueString()), strings.NewReader(out.String()))
-	workspaceVcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	workspaceVcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating workspace vcs resource request", fmt.Sprintf("Error creating workspace vcs resource request: %s", err))
-		return
+	if bodyRequest.Vcs == nil && bodyRequest.Ssh == nil && client.IsMissingVcs(client.CheckResponse(workspaceVcsResponse, bodyResponse)) {
+		// Private repositories can't be used without a VCS connection, retry with
+		// the organization default connection like the UI does.
+		vcsId, err := r.defaultVcsId(plan.OrganizationId.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error creating workspace vcs resource", fmt.Sprintf("Workspace creation without vcs_id failed, response status: %s, response body: %s. %s", workspaceVcsResponse.Status, string(bodyResponse), err))
+			return
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("Retrying workspace creation using the organization Vcs connection id: %s", vcsId))
+		bodyRequest.Vcs = &client.VcsEntity{ID: vcsId}
+		plan.VcsId = types.StringValue(vcsId)
+
+		workspaceVcsResponse, bodyResponse, err = r.postWorkspace(plan.OrganizationId.ValueString(), bodyRequest)
+		if err != nil {
+			resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request: %s", err))
+			return
+		}
 	}
 
-	workspaceVcsResponse, err := r.client.Do(workspaceVcsRequest)
-	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request, response status: %s, response body: %s, error: %s", workspaceVcsResponse.Status, workspaceVcsResponse.Body, err))
+	if workspaceVcsResponse.StatusCode != http.StatusCreated {
+		resp.Diagnostics.AddError("Error creating workspace vcs resource", fmt.Sprintf("Error creating workspace vcs resource, response status: %s, response body: %s", workspaceVcsResponse.Status, string(bodyResponse)))
 		return
 	}
 
-	bodyResponse, err := io.ReadAll(workspaceVcsResponse.Body)
-	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading workspace vcs resource response, response status: %s, response body: %s, error: %s", workspaceVcsResponse.Status, workspaceVcsResponse.Body, err))
+	// Save the id as soon as it is known, so a parsing problem below does not
+	// lead to a duplicated workspace on the next apply.
+	workspaceId := helpers.GetIDFromResponse(workspaceVcsResponse, bodyResponse)
+	if workspaceId != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), workspaceId)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_id"), plan.OrganizationId)...)
 	}
+
 	newWorkspaceVcs := &client.WorkspaceEntity{}
 
-	fmt.Println(string(bodyResponse))
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), newWorkspaceVcs)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", workspaceVcsResponse.Status, workspaceVcsResponse.Body, err))
+		if workspaceId == "" {
+			resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", workspaceVcsResponse.Status, string(bodyResponse), err))
+			return
+		}
+
+		resp.Diagnostics.AddWarning("Unable to read created workspace", fmt.Sprintf("Workspace %s was created but the response could not be parsed, the remaining attributes will be refreshed on the next plan. Error: %s", workspaceId, err))
+		plan.ID = types.StringValue(workspaceId)
+		plan.WebUrl = types.StringValue(workspaceWebUrl(r.uiEndpoint, plan.OrganizationId.ValueString(), workspaceId))
+		nullUnknownValues(&plan)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
 	plan.ID = types.StringValue(newWorkspaceVcs.ID)
+	plan.WebUrl = types.StringValue(workspaceWebUrl(r.uiEndpoint, plan.OrganizationId.ValueString(), plan.ID.ValueString()))
+
+	status := &workspaceStatus{client: r.client, endpoint: r.endpoint, token: r.token, fetch: r.fetchWorkspaceStatus}
+	var statusDiags diag.Diagnostics
+	plan.CurrentStateSerial, plan.LastJobDate, statusDiags = status.Read(ctx, plan.OrganizationId.ValueString(), newWorkspaceVcs)
+	resp.Diagnostics.Append(statusDiags...)
+
 	plan.Name = types.StringValue(newWorkspaceVcs.Name)
-	plan.Description = types.StringValue(newWorkspaceVcs.Description)
+	plan.Description = optionalStringValue(plan.Description, newWorkspaceVcs.Description)
 	plan.Repository = types.StringValue(newWorkspaceVcs.Source)
 	plan.Branch = types.StringValue(newWorkspaceVcs.Branch)
 	plan.IaCType = types.StringValue(newWorkspaceVcs.IaCType)
 	plan.IaCVersion = types.StringValue(newWorkspaceVcs.IaCVersion)
 
 	plan.TemplateId = types.StringValue(newWorkspaceVcs.TemplateId)
-	plan.ExecutionMode = types.StringValue(newWorkspaceVcs.ExecutionMode)
+	plan.ExecutionMode = executionModeValue(newWorkspaceVcs.ExecutionMode)
+	plan.EffectiveMode, err = r.effectiveExecutionMode(plan.OrganizationId.ValueString(), plan.ExecutionMode)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organization execution mode", fmt.Sprintf("Error reading the execution mode of organization %s: %s", plan.OrganizationId.ValueString(), err))
+	}
 
-	if !plan.VcsId.IsNull() {
+	if newWorkspaceVcs.Vcs != nil {
 		plan.VcsId = types.StringValue(newWorkspaceVcs.Vcs.ID)
+	} else {
+		plan.VcsId = types.StringNull()
+	}
+
+	if newWorkspaceVcs.Ssh != nil {
+		plan.SshId = types.StringValue(newWorkspaceVcs.Ssh.ID)
+	}
+
+	plan.Folder = types.StringValue(newWorkspaceVcs.Folder)
+	plan.WorkingDirectory = types.StringValue(newWorkspaceVcs.Folder)
+
+	if !plan.TagNames.IsNull() || plan.ExclusiveTags.ValueBool() {
+		tags := &workspaceTags{client: r.client, endpoint: r.endpoint, token: r.token}
+		resp.Diagnostics.Append(tags.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.TagNames, plan.CreateMissingTags.ValueBool(), plan.ExclusiveTags.ValueBool(), nil, resp.Private)...)
 	}
 
-	if !plan.Folder.IsNull() {
-		plan.Folder = types.StringValue(newWorkspaceVcs.Folder)
+	if !plan.Environment.IsNull() || plan.ExclusiveEnvironment.ValueBool() {
+		environment := &workspaceEnvironment{client: r.client, endpoint: r.endpoint, token: r.token, variables: r.variables}
+		resp.Diagnostics.Append(environment.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.Environment, plan.ExclusiveEnvironment.ValueBool(), nil, resp.Private)...)
+	}
+
+	if !plan.Access.IsNull() || plan.ExclusiveAccess.ValueBool() {
+		access := &workspaceAccess{client: r.client, endpoint: r.endpoint, token: r.token}
+		resp.Diagnostics.Append(access.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.Access, plan.ExclusiveAccess.ValueBool(), nil, resp.Private)...)
 	}
 
 	tflog.Info(ctx, "Workspace VCS Resource Created", map[string]any{"success": true})
@@ -255,6 +419,189 @@ func (r *WorkspaceVcsResource) Create(ctx context.Context, req resource.CreateRe
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
+// executionModeValue maps an execution mode that is not set on the workspace to null.
+func executionModeValue(executionMode string) types.String {
+	if executionMode == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(executionMode)
+}
+
+// effectiveExecutionMode returns the execution mode of the workspace, or the
+// organization execution mode when execution_mode is null. The organization is
+// only read in that case.
+func (r *WorkspaceVcsResource) effectiveExecutionMode(organizationId string, executionMode types.String) (types.String, error) {
+	if !executionMode.IsNull() {
+		return executionMode, nil
+	}
+
+	organizationRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", organizationId), nil)
+	if err != nil {
+		return types.StringNull(), err
+	}
+	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+	organizationResponse, err := r.client.Do(organizationRequest)
+	if err != nil {
+		return types.StringNull(), err
+	}
+	defer organizationResponse.Body.Close()
+
+	bodyResponse, err := io.ReadAll(organizationResponse.Body)
+	if err != nil {
+		return types.StringNull(), err
+	}
+
+	if err = client.CheckResponse(organizationResponse, bodyResponse); err != nil {
+		return types.StringNull(), err
+	}
+
+	organization := &client.OrganizationEntity{}
+	if err := jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), organization); err != nil {
+		return types.StringNull(), fmt.Errorf("unable to unmarshal organization: %w", err)
+	}
+
+	return executionModeValue(organization.ExecutionMode), nil
+}
+
+// checkTemplateReferences verifies that the templates of the workspace belong to its organization.
+func (r *WorkspaceVcsResource) checkTemplateReferences(plan WorkspaceVcsResourceModel) diag.Diagnostics {
+	if !r.validateReferences {
+		return nil
+	}
+
+	return checkTemplateReferences(r.client, r.endpoint, r.token, plan.OrganizationId.ValueString(), plan.TemplateId.ValueString())
+}
+
+// checkVcsConnection verifies that the VCS connection of the workspace has
+// completed the OAuth handshake, a workspace using a pending connection can't
+// clone its repository and only fails when the first job runs.
+func (r *WorkspaceVcsResource) checkVcsConnection(plan WorkspaceVcsResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !r.validateReferences || plan.VcsId.IsNull() || plan.VcsId.ValueString() == "" {
+		return diags
+	}
+
+	vcsRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "vcs", plan.VcsId.ValueString()), nil)
+	if err != nil {
+		diags.AddError("Error creating VCS connection request", fmt.Sprintf("Error creating VCS connection request: %s", err))
+		return diags
+	}
+	vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	vcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+	vcsResponse, err := r.client.Do(vcsRequest)
+	if err != nil {
+		diags.AddError("Error executing VCS connection request", fmt.Sprintf("Error executing VCS connection request: %s", err))
+		return diags
+	}
+	defer vcsResponse.Body.Close()
+
+	bodyResponse, _ := io.ReadAll(vcsResponse.Body)
+
+	err = client.CheckResponse(vcsResponse, bodyResponse)
+	switch {
+	case client.IsNotFound(err):
+		diags.AddAttributeError(path.Root("vcs_id"), "Invalid VCS connection reference", fmt.Sprintf("VCS connection %s not found in organization %s", plan.VcsId.ValueString(), plan.OrganizationId.ValueString()))
+		return diags
+	case err != nil:
+		diags.AddError("Error validating VCS connection", fmt.Sprintf("Error validating VCS connection %s, set validate_references = false in the provider to skip this check: %s", plan.VcsId.ValueString(), err))
+		return diags
+	}
+
+	vcs := &client.VcsEntity{}
+	if err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), vcs); err != nil {
+		diags.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s", err))
+		return diags
+	}
+
+	if vcs.Status != "COMPLETED" {
+		_, _, connectUrl := GetEndpointAndApiUrl(vcs.VcsType, vcs.ClientId, vcs.Endpoint)
+		diags.AddAttributeError(
+			path.Root("vcs_id"),
+			"VCS connection not connected",
+			fmt.Sprintf("VCS connection %s is %s, the workspace would not be able to clone %s. Logon to %s to connect it, check doc here %s, or set wait_for_connection on the terrakube_vcs resource.", vcs.Name, vcs.Status, plan.Repository.ValueString(), connectUrl, helpers.GetVCSProviderDoc()),
+		)
+	}
+
+	return diags
+}
+
+// postWorkspace sends the create request for a workspace and returns the response with its body.
+func (r *WorkspaceVcsResource) postWorkspace(organizationId string, bodyRequest *client.WorkspaceEntity) (*http.Response, []byte, error) {
+	var out = new(bytes.Buffer)
+	err := jsonapi.MarshalPayload(out, bodyRequest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to marshal payload: %w", err)
+	}
+
+	workspaceVcsRequest, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", organizationId, "workspace"), strings.NewReader(out.String()))
+	if err != nil {
+		return nil, nil, err
+	}
+	workspaceVcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	workspaceVcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+	workspaceVcsResponse, err := r.client.Do(workspaceVcsRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bodyResponse, err := io.ReadAll(workspaceVcsResponse.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading workspace vcs resource response, response status: %s, error: %w", workspaceVcsResponse.Status, err)
+	}
+
+	return workspaceVcsResponse, bodyResponse, nil
+}
+
+// defaultVcsId returns the id of the only COMPLETED VCS connection of the organization.
+func (r *WorkspaceVcsResource) defaultVcsId(organizationId string) (string, error) {
+	vcsRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", organizationId, "vcs"), nil)
+	if err != nil {
+		return "", err
+	}
+	vcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	vcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
+
+	vcsResponse, err := r.client.Do(vcsRequest)
+	if err != nil {
+		return "", err
+	}
+	defer vcsResponse.Body.Close()
+
+	bodyResponse, err := io.ReadAll(vcsResponse.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if err = client.CheckResponse(vcsResponse, bodyResponse); err != nil {
+		return "", fmt.Errorf("unable to list VCS connections: %w", err)
+	}
+
+	vcsList, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), reflect.TypeOf(new(client.VcsEntity)))
+	if err != nil {
+		return "", fmt.Errorf("unable to list VCS connections, response status: %s, error: %w", vcsResponse.Status, err)
+	}
+
+	var completed []string
+	for _, item := range vcsList {
+		vcs, _ := item.(*client.VcsEntity)
+		if vcs.Status == "COMPLETED" {
+			completed = append(completed, vcs.ID)
+		}
+	}
+
+	if len(completed) != 1 {
+		return "", fmt.Errorf("found %d COMPLETED VCS connections in the organization, please set vcs_id explicitly", len(completed))
+	}
+
+	return completed[0], nil
+}
+
 func (r *WorkspaceVcsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state WorkspaceVcsResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -263,7 +610,7 @@ func (r *WorkspaceVcsResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	workspaceRequest, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	workspaceRequest, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.ID.ValueString()), nil)
 	workspaceRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	workspaceRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -273,41 +620,122 @@ func (r *WorkspaceVcsResource) Read(ctx context.Context, req resource.ReadReques
 
 	workspaceResponse, err := r.client.Do(workspaceRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace cli resource request, response status: %s, response body: %s, error: %s", workspaceResponse.Status, workspaceResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace cli resource request, %s, error: %s", formatAPIError(workspaceResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(workspaceResponse.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading workspace vcs resource response, response status: %s, response body: %s, error: %s", workspaceResponse.Status, workspaceResponse.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading workspace vcs resource response, %s, error: %s", formatAPIError(workspaceResponse, bodyResponse), err))
+	}
+
+	if !checkReadResponse(ctx, resp, workspaceResponse, bodyResponse, "workspace vcs") {
+		return
 	}
+
 	workspace := &client.WorkspaceEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspace)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", workspaceResponse.Status, workspaceResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, %s, error: %s", formatAPIError(workspaceResponse, bodyResponse), err))
+		return
+	}
+
+	if workspace.Deleted {
+		tflog.Warn(ctx, "workspace vcs resource is deleted, removing from state")
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
 	state.Name = types.StringValue(workspace.Name)
-	state.Description = types.StringValue(workspace.Description)
-	state.ExecutionMode = types.StringValue(workspace.ExecutionMode)
+	state.Description = optionalStringValue(state.Description, workspace.Description)
+	state.ExecutionMode = executionModeValue(workspace.ExecutionMode)
+	state.EffectiveMode, err = r.effectiveExecutionMode(state.OrganizationId.ValueString(), state.ExecutionMode)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organization execution mode", fmt.Sprintf("Error reading the execution mode of organization %s: %s", state.OrganizationId.ValueString(), err))
+	}
 	state.Repository = types.StringValue(workspace.Source)
 	state.Branch = types.StringValue(workspace.Branch)
 	state.IaCType = types.StringValue(workspace.IaCType)
 	state.Folder = types.StringValue(workspace.Folder)
+	state.WorkingDirectory = types.StringValue(workspace.Folder)
 	state.TemplateId = types.StringValue(workspace.TemplateId)
 	state.IaCVersion = types.StringValue(workspace.IaCVersion)
 	state.ID = types.StringValue(workspace.ID)
+	state.WebUrl = types.StringValue(workspaceWebUrl(r.uiEndpoint, state.OrganizationId.ValueString(), state.ID.ValueString()))
+
+	status := &workspaceStatus{client: r.client, endpoint: r.endpoint, token: r.token, fetch: r.fetchWorkspaceStatus}
+	var statusDiags diag.Diagnostics
+	state.CurrentStateSerial, state.LastJobDate, statusDiags = status.Read(ctx, state.OrganizationId.ValueString(), workspace)
+	resp.Diagnostics.Append(statusDiags...)
 
 	if workspace.Vcs != nil {
 		state.VcsId = types.StringValue(workspace.Vcs.ID)
 	}
 
+	if workspace.Ssh != nil {
+		state.SshId = types.StringValue(workspace.Ssh.ID)
+	} else {
+		state.SshId = types.StringNull()
+	}
+
+	if state.CreateMissingTags.IsNull() {
+		state.CreateMissingTags = types.BoolValue(false)
+	}
+	if state.ExclusiveTags.IsNull() {
+		state.ExclusiveTags = types.BoolValue(false)
+	}
+	if state.ExclusiveEnvironment.IsNull() {
+		state.ExclusiveEnvironment = types.BoolValue(false)
+	}
+	if state.ExclusiveAccess.IsNull() {
+		state.ExclusiveAccess = types.BoolValue(false)
+	}
+
+	if state.DestroyProtection.IsNull() {
+		state.DestroyProtection = types.StringValue(destroyProtectionNone)
+	}
+
+	tags := &workspaceTags{client: r.client, endpoint: r.endpoint, token: r.token}
+	managedTags, tagDiags := tags.ManagedNames(ctx, req.Private, state.TagNames)
+	resp.Diagnostics.Append(tagDiags...)
+	if !state.TagNames.IsNull() || len(managedTags) > 0 || state.ExclusiveTags.ValueBool() {
+		tagNames, tagDiags := tags.Names(ctx, state.OrganizationId.ValueString(), state.ID.ValueString(), state.ExclusiveTags.ValueBool(), managedTags)
+		resp.Diagnostics.Append(tagDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.TagNames = tagNames
+	}
+
+	environment := &workspaceEnvironment{client: r.client, endpoint: r.endpoint, token: r.token, variables: r.variables}
+	managedKeys, _, environmentDiags := workspaceEnvironmentKeys.Read(ctx, req.Private)
+	resp.Diagnostics.Append(environmentDiags...)
+	if !state.Environment.IsNull() || len(managedKeys) > 0 || state.ExclusiveEnvironment.ValueBool() {
+		environmentValues, environmentDiags := environment.Read(ctx, state.OrganizationId.ValueString(), state.ID.ValueString(), state.ExclusiveEnvironment.ValueBool(), managedKeys)
+		resp.Diagnostics.Append(environmentDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.Environment = environmentValues
+	}
+
+	access := &workspaceAccess{client: r.client, endpoint: r.endpoint, token: r.token}
+	managedTeams, _, accessDiags := workspaceAccessTeams.Read(ctx, req.Private)
+	resp.Diagnostics.Append(accessDiags...)
+	if !state.Access.IsNull() || len(managedTeams) > 0 || state.ExclusiveAccess.ValueBool() {
+		accessValues, accessDiags := access.Read(ctx, state.OrganizationId.ValueString(), state.ID.ValueString(), state.Access, state.ExclusiveAccess.ValueBool(), managedTeams)
+		resp.Diagnostics.Append(accessDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.Access = accessValues
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -328,6 +756,22 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
+	resp.Diagnostics.Append(r.checkTemplateReferences(plan)...)
+	if !plan.VcsId.Equal(state.VcsId) {
+		resp.Diagnostics.Append(r.checkVcsConnection(plan)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Running jobs keep using the previous folder and IaC version, wait for them before changing it
+	if !plan.Folder.Equal(state.Folder) || !plan.IaCVersion.Equal(state.IaCVersion) {
+		resp.Diagnostics.Append(waitForIdleWorkspace(ctx, r.client, r.endpoint, r.token, state.OrganizationId.ValueString(), state.ID.ValueString(), plan.UpdateWaitForIdle)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	bodyRequest := &client.WorkspaceEntity{
 		IaCVersion:    plan.IaCVersion.ValueString(),
 		IaCType:       plan.IaCType.ValueString(),
@@ -341,7 +785,10 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 		ID:            state.ID.ValueString(),
 	}
 
-	if !plan.VcsId.IsNull() {
+	if !plan.SshId.IsNull() {
+		tflog.Info(ctx, fmt.Sprintf("Workspace using ssh id: %s", plan.SshId.ValueString()))
+		bodyRequest.Ssh = &client.SshEntity{ID: plan.SshId.ValueString()}
+	} else if !plan.VcsId.IsNull() && !plan.VcsId.IsUnknown() {
 		tflog.Info(ctx, fmt.Sprintf("Workspace using Vcs connection id: %s", plan.VcsId.ValueString()))
 		bodyRequest.Vcs = &client.VcsEntity{ID: plan.VcsId.ValueString()}
 	}
@@ -354,7 +801,7 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
-	organizationRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	organizationRequest, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.ID.ValueString()), strings.NewReader(out.String()))
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -364,18 +811,37 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 
 	organizationResponse, err := r.client.Do(organizationRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request, %s, error: %s", formatAPIError(organizationResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(organizationResponse.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading workspace vcs resource response, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading workspace vcs resource response, %s, error: %s", formatAPIError(organizationResponse, bodyResponse), err))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	organizationRequest, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, state.OrganizationId.ValueString(), state.ID.ValueString()), nil)
+	if err = client.CheckResponse(organizationResponse, bodyResponse); err != nil {
+		resp.Diagnostics.AddError("Error updating workspace vcs resource", fmt.Sprintf("Error updating workspace vcs resource, %s", formatAPIError(organizationResponse, bodyResponse)))
+		return
+	}
+
+	// A nil relationship is omitted from the PATCH, the previous one is cleared on its own
+	if !plan.SshId.IsNull() && !state.VcsId.IsNull() {
+		if err = r.clearRelationship(state.OrganizationId.ValueString(), state.ID.ValueString(), "vcs"); err != nil {
+			resp.Diagnostics.AddError("Error updating workspace vcs resource", fmt.Sprintf("Error removing VCS connection %s from workspace %s: %s", state.VcsId.ValueString(), state.ID.ValueString(), err))
+			return
+		}
+	}
+	if plan.SshId.IsNull() && !state.SshId.IsNull() {
+		if err = r.clearRelationship(state.OrganizationId.ValueString(), state.ID.ValueString(), "ssh"); err != nil {
+			resp.Diagnostics.AddError("Error updating workspace vcs resource", fmt.Sprintf("Error removing SSH key %s from workspace %s: %s", state.SshId.ValueString(), state.ID.ValueString(), err))
+			return
+		}
+	}
+
+	organizationRequest, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.ID.ValueString()), nil)
 	organizationRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	organizationRequest.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -385,13 +851,13 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 
 	organizationResponse, err = r.client.Do(organizationRequest)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace vcs resource request", fmt.Sprintf("Error executing workspace vcs resource request, %s, error: %s", formatAPIError(organizationResponse, nil), err))
 		return
 	}
 
 	bodyResponse, err = io.ReadAll(organizationResponse.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading workspace vcs resource response body", fmt.Sprintf("Error reading workspace vcs resource response body, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err))
+		resp.Diagnostics.AddError("Error reading workspace vcs resource response body", fmt.Sprintf("Error reading workspace vcs resource response body, %s, error: %s", formatAPIError(organizationResponse, bodyResponse), err))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -400,22 +866,65 @@ func (r *WorkspaceVcsResource) Update(ctx context.Context, req resource.UpdateRe
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), workspace)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status: %s, response body: %s, error: %s", organizationResponse.Status, organizationResponse.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, %s, error: %s", formatAPIError(organizationResponse, bodyResponse), err))
 		return
 	}
 
 	plan.ID = types.StringValue(state.ID.ValueString())
+	plan.WebUrl = types.StringValue(workspaceWebUrl(r.uiEndpoint, plan.OrganizationId.ValueString(), plan.ID.ValueString()))
+
+	// The status keeps the values planned from the state, the next refresh updates them
+	if plan.CurrentStateSerial.IsUnknown() || plan.LastJobDate.IsUnknown() {
+		status := &workspaceStatus{client: r.client, endpoint: r.endpoint, token: r.token, fetch: r.fetchWorkspaceStatus}
+		var statusDiags diag.Diagnostics
+		plan.CurrentStateSerial, plan.LastJobDate, statusDiags = status.Read(ctx, plan.OrganizationId.ValueString(), workspace)
+		resp.Diagnostics.Append(statusDiags...)
+	}
+
 	plan.Name = types.StringValue(workspace.Name)
-	plan.Description = types.StringValue(workspace.Description)
+	plan.Description = optionalStringValue(plan.Description, workspace.Description)
 	plan.Repository = types.StringValue(workspace.Source)
 	plan.Branch = types.StringValue(workspace.Branch)
 	plan.IaCType = types.StringValue(workspace.IaCType)
 	plan.IaCVersion = types.StringValue(workspace.IaCVersion)
-	plan.ExecutionMode = types.StringValue(workspace.ExecutionMode)
+	plan.ExecutionMode = executionModeValue(workspace.ExecutionMode)
+	plan.EffectiveMode, err = r.effectiveExecutionMode(plan.OrganizationId.ValueString(), plan.ExecutionMode)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading organization execution mode", fmt.Sprintf("Error reading the execution mode of organization %s: %s", plan.OrganizationId.ValueString(), err))
+	}
 	plan.Folder = types.StringValue(workspace.Folder)
+	plan.WorkingDirectory = types.StringValue(workspace.Folder)
 	plan.TemplateId = types.StringValue(workspace.TemplateId)
 	if workspace.Vcs != nil {
 		plan.VcsId = types.StringValue(workspace.Vcs.ID)
+	} else {
+		plan.VcsId = types.StringNull()
+	}
+	if workspace.Ssh != nil {
+		plan.SshId = types.StringValue(workspace.Ssh.ID)
+	} else {
+		plan.SshId = types.StringNull()
+	}
+
+	tags := &workspaceTags{client: r.client, endpoint: r.endpoint, token: r.token}
+	managedTags, tagDiags := tags.ManagedNames(ctx, req.Private, state.TagNames)
+	resp.Diagnostics.Append(tagDiags...)
+	if !tagDiags.HasError() {
+		resp.Diagnostics.Append(tags.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.TagNames, plan.CreateMissingTags.ValueBool(), plan.ExclusiveTags.ValueBool(), managedTags, resp.Private)...)
+	}
+
+	environment := &workspaceEnvironment{client: r.client, endpoint: r.endpoint, token: r.token, variables: r.variables}
+	managedKeys, _, environmentDiags := workspaceEnvironmentKeys.Read(ctx, req.Private)
+	resp.Diagnostics.Append(environmentDiags...)
+	if !environmentDiags.HasError() {
+		resp.Diagnostics.Append(environment.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.Environment, plan.ExclusiveEnvironment.ValueBool(), managedKeys, resp.Private)...)
+	}
+
+	access := &workspaceAccess{client: r.client, endpoint: r.endpoint, token: r.token}
+	managedTeams, _, accessDiags := workspaceAccessTeams.Read(ctx, req.Private)
+	resp.Diagnostics.Append(accessDiags...)
+	if !accessDiags.HasError() {
+		resp.Diagnostics.Append(access.Sync(ctx, plan.OrganizationId.ValueString(), plan.ID.ValueString(), plan.Access, plan.ExclusiveAccess.ValueBool(), managedTeams, resp.Private)...)
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -431,26 +940,15 @@ func (r *WorkspaceVcsResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
-	var chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
-
-	ll := len(chars)
-	b := make([]byte, 4)
-
-	if _, err := rand.Read(b); err != nil {
-		resp.Diagnostics.AddError("Error generating random string to delete workspace", fmt.Sprintf("Error generating random string to delete workspace: %s", err))
+	deleteWorkspace, diags := checkDestroyProtection(ctx, data.DestroyProtection, data.ID.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if !deleteWorkspace {
 		return
 	}
 
-	for i := 0; i < 4; i++ {
-		b[i] = chars[int(b[i])%ll]
-	}
-
-	tflog.Info(ctx, "Send patch request to mark as deleted...")
-	tflog.Info(ctx, fmt.Sprintf("%s_DEL_%s", data.Name.ValueString(), string(b)))
-
 	bodyRequest := &client.WorkspaceEntity{
 		ID:            data.ID.ValueString(),
-		Name:          fmt.Sprintf("%s_DEL_%s", data.Name.ValueString(), string(b)), // FORCE A NAME CHANGE WITH THE SAME LOGIC THAT IN THE UI
+		Name:          data.Name.ValueString(),
 		Description:   data.Description.ValueString(),
 		Source:        data.Repository.ValueString(),
 		Branch:        data.Branch.ValueString(),
@@ -458,35 +956,62 @@ func (r *WorkspaceVcsResource) Delete(ctx context.Context, req resource.DeleteRe
 		TemplateId:    data.TemplateId.ValueString(),
 		IaCVersion:    data.IaCVersion.ValueString(),
 		ExecutionMode: data.ExecutionMode.ValueString(),
-		Deleted:       true,
 	}
 
-	var out = new(bytes.Buffer)
-	err := jsonapi.MarshalPayload(out, bodyRequest)
+	if err := softDeleteWorkspace(ctx, r.client, r.endpoint, r.token, data.OrganizationId.ValueString(), bodyRequest); err != nil {
+		resp.Diagnostics.AddError("Error deleting workspace vcs resource", fmt.Sprintf("Error deleting workspace vcs resource: %s", err))
+		return
+	}
+}
 
-	tflog.Info(ctx, "Request Body...")
-	tflog.Info(ctx, out.String())
+func (r *WorkspaceVcsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to marshal payload", fmt.Sprintf("Unable to marshal payload: %s", err))
+	if req.Plan.Raw.IsNull() {
 		return
 	}
 
-	workspaceVcsRequest, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s", r.endpoint, data.OrganizationId.ValueString(), data.ID.ValueString()), strings.NewReader(out.String()))
-	workspaceVcsRequest.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
-	workspaceVcsRequest.Header.Add("Content-Type", "application/vnd.api+json")
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating vcs resource request", fmt.Sprintf("Error creating vcs resource request: %s", err))
+	var configVcsId, planSshId, stateSshId types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("vcs_id"), &configVcsId)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("ssh_id"), &planSshId)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("ssh_id"), &stateSshId)...)
+	}
+	if resp.Diagnostics.HasError() || !configVcsId.IsNull() {
 		return
 	}
 
-	workspaceVcsResponse, err := r.client.Do(workspaceVcsRequest)
-	if err != nil || workspaceVcsResponse.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing vcs resource request", fmt.Sprintf("Error executing vcs resource request, response status: %s, response body: %s, error: %s", workspaceVcsResponse.Status, workspaceVcsResponse.Body, err))
-		return
+	// vcs_id keeps its state value otherwise, a workspace cloned over SSH has no VCS connection
+	switch {
+	case !planSshId.IsNull():
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("vcs_id"), types.StringNull())...)
+	case !stateSshId.IsNull():
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("vcs_id"), types.StringUnknown())...)
+	}
+}
+
+// clearRelationship removes the vcs or ssh relationship of the workspace.
+func (r *WorkspaceVcsResource) clearRelationship(organizationId string, workspaceId string, relationship string) error {
+	relationshipURL := apiURL(r.endpoint, "organization", organizationId, "workspace", workspaceId, "relationships", relationship)
+	request, err := http.NewRequest(http.MethodPatch, relationshipURL, strings.NewReader(`{"data":null}`))
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
+	request.Header.Add("Content-Type", "application/vnd.api+json")
+
+	response, err := r.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	bodyResponse, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
 	}
 
-	tflog.Info(ctx, "Delete response code: "+strconv.Itoa(workspaceVcsResponse.StatusCode))
+	return client.CheckResponse(response, bodyResponse)
 }
 
 func (r *WorkspaceVcsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
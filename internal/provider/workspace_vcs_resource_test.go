@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEffectiveExecutionMode(t *testing.T) {
+	tests := []struct {
+		name          string
+		executionMode types.String
+		status        int
+		want          types.String
+		wantRequest   bool
+		wantErr       bool
+	}{
+		{name: "workspace mode", executionMode: types.StringValue("remote"), want: types.StringValue("remote")},
+		{name: "inherited", executionMode: types.StringNull(), status: http.StatusOK, want: types.StringValue("local"), wantRequest: true},
+		{name: "unauthorized", executionMode: types.StringNull(), status: http.StatusUnauthorized, want: types.StringNull(), wantRequest: true, wantErr: true},
+		{name: "server error", executionMode: types.StringNull(), status: http.StatusInternalServerError, want: types.StringNull(), wantRequest: true, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requested := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requested = true
+				if r.URL.Path != "/api/v1/organization/org" {
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+				w.WriteHeader(test.status)
+				if test.status == http.StatusOK {
+					_, _ = w.Write([]byte(`{"data":{"type":"organization","id":"org","attributes":{"name":"simple","executionMode":"local"}}}`))
+				}
+			}))
+			defer server.Close()
+
+			r := &WorkspaceVcsResource{client: server.Client(), endpoint: server.URL, token: "test-token"}
+			got, err := r.effectiveExecutionMode("org", test.executionMode)
+
+			if (err != nil) != test.wantErr {
+				t.Fatalf("effectiveExecutionMode error = %v, want error %t", err, test.wantErr)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("effectiveExecutionMode = %s, want %s", got, test.want)
+			}
+			if requested != test.wantRequest {
+				t.Errorf("organization requested = %t, want %t", requested, test.wantRequest)
+			}
+		})
+	}
+}
+
+func TestWorkspaceVcsUpdateRejected(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method != http.MethodPatch {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"errors":[{"detail":"branch is not valid"}]}`))
+	}))
+	defer server.Close()
+
+	r := NewWorkspaceVcsResource()
+	configureTestResource(t, r, server)
+
+	attributes := map[string]string{"id": "workspace", "organization_id": "org", "name": "simple", "branch": "main", "execution_mode": "remote"}
+	state := newTestState(t, r, attributes)
+	attributes["branch"] = "feature/"
+	plan := newTestPlan(t, r, attributes)
+
+	resp := resource.UpdateResponse{State: state}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: plan, State: state}, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Update returned no error for a rejected PATCH")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "branch is not valid") {
+		t.Errorf("error detail %q doesn't contain the API error", detail)
+	}
+	if len(requests) != 1 {
+		t.Errorf("requests after a rejected PATCH: %v", requests)
+	}
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// workspaceWebhooksPageSize is the number of webhooks requested on each page.
+const workspaceWebhooksPageSize = 100
+
+var (
+	_ datasource.DataSource              = &WorkspaceWebhookEventsDataSource{}
+	_ datasource.DataSourceWithConfigure = &WorkspaceWebhookEventsDataSource{}
+)
+
+type WorkspaceWebhookEventsDataSourceModel struct {
+	OrganizationId types.String                            `tfsdk:"organization_id"`
+	WorkspaceId    types.String                            `tfsdk:"workspace_id"`
+	Events         []WorkspaceWebhookEventsDataSourceEvent `tfsdk:"events"`
+}
+
+type WorkspaceWebhookEventsDataSourceEvent struct {
+	WebhookId  types.String `tfsdk:"webhook_id"`
+	Event      types.String `tfsdk:"event"`
+	Branch     types.List   `tfsdk:"branch"`
+	Path       types.List   `tfsdk:"path"`
+	TemplateId types.String `tfsdk:"template_id"`
+}
+
+type WorkspaceWebhookEventsDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewWorkspaceWebhookEventsDataSource() datasource.DataSource {
+	return &WorkspaceWebhookEventsDataSource{}
+}
+
+func (d *WorkspaceWebhookEventsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Workspace Webhook Events Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	ctx = tflog.SetField(ctx, "endpoint", d.endpoint)
+	ctx = tflog.SetField(ctx, "token", d.token)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "token")
+	tflog.Info(ctx, "Workspace Webhook Events Data Source configured")
+}
+
+func (d *WorkspaceWebhookEventsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_webhook_events"
+}
+
+func (d *WorkspaceWebhookEventsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the events that trigger runs through the webhooks of a workspace, one entry for each webhook with its event, branches, paths and template.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Organization ID",
+			},
+			"workspace_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Workspace ID",
+			},
+			"events": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Webhook events of the workspace",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"webhook_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Webhook ID",
+						},
+						"event": schema.StringAttribute{
+							Computed:    true,
+							Description: "Event that triggers a run, e.g. PUSH",
+						},
+						"branch": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "Branches that trigger a run, empty when not set",
+						},
+						"path": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "File paths that trigger a run, empty when not set",
+						},
+						"template_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Template ID of the run",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *WorkspaceWebhookEventsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state WorkspaceWebhookEventsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	webhooksURL := apiURL(d.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.WorkspaceId.ValueString(), "webhook")
+
+	webhooks, err := d.getWebhooks(ctx, webhooksURL)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading workspace webhooks", err.Error())
+		return
+	}
+
+	state.Events = []WorkspaceWebhookEventsDataSourceEvent{}
+	for _, webhook := range webhooks {
+		state.Events = append(state.Events, WorkspaceWebhookEventsDataSourceEvent{
+			WebhookId:  types.StringValue(webhook.ID),
+			Event:      types.StringValue(webhook.Event),
+			Branch:     splitWebhookList(webhook.Branch),
+			Path:       splitWebhookList(webhook.Path),
+			TemplateId: types.StringValue(webhook.TemplateId),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// getWebhooks returns every webhook of the workspace, requesting one page at a time.
+func (d *WorkspaceWebhookEventsDataSource) getWebhooks(ctx context.Context, apiURL string) ([]*client.WorkspaceWebhookEntity, error) {
+	query := url.Values{}
+	query.Set("page[size]", fmt.Sprintf("%d", workspaceWebhooksPageSize))
+
+	var webhooks []*client.WorkspaceWebhookEntity
+	for page := 1; ; page++ {
+		query.Set("page[number]", fmt.Sprintf("%d", page))
+
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s?%s", apiURL, query.Encode()), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating webhook request: %w", err)
+		}
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+		request.Header.Add("Content-Type", "application/vnd.api+json")
+
+		response, err := d.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("error executing webhook request: %w", err)
+		}
+
+		bodyResponse, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading webhook response body: %w", err)
+		}
+
+		tflog.Debug(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+
+		if err = client.CheckResponse(response, bodyResponse); err != nil {
+			return nil, err
+		}
+
+		items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), reflect.TypeOf(new(client.WorkspaceWebhookEntity)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal payload: %w", err)
+		}
+
+		for _, item := range items {
+			webhooks = append(webhooks, item.(*client.WorkspaceWebhookEntity))
+		}
+
+		if len(items) < workspaceWebhooksPageSize {
+			return webhooks, nil
+		}
+	}
+}
+
+// splitWebhookList splits a comma separated list of branches or paths, an
+// empty value is an empty list.
+func splitWebhookList(value string) types.List {
+	elements := []attr.Value{}
+	for _, element := range strings.Split(value, ",") {
+		if element != "" {
+			elements = append(elements, types.StringValue(element))
+		}
+	}
+
+	return types.ListValueMust(types.StringType, elements)
+}
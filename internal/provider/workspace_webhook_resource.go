@@ -3,47 +3,59 @@ package provider
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strings"
 	"terraform-provider-terrakube/internal/client"
 
 	"github.com/google/jsonapi"
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &WorkspaceWebhookResource{}
 var _ resource.ResourceWithImportState = &WorkspaceWebhookResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceWebhookResource{}
+var _ resource.ResourceWithUpgradeState = &WorkspaceWebhookResource{}
 
 type WorkspaceWebhookResource struct {
-	client   *http.Client
-	endpoint string
-	token    string
+	client                *http.Client
+	endpoint              string
+	token                 string
+	validateReferences    bool
+	defaultOrganizationId string
 }
 
 type WorkspaceWebhookResourceModel struct {
-	ID             types.String `tfsdk:"id"`
-	OrganizationId types.String `tfsdk:"organization_id"`
-	WorkspaceId    types.String `tfsdk:"workspace_id"`
-	Path           types.List   `tfsdk:"path"`
-	Branch         types.List   `tfsdk:"branch"`
-	TemplateId     types.String `tfsdk:"template_id"`
-	RemoteHookId   types.String `tfsdk:"remote_hook_id"`
-	Event          types.String `tfsdk:"event"`
+	ID              types.String `tfsdk:"id"`
+	OrganizationId  types.String `tfsdk:"organization_id"`
+	WorkspaceId     types.String `tfsdk:"workspace_id"`
+	Path            types.List   `tfsdk:"path"`
+	Branch          types.List   `tfsdk:"branch"`
+	TemplateId      types.String `tfsdk:"template_id"`
+	RemoteHookId    types.String `tfsdk:"remote_hook_id"`
+	Event           types.String `tfsdk:"event"`
+	AllowMultiple   types.Bool   `tfsdk:"allow_multiple"`
+	AllowNoTemplate types.Bool   `tfsdk:"allow_no_template"`
 }
 
 func NewWorkspaceWebhookResource() resource.Resource {
@@ -62,6 +74,7 @@ func (r *WorkspaceWebhookResource) Schema(ctx context.Context, req resource.Sche
 	)
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Create a webhook attached to a workspace. Can be useful for automated apply/plan workflows.",
+		Version:             1,
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -71,23 +84,32 @@ func (r *WorkspaceWebhookResource) Schema(ctx context.Context, req resource.Sche
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"organization_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Terrakube organization id",
-			},
+			"organization_id": organizationIdAttribute(),
 			"workspace_id": schema.StringAttribute{
 				Required:    true,
 				Description: "Terrakube workspace id",
+				Validators: []validator.String{
+					workspaceIdValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"path": schema.ListAttribute{
 				Optional:    true,
 				Description: "The file paths in regex that trigger a run.",
 				ElementType: types.StringType,
+				Validators: []validator.List{
+					webhookRegexListValidator(),
+				},
 			},
 			"branch": schema.ListAttribute{
 				Optional:    true,
 				Description: "A list of branches that trigger a run. Support regex for more complex matching.",
 				ElementType: types.StringType,
+				Validators: []validator.List{
+					webhookRegexListValidator(),
+				},
 			},
 			"template_id": schema.StringAttribute{
 				Optional:    true,
@@ -97,6 +119,9 @@ func (r *WorkspaceWebhookResource) Schema(ctx context.Context, req resource.Sche
 				Optional:    true,
 				Computed:    true,
 				Description: "The remote hook ID.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"event": schema.StringAttribute{
 				Optional:    true,
@@ -107,6 +132,18 @@ func (r *WorkspaceWebhookResource) Schema(ctx context.Context, req resource.Sche
 					stringvalidator.OneOf("PUSH"),
 				},
 			},
+			"allow_no_template": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Allow a webhook without `template_id` on a workspace without default template, default is `false`. Such a webhook never starts a run, it is reported as a warning instead of an error.",
+			},
+			"allow_multiple": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Allow the creation of a webhook when the workspace already has one, default is `false`. GitHub keeps a single hook per URL, several webhooks on the same workspace replace each other's remote hook on every apply.",
+			},
 		},
 	}
 }
@@ -126,20 +163,12 @@ func (r *WorkspaceWebhookResource) Configure(ctx context.Context, req resource.C
 		return
 	}
 
-	if providerData.InsecureHttpClient {
-		if custom, ok := http.DefaultTransport.(*http.Transport); ok {
-			customTransport := custom.Clone()
-			customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			r.client = &http.Client{Transport: customTransport}
-		} else {
-			r.client = &http.Client{}
-		}
-	} else {
-		r.client = &http.Client{}
-	}
+	r.client = providerData.Client
 
 	r.endpoint = providerData.Endpoint
 	r.token = providerData.Token
+	r.defaultOrganizationId = providerData.OrganizationId
+	r.validateReferences = providerData.ValidateReferences
 
 	tflog.Debug(ctx, "Configuring Webhook resource", map[string]any{"success": true})
 }
@@ -153,6 +182,25 @@ func (r *WorkspaceWebhookResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
+	if r.validateReferences {
+		resp.Diagnostics.Append(checkTemplateReferences(r.client, r.endpoint, r.token, plan.OrganizationId.ValueString(), plan.TemplateId.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(r.checkWorkspaceTemplate(plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.AllowMultiple.ValueBool() {
+		resp.Diagnostics.Append(r.checkExistingWebhook(plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	var branchList, pathList []string
 	plan.Branch.ElementsAs(ctx, &branchList, true)
 	plan.Path.ElementsAs(ctx, &pathList, true)
@@ -172,7 +220,7 @@ func (r *WorkspaceWebhookResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook", r.endpoint, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString()), strings.NewReader(out.String()))
+	request, err := http.NewRequest(http.MethodPost, apiURL(r.endpoint, "organization", plan.OrganizationId.ValueString(), "workspace", plan.WorkspaceId.ValueString(), "webhook"), strings.NewReader(out.String()))
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	request.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -182,27 +230,27 @@ func (r *WorkspaceWebhookResource) Create(ctx context.Context, req resource.Crea
 
 	response, err := r.client.Do(request)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, %s, error: %s", formatAPIError(response, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(response.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading workspace webhook resource, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading workspace webhook resource, %s, error: %s", formatAPIError(response, bodyResponse), err))
 	}
 	webhook := &client.WorkspaceWebhookEntity{}
 
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), webhook)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response: %s, error: %s", formatAPIError(response, bodyResponse), err))
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
-	plan.Path, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Path, ","))
-	plan.Branch, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Branch, ","))
+	plan.Path = webhookList(webhook.Path)
+	plan.Branch = webhookList(webhook.Branch)
 	plan.TemplateId = types.StringValue(webhook.TemplateId)
 	plan.RemoteHookId = types.StringValue(webhook.RemoteHookId)
 	plan.Event = types.StringValue(webhook.Event)
@@ -221,7 +269,7 @@ func (r *WorkspaceWebhookResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	request, err := http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.WorkspaceId.ValueString(), "webhook", state.ID.ValueString()), nil)
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	request.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -231,32 +279,49 @@ func (r *WorkspaceWebhookResource) Read(ctx context.Context, req resource.ReadRe
 
 	response, err := r.client.Do(request)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, %s, error: %s", formatAPIError(response, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(response.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading workspace webhook resource response, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading workspace webhook resource response, %s, error: %s", formatAPIError(response, bodyResponse), err))
+	}
+
+	if err = client.CheckResponse(response, bodyResponse); err != nil && !client.IsNotFound(err) && workspaceGone(ctx, r.client, r.endpoint, r.token, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString()) {
+		tflog.Debug(ctx, "Workspace of the webhook was deleted, removing from state", map[string]any{"workspaceId": state.WorkspaceId.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if !checkReadResponse(ctx, resp, response, bodyResponse, "workspace webhook") {
+		return
 	}
+
 	webhook := &client.WorkspaceWebhookEntity{}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 	err = jsonapi.UnmarshalPayload(strings.NewReader(string(bodyResponse)), webhook)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error unmarshal payload response", fmt.Sprintf("Error unmarshal payload response, %s, error: %s", formatAPIError(response, bodyResponse), err))
 		return
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
 
-	state.Path, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Path, ","))
-	state.Branch, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Branch, ","))
+	state.Path = webhookList(webhook.Path)
+	state.Branch = webhookList(webhook.Branch)
 	state.TemplateId = types.StringValue(webhook.TemplateId)
 	state.RemoteHookId = types.StringValue(webhook.RemoteHookId)
 	state.Event = types.StringValue(webhook.Event)
 	state.ID = types.StringValue(webhook.ID)
+	if state.AllowMultiple.IsNull() {
+		state.AllowMultiple = types.BoolValue(false)
+	}
+	if state.AllowNoTemplate.IsNull() {
+		state.AllowNoTemplate = types.BoolValue(false)
+	}
 
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
@@ -278,6 +343,18 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
+	if r.validateReferences {
+		resp.Diagnostics.Append(checkTemplateReferences(r.client, r.endpoint, r.token, plan.OrganizationId.ValueString(), plan.TemplateId.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(r.checkWorkspaceTemplate(plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var branchList, pathList []string
 	plan.Branch.ElementsAs(ctx, &branchList, true)
 	plan.Path.ElementsAs(ctx, &pathList, true)
@@ -298,7 +375,7 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), strings.NewReader(out.String()))
+	request, err := http.NewRequest(http.MethodPatch, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.WorkspaceId.ValueString(), "webhook", state.ID.ValueString()), strings.NewReader(out.String()))
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	request.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -308,18 +385,18 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 
 	response, err := r.client.Do(request)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, %s, error: %s", formatAPIError(response, nil), err))
 		return
 	}
 
 	bodyResponse, err := io.ReadAll(response.Body)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error reading Workspace webhook resource response, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		tflog.Error(ctx, fmt.Sprintf("Error reading Workspace webhook resource response, %s, error: %s", formatAPIError(response, bodyResponse), err))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"success": string(bodyResponse)})
 
-	request, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, state.OrganizationId.ValueString(), state.WorkspaceId.ValueString(), state.ID.ValueString()), nil)
+	request, err = http.NewRequest(http.MethodGet, apiURL(r.endpoint, "organization", state.OrganizationId.ValueString(), "workspace", state.WorkspaceId.ValueString(), "webhook", state.ID.ValueString()), nil)
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	request.Header.Add("Content-Type", "application/vnd.api+json")
 	if err != nil {
@@ -329,13 +406,13 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 
 	response, err = r.client.Do(request)
 	if err != nil {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, %s, error: %s", formatAPIError(response, nil), err))
 		return
 	}
 
 	bodyResponse, err = io.ReadAll(response.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading workspace webhook resource response body", fmt.Sprintf("Error reading workspace webhook resource response body, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+		resp.Diagnostics.AddError("Error reading workspace webhook resource response body", fmt.Sprintf("Error reading workspace webhook resource response body, %s, error: %s", formatAPIError(response, bodyResponse), err))
 	}
 
 	tflog.Info(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
@@ -349,8 +426,8 @@ func (r *WorkspaceWebhookResource) Update(ctx context.Context, req resource.Upda
 	}
 
 	plan.ID = types.StringValue(state.ID.ValueString())
-	plan.Path, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Path, ","))
-	plan.Branch, _ = types.ListValueFrom(ctx, types.StringType, strings.Split(webhook.Branch, ","))
+	plan.Path = webhookList(webhook.Path)
+	plan.Branch = webhookList(webhook.Branch)
 	plan.TemplateId = types.StringValue(webhook.TemplateId)
 	plan.RemoteHookId = types.StringValue(webhook.RemoteHookId)
 	plan.Event = types.StringValue(webhook.Event)
@@ -368,7 +445,7 @@ func (r *WorkspaceWebhookResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
-	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/api/v1/organization/%s/workspace/%s/webhook/%s", r.endpoint, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString(), data.ID.ValueString()), nil)
+	request, err := http.NewRequest(http.MethodDelete, apiURL(r.endpoint, "organization", data.OrganizationId.ValueString(), "workspace", data.WorkspaceId.ValueString(), "webhook", data.ID.ValueString()), nil)
 	request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", r.token))
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating workspace webhook resource request", fmt.Sprintf("Error creating workspace webhook resource request: %s", err))
@@ -376,12 +453,137 @@ func (r *WorkspaceWebhookResource) Delete(ctx context.Context, req resource.Dele
 	}
 
 	response, err := r.client.Do(request)
-	if err != nil || response.StatusCode != http.StatusNoContent {
-		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, response status %s, response body: %s, error: %s", response.Status, response.Body, err))
+	if err != nil {
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request, error: %s", err))
+		return
+	}
+
+	bodyResponse, _ := io.ReadAll(response.Body)
+	if err = checkDeleteResponse(response, bodyResponse); err != nil {
+		if workspaceGone(ctx, r.client, r.endpoint, r.token, data.OrganizationId.ValueString(), data.WorkspaceId.ValueString()) {
+			tflog.Debug(ctx, "Workspace of the webhook was deleted, nothing to delete", map[string]any{"workspaceId": data.WorkspaceId.ValueString()})
+			return
+		}
+		resp.Diagnostics.AddError("Error executing workspace webhook resource request", fmt.Sprintf("Error executing workspace webhook resource request: %s", err))
 		return
 	}
 }
 
+func (r *WorkspaceWebhookResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: emptyWebhookListStateUpgrader("branch", "path"),
+	}
+}
+
+// webhookList splits the comma separated branches or paths of a webhook, an
+// empty value is a null list like an attribute that is not configured.
+func webhookList(value string) types.List {
+	if value == "" {
+		return types.ListNull(types.StringType)
+	}
+
+	elements := []attr.Value{}
+	for _, element := range strings.Split(value, ",") {
+		elements = append(elements, types.StringValue(element))
+	}
+
+	return types.ListValueMust(types.StringType, elements)
+}
+
+// emptyWebhookListStateUpgrader sets to null the lists holding a single empty
+// string, written by versions that split an empty value into [""].
+func emptyWebhookListStateUpgrader(names ...string) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			var state map[string]json.RawMessage
+			if err := json.Unmarshal(req.RawState.JSON, &state); err != nil {
+				resp.Diagnostics.AddError("Unable to upgrade state", fmt.Sprintf("Unable to read prior state: %s", err))
+				return
+			}
+
+			for _, name := range names {
+				var values []string
+				if json.Unmarshal(state[name], &values) == nil && len(values) == 1 && values[0] == "" {
+					state[name] = json.RawMessage("null")
+				}
+			}
+
+			upgraded, err := json.Marshal(state)
+			if err != nil {
+				resp.Diagnostics.AddError("Unable to upgrade state", fmt.Sprintf("Unable to write upgraded state: %s", err))
+				return
+			}
+
+			resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+		},
+	}
+}
+
+func (r *WorkspaceWebhookResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	setDefaultOrganization(ctx, r.defaultOrganizationId, req, resp)
+}
+
+// checkWorkspaceTemplate fails when neither the webhook nor its workspace have
+// a template, the webhook would never start a run.
+func (r *WorkspaceWebhookResource) checkWorkspaceTemplate(plan WorkspaceWebhookResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if plan.TemplateId.ValueString() != "" {
+		return diags
+	}
+
+	status, body, err := getParent(r.client, r.token, apiURL(r.endpoint, "organization", url.PathEscape(plan.OrganizationId.ValueString()), "workspace", url.PathEscape(plan.WorkspaceId.ValueString())))
+	workspace := &client.WorkspaceEntity{}
+	if err == nil && status == http.StatusOK {
+		err = jsonapi.UnmarshalPayload(strings.NewReader(string(body)), workspace)
+	} else if err == nil {
+		err = fmt.Errorf("response status %d", status)
+	}
+	if err != nil {
+		diags.AddWarning("Unable to check workspace template", fmt.Sprintf("Unable to read the default template of workspace %s: %s", plan.WorkspaceId.ValueString(), err))
+		return diags
+	}
+
+	if workspace.TemplateId != "" {
+		return diags
+	}
+
+	summary := "Webhook without template"
+	detail := fmt.Sprintf("The webhook has no template_id and workspace %s has no default template, pushes will never start a run. Set template_id, or set allow_no_template = true if the template is set on the workspace later.", plan.WorkspaceId.ValueString())
+	if plan.AllowNoTemplate.ValueBool() {
+		diags.AddAttributeWarning(path.Root("template_id"), summary, detail)
+	} else {
+		diags.AddAttributeError(path.Root("template_id"), summary, detail)
+	}
+
+	return diags
+}
+
+// checkExistingWebhook fails when the workspace already has a webhook, usually
+// the same webhook defined twice. The error quotes the import command so the
+// existing webhook can be managed instead.
+func (r *WorkspaceWebhookResource) checkExistingWebhook(plan WorkspaceWebhookResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	webhooks, err := listAll(r.client, r.token, apiURL(r.endpoint, "organization", url.PathEscape(plan.OrganizationId.ValueString()), "workspace", url.PathEscape(plan.WorkspaceId.ValueString()), "webhook"), reflect.TypeOf(new(client.WorkspaceWebhookEntity)))
+	if err != nil {
+		diags.AddError("Error reading workspace webhooks", fmt.Sprintf("Unable to check the existing webhooks of workspace %s: %s", plan.WorkspaceId.ValueString(), err))
+		return diags
+	}
+
+	if len(webhooks) == 0 {
+		return diags
+	}
+
+	existing := webhooks[0].(*client.WorkspaceWebhookEntity)
+	diags.AddAttributeError(
+		path.Root("workspace_id"),
+		"Workspace already has a webhook",
+		fmt.Sprintf("Workspace %s already has webhook %s. Import it with terraform import <address> %s,%s,%s, or set allow_multiple = true to create another webhook.", plan.WorkspaceId.ValueString(), existing.ID, plan.OrganizationId.ValueString(), plan.WorkspaceId.ValueString(), existing.ID),
+	)
+	return diags
+}
+
 func (r *WorkspaceWebhookResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	idParts := strings.Split(req.ID, ",")
 
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestWorkspaceWebhookWorkspaceIdRequiresReplace(t *testing.T) {
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	NewWorkspaceWebhookResource().Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	attribute, ok := schemaResp.Schema.Attributes["workspace_id"].(schema.StringAttribute)
+	if !ok {
+		t.Fatalf("workspace_id is a %T, want schema.StringAttribute", schemaResp.Schema.Attributes["workspace_id"])
+	}
+
+	tests := []struct {
+		name        string
+		plan        string
+		wantReplace bool
+	}{
+		{name: "unchanged", plan: "ws-1"},
+		{name: "moved to another workspace", plan: "ws-2", wantReplace: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := newTestState(t, NewWorkspaceWebhookResource(), map[string]string{"id": "webhook", "workspace_id": "ws-1"})
+			req := planmodifier.StringRequest{
+				Path:       path.Root("workspace_id"),
+				StateValue: types.StringValue("ws-1"),
+				PlanValue:  types.StringValue(test.plan),
+				State:      state,
+				Plan:       newTestPlan(t, NewWorkspaceWebhookResource(), map[string]string{"id": "webhook", "workspace_id": test.plan}),
+			}
+			resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+			for _, modifier := range attribute.PlanModifiers {
+				modifier.PlanModifyString(ctx, req, resp)
+			}
+
+			if resp.RequiresReplace != test.wantReplace {
+				t.Errorf("RequiresReplace = %t, want %t", resp.RequiresReplace, test.wantReplace)
+			}
+		})
+	}
+}
+
+func TestCheckExistingWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{name: "no webhook", status: http.StatusOK, body: `{"data":[]}`},
+		{name: "existing webhook", status: http.StatusOK, body: `{"data":[{"type":"webhook","id":"webhook-1","attributes":{"event":"PUSH"}}]}`, wantErr: "terraform import <address> org,ws,webhook-1"},
+		{name: "list refused", status: http.StatusForbidden, body: `{"errors":[{"detail":"forbidden"}]}`, wantErr: "Unable to check the existing webhooks"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/organization/org/workspace/ws/webhook" {
+					t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			r := &WorkspaceWebhookResource{client: server.Client(), endpoint: server.URL, token: "test-token"}
+			diags := r.checkExistingWebhook(WorkspaceWebhookResourceModel{OrganizationId: types.StringValue("org"), WorkspaceId: types.StringValue("ws")})
+
+			if test.wantErr == "" {
+				if diags.HasError() {
+					t.Errorf("checkExistingWebhook returned %v", diags)
+				}
+				return
+			}
+			if !diags.HasError() {
+				t.Fatal("checkExistingWebhook returned no error")
+			}
+			if detail := diags.Errors()[0].Detail(); !strings.Contains(detail, test.wantErr) {
+				t.Errorf("error detail %q doesn't contain %q", detail, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckWorkspaceTemplate(t *testing.T) {
+	tests := []struct {
+		name            string
+		templateId      string
+		allowNoTemplate bool
+		workspace       string
+		status          int
+		wantErr         bool
+		wantWarning     bool
+		wantRequest     bool
+	}{
+		{name: "webhook template", templateId: "template"},
+		{name: "workspace template", status: http.StatusOK, workspace: `{"data":{"type":"workspace","id":"ws","attributes":{"name":"simple","defaultTemplate":"template"}}}`, wantRequest: true},
+		{name: "no template", status: http.StatusOK, workspace: `{"data":{"type":"workspace","id":"ws","attributes":{"name":"simple"}}}`, wantErr: true, wantRequest: true},
+		{name: "no template allowed", allowNoTemplate: true, status: http.StatusOK, workspace: `{"data":{"type":"workspace","id":"ws","attributes":{"name":"simple"}}}`, wantWarning: true, wantRequest: true},
+		{name: "workspace unreadable", status: http.StatusForbidden, wantWarning: true, wantRequest: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requested := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requested = true
+				if r.URL.Path != "/api/v1/organization/org/workspace/ws" {
+					t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.workspace))
+			}))
+			defer server.Close()
+
+			r := &WorkspaceWebhookResource{client: server.Client(), endpoint: server.URL, token: "test-token"}
+			diags := r.checkWorkspaceTemplate(WorkspaceWebhookResourceModel{
+				OrganizationId:  types.StringValue("org"),
+				WorkspaceId:     types.StringValue("ws"),
+				TemplateId:      types.StringValue(test.templateId),
+				AllowNoTemplate: types.BoolValue(test.allowNoTemplate),
+			})
+
+			if diags.HasError() != test.wantErr {
+				t.Errorf("checkWorkspaceTemplate errors = %v, want error %t", diags.Errors(), test.wantErr)
+			}
+			if (diags.WarningsCount() > 0) != test.wantWarning {
+				t.Errorf("checkWorkspaceTemplate warnings = %v, want warning %t", diags.Warnings(), test.wantWarning)
+			}
+			if requested != test.wantRequest {
+				t.Errorf("workspace requested = %t, want %t", requested, test.wantRequest)
+			}
+		})
+	}
+}
+
+func TestEmptyWebhookListStateUpgrader(t *testing.T) {
+	raw := `{"id":"webhook","branch":[""],"path":[""],"event":"PUSH"}`
+	normal := `{"id":"webhook","branch":["main","feature/.*"],"path":[""],"event":"PUSH"}`
+
+	tests := []struct {
+		name       string
+		raw        string
+		wantBranch string
+		wantPath   string
+	}{
+		{name: "empty lists", raw: raw, wantBranch: `null`, wantPath: `null`},
+		{name: "configured list", raw: normal, wantBranch: `["main","feature/.*"]`, wantPath: `null`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := resource.UpgradeStateResponse{}
+			emptyWebhookListStateUpgrader("branch", "path").StateUpgrader(context.Background(), resource.UpgradeStateRequest{
+				RawState: &tfprotov6.RawState{JSON: []byte(test.raw)},
+			}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("StateUpgrader returned %v", resp.Diagnostics)
+			}
+			if resp.DynamicValue == nil {
+				t.Fatal("StateUpgrader returned no state")
+			}
+
+			var state map[string]json.RawMessage
+			if err := json.Unmarshal(resp.DynamicValue.JSON, &state); err != nil {
+				t.Fatalf("upgraded state %s: %s", resp.DynamicValue.JSON, err)
+			}
+			if got := string(state["branch"]); got != test.wantBranch {
+				t.Errorf("branch = %s, want %s", got, test.wantBranch)
+			}
+			if got := string(state["path"]); got != test.wantPath {
+				t.Errorf("path = %s, want %s", got, test.wantPath)
+			}
+			if got := string(state["event"]); got != `"PUSH"` {
+				t.Errorf("event = %s, want it unchanged", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"terraform-provider-terrakube/internal/client"
+
+	"github.com/google/jsonapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// workspacesPageSize is the number of workspaces requested on each page.
+const workspacesPageSize = 100
+
+var (
+	_ datasource.DataSource              = &WorkspacesDataSource{}
+	_ datasource.DataSourceWithConfigure = &WorkspacesDataSource{}
+)
+
+type WorkspacesDataSourceModel struct {
+	OrganizationId types.String                         `tfsdk:"organization_id"`
+	Tag            types.String                         `tfsdk:"tag"`
+	NamePrefix     types.String                         `tfsdk:"name_prefix"`
+	Workspaces     []WorkspacesDataSourceWorkspaceModel `tfsdk:"workspaces"`
+	ByName         types.Map                            `tfsdk:"by_name"`
+}
+
+type WorkspacesDataSourceWorkspaceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Branch        types.String `tfsdk:"branch"`
+	IaCType       types.String `tfsdk:"iac_type"`
+	ExecutionMode types.String `tfsdk:"execution_mode"`
+}
+
+var workspacesDataSourceWorkspaceAttrTypes = map[string]attr.Type{
+	"id":             types.StringType,
+	"name":           types.StringType,
+	"branch":         types.StringType,
+	"iac_type":       types.StringType,
+	"execution_mode": types.StringType,
+}
+
+type WorkspacesDataSource struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+func NewWorkspacesDataSource() datasource.DataSource {
+	return &WorkspacesDataSource{}
+}
+
+func (d *WorkspacesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, res *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*TerrakubeConnectionData)
+	if !ok {
+		res.Diagnostics.AddError(
+			"Unexpected Workspaces Data Source Configure Type",
+			fmt.Sprintf("Expected *TerrakubeConnectionData got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = providerData.Client
+	d.endpoint = providerData.Endpoint
+	d.token = providerData.Token
+
+	ctx = tflog.SetField(ctx, "endpoint", d.endpoint)
+	ctx = tflog.SetField(ctx, "token", d.token)
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "token")
+	tflog.Info(ctx, "Workspaces Data Source configured")
+}
+
+func (d *WorkspacesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspaces"
+}
+
+func workspacesDataSourceWorkspaceAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Computed:    true,
+			Description: "Workspace ID",
+		},
+		"name": schema.StringAttribute{
+			Computed:    true,
+			Description: "Workspace name",
+		},
+		"branch": schema.StringAttribute{
+			Computed:    true,
+			Description: "Workspace branch",
+		},
+		"iac_type": schema.StringAttribute{
+			Computed:    true,
+			Description: "Workspace IaC type",
+		},
+		"execution_mode": schema.StringAttribute{
+			Computed:    true,
+			Description: "Workspace execution mode",
+		},
+	}
+}
+
+func (d *WorkspacesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "List the workspaces of an organization, optionally filtered by tag and name prefix. Deleted workspaces are not returned.",
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				Required:    true,
+				Description: "Organization ID",
+			},
+			"tag": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return workspaces with the organization tag with this name",
+			},
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return workspaces whose name starts with this prefix",
+			},
+			"workspaces": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Workspaces matching the filters, sorted by name",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: workspacesDataSourceWorkspaceAttributes(),
+				},
+			},
+			"by_name": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Workspaces matching the filters keyed by name, useful with `for_each`",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: workspacesDataSourceWorkspaceAttributes(),
+				},
+			},
+		},
+	}
+}
+
+func (d *WorkspacesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state WorkspacesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organizationId := state.OrganizationId.ValueString()
+	tags := &workspaceTags{client: d.client, endpoint: d.endpoint, token: d.token}
+
+	filters := []string{"deleted==false"}
+	if !state.NamePrefix.IsNull() {
+		filters = append(filters, fmt.Sprintf("name=='%s*'", state.NamePrefix.ValueString()))
+	}
+
+	tagId := ""
+	if !state.Tag.IsNull() {
+		organizationTags, err := tags.organizationTags(organizationId)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading organization tags", err.Error())
+			return
+		}
+
+		for _, tag := range organizationTags {
+			if tag.Name == state.Tag.ValueString() {
+				tagId = tag.ID
+			}
+		}
+
+		if tagId == "" {
+			resp.Diagnostics.AddError("Tag not found", fmt.Sprintf("Tag %s doesn't exist in organization %s", state.Tag.ValueString(), organizationId))
+			return
+		}
+	}
+
+	workspacesURL := apiURL(d.endpoint, "organization", organizationId, "workspace")
+
+	workspaces, err := d.getWorkspaces(ctx, workspacesURL, filters)
+	if err != nil {
+		tflog.Warn(ctx, "Filtered workspace request failed, filtering workspaces on the client", map[string]any{"error": err.Error()})
+		workspaces, err = d.getWorkspaces(ctx, workspacesURL, nil)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading workspaces", err.Error())
+		return
+	}
+
+	state.Workspaces = []WorkspacesDataSourceWorkspaceModel{}
+	for _, workspace := range workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		if !state.NamePrefix.IsNull() && !strings.HasPrefix(workspace.Name, state.NamePrefix.ValueString()) {
+			continue
+		}
+		if tagId != "" {
+			attachedTags, err := tags.attachedTags(organizationId, workspace.ID)
+			if err != nil {
+				resp.Diagnostics.AddError("Error reading workspace tags", err.Error())
+				return
+			}
+
+			tagged := false
+			for _, attachedTag := range attachedTags {
+				if attachedTag.TagID == tagId {
+					tagged = true
+				}
+			}
+			if !tagged {
+				continue
+			}
+		}
+
+		state.Workspaces = append(state.Workspaces, WorkspacesDataSourceWorkspaceModel{
+			ID:            types.StringValue(workspace.ID),
+			Name:          types.StringValue(workspace.Name),
+			Branch:        types.StringValue(workspace.Branch),
+			IaCType:       types.StringValue(workspace.IaCType),
+			ExecutionMode: types.StringValue(workspace.ExecutionMode),
+		})
+	}
+
+	sort.SliceStable(state.Workspaces, func(i, j int) bool {
+		return state.Workspaces[i].Name.ValueString() < state.Workspaces[j].Name.ValueString()
+	})
+
+	byName := map[string]attr.Value{}
+	for _, workspace := range state.Workspaces {
+		value, diags := types.ObjectValueFrom(ctx, workspacesDataSourceWorkspaceAttrTypes, workspace)
+		resp.Diagnostics.Append(diags...)
+		byName[workspace.Name.ValueString()] = value
+	}
+
+	byNameValue, diags := types.MapValue(types.ObjectType{AttrTypes: workspacesDataSourceWorkspaceAttrTypes}, byName)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.ByName = byNameValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// getWorkspaces returns every workspace matching the filters, requesting one page at a time.
+func (d *WorkspacesDataSource) getWorkspaces(ctx context.Context, apiURL string, filters []string) ([]*client.WorkspaceEntity, error) {
+	query := url.Values{}
+	query.Set("page[size]", fmt.Sprintf("%d", workspacesPageSize))
+	query.Set("sort", "name")
+	if len(filters) > 0 {
+		query.Set("filter[workspace]", strings.Join(filters, ";"))
+	}
+
+	var workspaces []*client.WorkspaceEntity
+	for page := 1; ; page++ {
+		query.Set("page[number]", fmt.Sprintf("%d", page))
+
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s?%s", apiURL, query.Encode()), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating workspace request: %w", err)
+		}
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", d.token))
+		request.Header.Add("Content-Type", "application/vnd.api+json")
+
+		response, err := d.client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("error executing workspace request: %w", err)
+		}
+
+		bodyResponse, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading workspace response body: %w", err)
+		}
+
+		tflog.Debug(ctx, "Body Response", map[string]any{"bodyResponse": string(bodyResponse)})
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("response status: %s, response body: %s", response.Status, string(bodyResponse))
+		}
+
+		items, err := jsonapi.UnmarshalManyPayload(strings.NewReader(string(bodyResponse)), reflect.TypeOf(new(client.WorkspaceEntity)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to unmarshal payload, error: %w, response body: %s", err, string(bodyResponse))
+		}
+
+		for _, item := range items {
+			workspaces = append(workspaces, item.(*client.WorkspaceEntity))
+		}
+
+		if len(items) < workspacesPageSize {
+			return workspaces, nil
+		}
+	}
+}